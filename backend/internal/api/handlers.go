@@ -2,15 +2,26 @@ package api
 
 import (
 	"github.com/aprilboiz/flight-management/internal/api/handlers"
+	"github.com/aprilboiz/flight-management/internal/middleware"
 	"go.uber.org/zap"
 )
 
 type Handlers struct {
-	ParameterHandler handlers.ParameterHandler
-	AirportHandler   handlers.AirportHandler
-	PlaneHandler     handlers.PlaneHandler
-	FlightHandler    handlers.FlightHandler
-	TicketHandler    handlers.TicketHandler
-	UserHandler      handlers.UserHandler
-	Logger           *zap.Logger
+	ParameterHandler   handlers.ParameterHandler
+	AirportHandler     handlers.AirportHandler
+	PlaneHandler       handlers.PlaneHandler
+	TicketClassHandler handlers.TicketClassHandler
+	MaintenanceHandler handlers.MaintenanceHandler
+	RouteHandler       handlers.RouteHandler
+	FlightHandler      handlers.FlightHandler
+	TicketHandler      handlers.TicketHandler
+	UserHandler        handlers.UserHandler
+	RoleHandler        handlers.RoleHandler
+	AuditHandler       handlers.AuditHandler
+	PassengerHandler   handlers.PassengerHandler
+	LoyaltyHandler     handlers.LoyaltyHandler
+	PrivacyHandler     handlers.PrivacyHandler
+	TokenValidator     middleware.TokenValidator
+	PermissionResolver middleware.PermissionResolver
+	Logger             *zap.Logger
 }
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/service"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -35,6 +37,33 @@ func (h *airportHandler) GetAllAirports(c *gin.Context) {
 	c.JSON(http.StatusOK, airports)
 }
 
+// SearchAirports godoc
+//	@Summary		Search airports
+//	@Description	Type-ahead search on airport code, name, city and country, ignoring case and diacritics.
+//	@Description	Code and prefix matches are ranked first, then matches inside words, then matches with a typo.
+//	@Tags			airports
+//	@Produce		json
+//	@Param			q		query		string	true	"Search text"
+//	@Param			limit	query		int		false	"Maximum number of airports, 10 by default"
+//	@Success		200		{array}		dto.AirportResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/airports/search [get]
+func (h *airportHandler) SearchAirports(c *gin.Context) {
+	var query dto.AirportSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid airport search", err))
+		return
+	}
+
+	airports, err := h.airportService.SearchAirports(&query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, airports)
+}
+
 // GetAirportByCode godoc
 //	@Summary		Get airport by code
 //	@Description	Retrieve an airport by its unique code
@@ -55,3 +84,121 @@ func (h *airportHandler) GetAirportByCode(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, airport)
 }
+
+// CreateAirport godoc
+//	@Summary		Create an airport
+//	@Description	Add an airport. IATA and ICAO codes must be unique, and the number of airports is limited by the parameters.
+//	@Tags			airports
+//	@Accept			json
+//	@Produce		json
+//	@Param			airport	body		dto.AirportRequest	true	"Airport information"
+//	@Success		201		{object}	dto.AirportResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/airports [post]
+func (h *airportHandler) CreateAirport(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	airportRequest, ok := validatedModel.(*dto.AirportRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to AirportRequest", nil))
+		return
+	}
+
+	airport, err := h.airportService.CreateAirport(airportRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, airport)
+}
+
+// UpdateAirport godoc
+//	@Summary		Update an airport
+//	@Description	Change an airport's codes, names or coordinates
+//	@Tags			airports
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string				true	"Airport Code"
+//	@Param			airport	body		dto.AirportRequest	true	"Airport information"
+//	@Success		200		{object}	dto.AirportResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/airports/{code} [put]
+func (h *airportHandler) UpdateAirport(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	airportRequest, ok := validatedModel.(*dto.AirportRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to AirportRequest", nil))
+		return
+	}
+
+	airport, err := h.airportService.UpdateAirport(c.Param("code"), airportRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, airport)
+}
+
+// DeleteAirport godoc
+//	@Summary		Delete an airport
+//	@Description	Remove an airport. Refused while flights depart from, arrive at or stop at it.
+//	@Tags			airports
+//	@Param			code	path	string	true	"Airport Code"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/airports/{code} [delete]
+func (h *airportHandler) DeleteAirport(c *gin.Context) {
+	if err := h.airportService.DeleteAirport(c.Param("code")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ImportAirports godoc
+//	@Summary		Import airports from a CSV file
+//	@Description	Create and update airports from an OurAirports airports.csv file in the server's import directory, matched on IATA code.
+//	@Description	The file is validated first and saved in one transaction; invalid rows are listed in the error details and nothing is imported.
+//	@Tags			airports
+//	@Accept			json
+//	@Produce		json
+//	@Param			import	body		dto.AirportImportRequest	true	"Import options"
+//	@Success		200		{object}	dto.AirportImportResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/airports/import [post]
+func (h *airportHandler) ImportAirports(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	importRequest, ok := validatedModel.(*dto.AirportImportRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to AirportImportRequest", nil))
+		return
+	}
+
+	result, err := h.airportService.ImportAirports(importRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewAuditHandler(auditService service.AuditService) AuditHandler {
+	if auditService == nil {
+		panic("Missing required audit service")
+	}
+	return &auditHandler{auditService: auditService}
+}
+
+type auditHandler struct {
+	auditService service.AuditService
+}
+
+// GetAuditLog godoc
+//
+//	@Summary		Search the audit log
+//	@Description	Retrieve the latest security events, newest first, such as login lockouts and account unlocks
+//	@Tags			audit
+//	@Produce		json
+//	@Param			action	query		string	false	"Action"	Enums(LOGIN_LOCKOUT, ACCOUNT_UNLOCKED, PASSWORD_CHANGED, PASSWORD_RESET)
+//	@Param			subject	query		string	false	"Subject, e.g. user:alice or ip:203.0.113.7"
+//	@Param			limit	query		int		false	"Maximum number of entries, 100 by default"
+//	@Success		200		{array}		dto.AuditLogResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/audit-logs [get]
+func (h *auditHandler) GetAuditLog(c *gin.Context) {
+	var query dto.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid audit log query", err))
+		return
+	}
+
+	entries, err := h.auditService.SearchAuditLog(&query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
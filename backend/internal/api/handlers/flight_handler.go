@@ -79,10 +79,98 @@ func (f *flightHandler) GetFlightByCode(c *gin.Context) {
 	c.JSON(http.StatusOK, flight)
 }
 
+// GetFlightSeats godoc
+//
+//	@Summary		Find seats on a flight
+//	@Description	List the flight's seats with their attributes, fare and booking status, filtered e.g. to available window seats
+//	@Tags			flights
+//	@Accept			json
+//	@Produce		json
+//	@Param			code			path		string	true	"Flight Code"
+//	@Param			class			query		string	false	"Ticket class name"
+//	@Param			position		query		string	false	"Seat position"	Enums(WINDOW, AISLE, MIDDLE)
+//	@Param			exit_row		query		bool	false	"Exit row seats only (true) or excluded (false)"
+//	@Param			extra_legroom	query		bool	false	"Extra legroom seats only (true) or excluded (false)"
+//	@Param			bassinet		query		bool	false	"Bassinet seats only (true) or excluded (false)"
+//	@Param			available		query		bool	false	"Hide booked and blocked seats"
+//	@Param			max_surcharge	query		number	false	"Highest acceptable seat surcharge"
+//	@Success		200				{array}		dto.SeatInfo
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/api/flights/{code}/seats [get]
+func (f *flightHandler) GetFlightSeats(c *gin.Context) {
+	var filter dto.SeatFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid seat filter", err))
+		return
+	}
+
+	seats, err := f.flightService.GetFlightSeats(c.Param("code"), &filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, seats)
+}
+
+// GetFlightParameters godoc
+//
+//	@Summary		Effective parameters of a flight
+//	@Description	Show the parameters in force on a flight and whether each comes from the global parameters, the flight's route or the flight itself
+//	@Tags			flights
+//	@Produce		json
+//	@Param			code	path		string	true	"Flight Code"
+//	@Success		200		{object}	dto.EffectiveParametersResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/flights/{code}/parameters [get]
+func (f *flightHandler) GetFlightParameters(c *gin.Context) {
+	params, err := f.flightService.GetEffectiveParameters(c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, params)
+}
+
+// SuggestFlightDuration godoc
+//
+//	@Summary		Suggest a flight duration
+//	@Description	Estimate the time in the air for a route from the great-circle distance of its legs and the aircraft type's cruise speed.
+//	@Description	Legs beyond the type's range are listed in warnings.
+//	@Tags			flights
+//	@Produce		json
+//	@Param			departure_airport	query		string	true	"Departure airport code"
+//	@Param			arrival_airport		query		string	true	"Arrival airport code"
+//	@Param			stops				query		string	false	"Comma-separated intermediate stop airport codes, in order"
+//	@Param			plane_code			query		string	false	"Plane code, required without aircraft_type"
+//	@Param			aircraft_type		query		string	false	"Aircraft type code"
+//	@Success		200					{object}	dto.DurationSuggestionResponse
+//	@Failure		400					{object}	dto.ErrorResponse
+//	@Failure		404					{object}	dto.ErrorResponse
+//	@Failure		500					{object}	dto.ErrorResponse
+//	@Router			/api/flights/duration-suggestion [get]
+func (f *flightHandler) SuggestFlightDuration(c *gin.Context) {
+	var query dto.DurationSuggestionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid route", err))
+		return
+	}
+
+	suggestion, err := f.flightService.SuggestDuration(&query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, suggestion)
+}
+
 // CreateFlight godoc
 //
 //	@Summary		Create a new flight
-//	@Description	Create a new flight with the provided information
+//	@Description	Create a new flight with the provided information. The airports must form an approved route, whose
+//	@Description	default duration and base price are used when the request leaves them out.
 //	@Tags			flights
 //	@Accept			json
 //	@Produce		json
@@ -113,7 +201,7 @@ func (f *flightHandler) CreateFlight(c *gin.Context) {
 // UpdateFlight godoc
 //
 //	@Summary		Update a flight
-//	@Description	Update an existing flight with the provided information
+//	@Description	Update an existing flight with the provided information. The airports must form an approved route.
 //	@Tags			flights
 //	@Accept			json
 //	@Produce		json
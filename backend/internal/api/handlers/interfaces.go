@@ -8,6 +8,9 @@ type FlightHandler interface {
 	GetAllFlightsInList(c *gin.Context)
 	GetAllFlights(c *gin.Context)
 	GetFlightByCode(c *gin.Context)
+	GetFlightSeats(c *gin.Context)
+	GetFlightParameters(c *gin.Context)
+	SuggestFlightDuration(c *gin.Context)
 	CreateFlight(c *gin.Context)
 	UpdateFlight(c *gin.Context)
 	DeleteFlightByCode(c *gin.Context)
@@ -19,29 +22,128 @@ type FlightHandler interface {
 type PlaneHandler interface {
 	GetAllPlanes(c *gin.Context)
 	GetPlaneByCode(c *gin.Context)
+	CreatePlane(c *gin.Context)
+	UpdatePlane(c *gin.Context)
+	BuildSeatMap(c *gin.Context)
+	RetirePlane(c *gin.Context)
+	GetAllAircraftTypes(c *gin.Context)
+	GetAircraftType(c *gin.Context)
+	CreateAircraftType(c *gin.Context)
+	UpdateAircraftType(c *gin.Context)
+	DeleteAircraftType(c *gin.Context)
 }
 
 type AirportHandler interface {
 	GetAllAirports(c *gin.Context)
 	GetAirportByCode(c *gin.Context)
+	SearchAirports(c *gin.Context)
+	CreateAirport(c *gin.Context)
+	UpdateAirport(c *gin.Context)
+	DeleteAirport(c *gin.Context)
+	ImportAirports(c *gin.Context)
 }
 
 type ParameterHandler interface {
 	GetAllParameters(c *gin.Context)
 	UpdateParameters(c *gin.Context)
+	AnalyzeParameters(c *gin.Context)
+	GetParameterVersions(c *gin.Context)
+	GetParameterVersion(c *gin.Context)
+	RollbackParameters(c *gin.Context)
+	CancelParameterVersion(c *gin.Context)
+}
+
+type MaintenanceHandler interface {
+	GetMaintenanceWindows(c *gin.Context)
+	CreateMaintenanceWindow(c *gin.Context)
+	DeleteMaintenanceWindow(c *gin.Context)
+	GetAffectedFlights(c *gin.Context)
+	GetFleetAvailability(c *gin.Context)
+}
+
+type TicketClassHandler interface {
+	GetAllTicketClasses(c *gin.Context)
+	GetTicketClass(c *gin.Context)
+	CreateTicketClass(c *gin.Context)
+	UpdateTicketClass(c *gin.Context)
+	DeleteTicketClass(c *gin.Context)
+}
+
+type RouteHandler interface {
+	GetAllRoutes(c *gin.Context)
+	GetRoute(c *gin.Context)
+	GetRouteNetwork(c *gin.Context)
+	CreateRoute(c *gin.Context)
+	UpdateRoute(c *gin.Context)
+	DeleteRoute(c *gin.Context)
 }
 
 type TicketHandler interface {
 	GetAllTickets(c *gin.Context)
 	GetTicketByID(c *gin.Context)
 	CreateTicket(c *gin.Context)
+	CreateGroupBooking(c *gin.Context)
 	UpdateTicketStatus(c *gin.Context)
 	DeleteTicket(c *gin.Context)
 	GetTicketStatuses(c *gin.Context)
+	RotatePIIEncryption(c *gin.Context)
 	GetBookingTypes(c *gin.Context)
 }
 
+type PassengerHandler interface {
+	SearchPassengers(c *gin.Context)
+	GetPassengerByID(c *gin.Context)
+	GetPassengerHistory(c *gin.Context)
+	CreatePassenger(c *gin.Context)
+	UpdatePassenger(c *gin.Context)
+	AddPassengerDocument(c *gin.Context)
+}
+
+type LoyaltyHandler interface {
+	EnrollMember(c *gin.Context)
+	GetAccount(c *gin.Context)
+	GetTransactions(c *gin.Context)
+}
+
+type PrivacyHandler interface {
+	ExportPassengerData(c *gin.Context)
+	CreateErasureRequest(c *gin.Context)
+	GetErasureRequests(c *gin.Context)
+}
+
+type AuditHandler interface {
+	GetAuditLog(c *gin.Context)
+}
+
+type RoleHandler interface {
+	GetAllPermissions(c *gin.Context)
+	GetAllRoles(c *gin.Context)
+	GetRole(c *gin.Context)
+	CreateRole(c *gin.Context)
+	UpdateRole(c *gin.Context)
+	DeleteRole(c *gin.Context)
+}
+
 type UserHandler interface {
 	Register(c *gin.Context)
 	Login(c *gin.Context)
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	RequestPasswordReset(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	GetJWKS(c *gin.Context)
+	GetMe(c *gin.Context)
+	GetUsers(c *gin.Context)
+	GetUser(c *gin.Context)
+	CreateUser(c *gin.Context)
+	ChangeUserRole(c *gin.Context)
+	DisableUser(c *gin.Context)
+	EnableUser(c *gin.Context)
+	UnlockUser(c *gin.Context)
+	DeleteUser(c *gin.Context)
+	CreateInvitation(c *gin.Context)
+	GetInvitations(c *gin.Context)
+	RevokeInvitation(c *gin.Context)
 }
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewLoyaltyHandler(loyaltyService service.LoyaltyService) LoyaltyHandler {
+	if loyaltyService == nil {
+		panic("Missing required loyalty service")
+	}
+	return &loyaltyHandler{loyaltyService: loyaltyService}
+}
+
+type loyaltyHandler struct {
+	loyaltyService service.LoyaltyService
+}
+
+// EnrollMember godoc
+//
+//	@Summary		Enroll a passenger in the loyalty program
+//	@Description	Create a frequent-flyer account with a new member number for a passenger
+//	@Tags			loyalty
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.LoyaltyEnrollRequest	true	"Passenger to enroll"
+//	@Success		201		{object}	dto.LoyaltyAccountResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/loyalty/accounts [post]
+func (h *loyaltyHandler) EnrollMember(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	enrollRequest, ok := validatedModel.(*dto.LoyaltyEnrollRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to LoyaltyEnrollRequest", nil))
+		return
+	}
+
+	account, err := h.loyaltyService.Enroll(enrollRequest.PassengerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, account)
+}
+
+// GetAccount godoc
+//
+//	@Summary		Get loyalty account
+//	@Description	Retrieve a member's balance, tier and progress towards the next tier
+//	@Tags			loyalty
+//	@Accept			json
+//	@Produce		json
+//	@Param			number	path		string	true	"Member number"
+//	@Success		200		{object}	dto.LoyaltyAccountResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/loyalty/accounts/{number} [get]
+func (h *loyaltyHandler) GetAccount(c *gin.Context) {
+	account, err := h.loyaltyService.GetAccount(c.Param("number"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// GetTransactions godoc
+//
+//	@Summary		Get loyalty ledger
+//	@Description	Retrieve every points ledger entry of a member, newest first
+//	@Tags			loyalty
+//	@Accept			json
+//	@Produce		json
+//	@Param			number	path		string	true	"Member number"
+//	@Success		200		{array}		dto.LoyaltyTransactionResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/loyalty/accounts/{number}/transactions [get]
+func (h *loyaltyHandler) GetTransactions(c *gin.Context) {
+	transactions, err := h.loyaltyService.GetTransactions(c.Param("number"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, transactions)
+}
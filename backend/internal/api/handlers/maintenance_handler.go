@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewMaintenanceHandler(maintenanceService service.MaintenanceService) MaintenanceHandler {
+	if maintenanceService == nil {
+		panic("Missing required maintenance service")
+	}
+	return &maintenanceHandler{maintenanceService: maintenanceService}
+}
+
+type maintenanceHandler struct {
+	maintenanceService service.MaintenanceService
+}
+
+// GetMaintenanceWindows godoc
+//
+//	@Summary		List a plane's maintenance windows
+//	@Description	Retrieve the periods in which the plane is out of service, earliest first
+//	@Tags			maintenance
+//	@Produce		json
+//	@Param			code	path		string	true	"Plane Code"
+//	@Success		200		{array}		dto.MaintenanceWindowResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/planes/{code}/maintenance [get]
+func (h *maintenanceHandler) GetMaintenanceWindows(c *gin.Context) {
+	windows, err := h.maintenanceService.GetWindows(c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, windows)
+}
+
+// CreateMaintenanceWindow godoc
+//
+//	@Summary		Schedule maintenance for a plane
+//	@Description	Take the plane out of service between two local date times. New flights overlapping the window are refused.
+//	@Description	Flights already scheduled inside the window are returned in affected_flights so they can be moved.
+//	@Tags			maintenance
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string							true	"Plane Code"
+//	@Param			window	body		dto.MaintenanceWindowRequest	true	"Maintenance window"
+//	@Success		201		{object}	dto.MaintenanceWindowResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/planes/{code}/maintenance [post]
+func (h *maintenanceHandler) CreateMaintenanceWindow(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	windowRequest, ok := validatedModel.(*dto.MaintenanceWindowRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to MaintenanceWindowRequest", nil))
+		return
+	}
+
+	window, err := h.maintenanceService.CreateWindow(c.Param("code"), windowRequest, c.GetString("username"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, window)
+}
+
+// DeleteMaintenanceWindow godoc
+//
+//	@Summary		Cancel a maintenance window
+//	@Description	Return the plane to service for the window's period
+//	@Tags			maintenance
+//	@Param			code	path	string	true	"Plane Code"
+//	@Param			id		path	int		true	"Maintenance window ID"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/planes/{code}/maintenance/{id} [delete]
+func (h *maintenanceHandler) DeleteMaintenanceWindow(c *gin.Context) {
+	id, ok := parseMaintenanceWindowID(c)
+	if !ok {
+		return
+	}
+	if err := h.maintenanceService.DeleteWindow(c.Param("code"), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetAffectedFlights godoc
+//
+//	@Summary		Flights affected by a maintenance window
+//	@Description	List the plane's flights that are in the air during the window, with their active ticket counts
+//	@Tags			maintenance
+//	@Produce		json
+//	@Param			code	path		string	true	"Plane Code"
+//	@Param			id		path		int		true	"Maintenance window ID"
+//	@Success		200		{array}		dto.AffectedFlightResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/planes/{code}/maintenance/{id}/affected-flights [get]
+func (h *maintenanceHandler) GetAffectedFlights(c *gin.Context) {
+	id, ok := parseMaintenanceWindowID(c)
+	if !ok {
+		return
+	}
+	flights, err := h.maintenanceService.GetAffectedFlights(c.Param("code"), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, flights)
+}
+
+// GetFleetAvailability godoc
+//
+//	@Summary		Fleet availability calendar
+//	@Description	Show, day by day, whether each plane is available, flying, in maintenance or retired
+//	@Tags			maintenance
+//	@Produce		json
+//	@Param			from	query		string	false	"First day (YYYY-MM-DD), defaults to today"
+//	@Param			to		query		string	false	"Last day (YYYY-MM-DD), defaults to 30 days from the first"
+//	@Success		200		{object}	dto.FleetAvailabilityResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/fleet/availability [get]
+func (h *maintenanceHandler) GetFleetAvailability(c *gin.Context) {
+	var query dto.FleetAvailabilityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid calendar range", err))
+		return
+	}
+
+	calendar, err := h.maintenanceService.GetFleetAvailability(&query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, calendar)
+}
+
+func parseMaintenanceWindowID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid maintenance window ID format", err))
+		return 0, false
+	}
+	return uint(id), true
+}
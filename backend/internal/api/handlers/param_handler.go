@@ -2,9 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/aprilboiz/flight-management/internal/dto"
 	e "github.com/aprilboiz/flight-management/internal/exceptions"
-	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -20,11 +21,11 @@ type paramHandler struct {
 // GetAllParameters godoc
 //
 //	@Summary		Get all the parameters
-//	@Description	Retrieve a list of all parameters
+//	@Description	Retrieve the parameter version in force now
 //	@Tags			parameters
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{array}		models.Parameter
+//	@Success		200	{object}	dto.ParameterVersionResponse
 //	@Failure		500	{object}	dto.ErrorResponse
 //	@Router			/api/params [get]
 func (p *paramHandler) GetAllParameters(c *gin.Context) {
@@ -39,29 +40,179 @@ func (p *paramHandler) GetAllParameters(c *gin.Context) {
 // UpdateParameters godoc
 //
 //	@Summary		Update parameters
-//	@Description	Update all parameters
+//	@Description	Add a parameter version with the author, the changes from the version it replaces and an optional comment.
+//	@Description	The version takes effect now, or at effective_from when given. Parameters left out keep their values.
+//	@Description	A change that existing flights or routes break is refused with the impact, unless force is set.
 //	@Tags			parameters
 //	@Accept			json
 //	@Produce		json
-//	@Param			param	body		models.Parameter	true	"Parameter information"
-//	@Success		200		{array}		models.Parameter
+//	@Param			param	body		dto.ParameterRequest	true	"Parameter information"
+//	@Param			force	query		bool					false	"Apply the change even if existing data breaks it"
+//	@Success		200		{object}	dto.ParameterVersionResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
 //	@Failure		500		{object}	dto.ErrorResponse
 //	@Router			/api/params [put]
 func (p *paramHandler) UpdateParameters(c *gin.Context) {
+	var query dto.ParameterChangeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid parameter change query", err))
+		return
+	}
 	validatedModel, exists := c.Get("validatedModel")
 	if !exists {
 		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
 		return
 	}
-	paramRequest, ok := validatedModel.(*models.Parameter)
+	paramRequest, ok := validatedModel.(*dto.ParameterRequest)
 	if !ok {
 		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to ParameterRequest", nil))
 		return
 	}
-	paramResponse, err := p.paramService.UpdateParams(paramRequest)
+	paramResponse, err := p.paramService.UpdateParams(paramRequest, c.GetString("username"), query.Force)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, paramResponse)
 }
+
+// AnalyzeParameters godoc
+//
+//	@Summary		Analyze a parameter change
+//	@Description	List the existing flights, tickets and routes a parameter change would break, without applying it
+//	@Tags			parameters
+//	@Accept			json
+//	@Produce		json
+//	@Param			param	body		dto.ParameterRequest	true	"Parameter information"
+//	@Success		200		{object}	dto.ParameterImpactResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/params/impact [post]
+func (p *paramHandler) AnalyzeParameters(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	paramRequest, ok := validatedModel.(*dto.ParameterRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to ParameterRequest", nil))
+		return
+	}
+	impact, err := p.paramService.AnalyzeParams(paramRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, impact)
+}
+
+// GetParameterVersions godoc
+//
+//	@Summary		Parameter history
+//	@Description	List every parameter version, newest first, with its author, changes and status
+//	@Tags			parameters
+//	@Produce		json
+//	@Success		200	{array}		dto.ParameterVersionResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/params/versions [get]
+func (p *paramHandler) GetParameterVersions(c *gin.Context) {
+	versions, err := p.paramService.GetVersions()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetParameterVersion godoc
+//
+//	@Summary		Get a parameter version
+//	@Description	Retrieve a parameter version by its number
+//	@Tags			parameters
+//	@Produce		json
+//	@Param			version	path		int	true	"Version number"
+//	@Success		200		{object}	dto.ParameterVersionResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/params/versions/{version} [get]
+func (p *paramHandler) GetParameterVersion(c *gin.Context) {
+	version, ok := parseParameterVersion(c)
+	if !ok {
+		return
+	}
+	params, err := p.paramService.GetVersion(version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, params)
+}
+
+// RollbackParameters godoc
+//
+//	@Summary		Roll back the parameters
+//	@Description	Restore the values of an earlier version by adding a version that takes effect now.
+//	@Description	A rollback that existing flights or routes break is refused with the impact, unless force is set.
+//	@Tags			parameters
+//	@Produce		json
+//	@Param			version	path		int		true	"Version number to restore"
+//	@Param			force	query		bool	false	"Apply the rollback even if existing data breaks it"
+//	@Success		200		{object}	dto.ParameterVersionResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/params/versions/{version}/rollback [post]
+func (p *paramHandler) RollbackParameters(c *gin.Context) {
+	version, ok := parseParameterVersion(c)
+	if !ok {
+		return
+	}
+	var query dto.ParameterChangeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid parameter change query", err))
+		return
+	}
+	params, err := p.paramService.RollbackParams(version, c.GetString("username"), query.Force)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, params)
+}
+
+// CancelParameterVersion godoc
+//
+//	@Summary		Cancel a scheduled parameter version
+//	@Description	Remove a version that has not taken effect yet
+//	@Tags			parameters
+//	@Param			version	path	int	true	"Version number"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/params/versions/{version} [delete]
+func (p *paramHandler) CancelParameterVersion(c *gin.Context) {
+	version, ok := parseParameterVersion(c)
+	if !ok {
+		return
+	}
+	if err := p.paramService.CancelScheduledParams(version); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func parseParameterVersion(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid parameter version format", err))
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewPassengerHandler(passengerService service.PassengerService) PassengerHandler {
+	if passengerService == nil {
+		panic("Missing required passenger service")
+	}
+	return &passengerHandler{passengerService: passengerService}
+}
+
+type passengerHandler struct {
+	passengerService service.PassengerService
+}
+
+// SearchPassengers godoc
+//
+//	@Summary		Search passengers
+//	@Description	Find saved passenger profiles by part of the full name, or by exact email, phone number or document number, ignoring case. Partial contact details and document numbers do not match, since these are stored encrypted.
+//	@Tags			passengers
+//	@Accept			json
+//	@Produce		json
+//	@Param			q	query		string	true	"Search term (at least 2 characters)"
+//	@Success		200	{array}		dto.PassengerResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/passengers/search [get]
+func (h *passengerHandler) SearchPassengers(c *gin.Context) {
+	passengers, err := h.passengerService.Search(c.Query("q"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !canViewPII(c) {
+		for _, passenger := range passengers {
+			passenger.MaskPII()
+		}
+	}
+	c.JSON(http.StatusOK, passengers)
+}
+
+// GetPassengerByID godoc
+//
+//	@Summary		Get passenger by ID
+//	@Description	Retrieve a passenger profile with its travel documents
+//	@Tags			passengers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Passenger ID"
+//	@Success		200	{object}	dto.PassengerResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/passengers/{id} [get]
+func (h *passengerHandler) GetPassengerByID(c *gin.Context) {
+	id, ok := parsePassengerID(c)
+	if !ok {
+		return
+	}
+
+	passenger, err := h.passengerService.GetByID(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !canViewPII(c) {
+		passenger.MaskPII()
+	}
+	c.JSON(http.StatusOK, passenger)
+}
+
+// GetPassengerHistory godoc
+//
+//	@Summary		Get passenger booking history
+//	@Description	Retrieve all tickets issued to a passenger, most recent departure first
+//	@Tags			passengers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Passenger ID"
+//	@Success		200	{object}	dto.PassengerHistoryResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/passengers/{id}/tickets [get]
+func (h *passengerHandler) GetPassengerHistory(c *gin.Context) {
+	id, ok := parsePassengerID(c)
+	if !ok {
+		return
+	}
+
+	history, err := h.passengerService.GetBookingHistory(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !canViewPII(c) {
+		history.MaskPII()
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// CreatePassenger godoc
+//
+//	@Summary		Create a passenger
+//	@Description	Create a passenger profile with its travel documents
+//	@Tags			passengers
+//	@Accept			json
+//	@Produce		json
+//	@Param			passenger	body		dto.PassengerRequest	true	"Passenger information"
+//	@Success		201			{object}	dto.PassengerResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/api/passengers [post]
+func (h *passengerHandler) CreatePassenger(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	passengerRequest, ok := validatedModel.(*dto.PassengerRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to PassengerRequest", nil))
+		return
+	}
+
+	passenger, err := h.passengerService.Create(passengerRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !canViewPII(c) {
+		passenger.MaskPII()
+	}
+	c.JSON(http.StatusCreated, passenger)
+}
+
+// UpdatePassenger godoc
+//
+//	@Summary		Update a passenger
+//	@Description	Update a passenger profile and add or update its travel documents
+//	@Tags			passengers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int						true	"Passenger ID"
+//	@Param			passenger	body		dto.PassengerRequest	true	"Passenger information"
+//	@Success		200			{object}	dto.PassengerResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/api/passengers/{id} [put]
+func (h *passengerHandler) UpdatePassenger(c *gin.Context) {
+	id, ok := parsePassengerID(c)
+	if !ok {
+		return
+	}
+
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	passengerRequest, ok := validatedModel.(*dto.PassengerRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to PassengerRequest", nil))
+		return
+	}
+
+	passenger, err := h.passengerService.Update(id, passengerRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !canViewPII(c) {
+		passenger.MaskPII()
+	}
+	c.JSON(http.StatusOK, passenger)
+}
+
+// AddPassengerDocument godoc
+//
+//	@Summary		Add a travel document
+//	@Description	Add a travel document to a passenger, or update it if the passenger already holds it
+//	@Tags			passengers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int						true	"Passenger ID"
+//	@Param			document	body		dto.TravelDocumentDTO	true	"Travel document"
+//	@Success		200			{object}	dto.PassengerResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/api/passengers/{id}/documents [post]
+func (h *passengerHandler) AddPassengerDocument(c *gin.Context) {
+	id, ok := parsePassengerID(c)
+	if !ok {
+		return
+	}
+
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	documentRequest, ok := validatedModel.(*dto.TravelDocumentDTO)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to TravelDocumentDTO", nil))
+		return
+	}
+
+	passenger, err := h.passengerService.AddDocument(id, documentRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !canViewPII(c) {
+		passenger.MaskPII()
+	}
+	c.JSON(http.StatusOK, passenger)
+}
+
+func parsePassengerID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid passenger ID format", err))
+		return 0, false
+	}
+	return uint(id), true
+}
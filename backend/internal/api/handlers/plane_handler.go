@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/service"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -52,3 +54,253 @@ func (h *planeHandler) GetPlaneByCode(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, plane)
 }
+
+// CreatePlane godoc
+//
+//	@Summary		Create a plane
+//	@Description	Add a plane to the fleet, optionally generating its seats from a layout
+//	@Tags			planes
+//	@Accept			json
+//	@Produce		json
+//	@Param			plane	body		dto.PlaneRequest	true	"Plane information"
+//	@Success		201		{object}	dto.PlaneResponseDetails
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/planes [post]
+func (h *planeHandler) CreatePlane(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	planeRequest, ok := validatedModel.(*dto.PlaneRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to PlaneRequest", nil))
+		return
+	}
+
+	plane, err := h.planeService.CreatePlane(planeRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, plane)
+}
+
+// UpdatePlane godoc
+//
+//	@Summary		Update a plane
+//	@Description	Change a plane's code or name, regenerating its seats when a layout is given
+//	@Tags			planes
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string				true	"Plane Code"
+//	@Param			plane	body		dto.PlaneRequest	true	"Plane information"
+//	@Success		200		{object}	dto.PlaneResponseDetails
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/planes/{code} [put]
+func (h *planeHandler) UpdatePlane(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	planeRequest, ok := validatedModel.(*dto.PlaneRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to PlaneRequest", nil))
+		return
+	}
+
+	plane, err := h.planeService.UpdatePlane(c.Param("code"), planeRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, plane)
+}
+
+// BuildSeatMap godoc
+//
+//	@Summary		Build a plane's seat map
+//	@Description	Generate the plane's seats from rows, column letters, aisles, cabin classes and blocked seats.
+//	@Description	Refused when a seat holding an active ticket would be removed or change class.
+//	@Tags			planes
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string				true	"Plane Code"
+//	@Param			layout	body		dto.SeatLayoutDTO	true	"Seat layout"
+//	@Success		200		{object}	dto.PlaneResponseDetails
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/planes/{code}/seat-map [put]
+func (h *planeHandler) BuildSeatMap(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	layout, ok := validatedModel.(*dto.SeatLayoutDTO)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to SeatLayoutDTO", nil))
+		return
+	}
+
+	plane, err := h.planeService.BuildSeatMap(c.Param("code"), layout)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, plane)
+}
+
+// RetirePlane godoc
+//
+//	@Summary		Retire a plane
+//	@Description	Take a plane out of service so it can no longer be scheduled. Refused while the plane is scheduled on upcoming flights, which are listed in the conflict.
+//	@Tags			planes
+//	@Produce		json
+//	@Param			code	path		string	true	"Plane Code"
+//	@Success		200		{object}	dto.PlaneResponseDetails
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/planes/{code}/retire [post]
+func (h *planeHandler) RetirePlane(c *gin.Context) {
+	plane, err := h.planeService.RetirePlane(c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, plane)
+}
+
+// GetAllAircraftTypes godoc
+//
+//	@Summary		List aircraft types
+//	@Description	Retrieve every aircraft type with its performance data and seat template
+//	@Tags			aircraft-types
+//	@Produce		json
+//	@Success		200	{array}		dto.AircraftTypeResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/aircraft-types [get]
+func (h *planeHandler) GetAllAircraftTypes(c *gin.Context) {
+	aircraftTypes, err := h.planeService.GetAllAircraftTypes()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, aircraftTypes)
+}
+
+// GetAircraftType godoc
+//
+//	@Summary		Get an aircraft type
+//	@Description	Retrieve an aircraft type by its code
+//	@Tags			aircraft-types
+//	@Produce		json
+//	@Param			code	path		string	true	"Aircraft type code"
+//	@Success		200		{object}	dto.AircraftTypeResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/aircraft-types/{code} [get]
+func (h *planeHandler) GetAircraftType(c *gin.Context) {
+	aircraftType, err := h.planeService.GetAircraftType(c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, aircraftType)
+}
+
+// CreateAircraftType godoc
+//
+//	@Summary		Create an aircraft type
+//	@Description	Add an aircraft type. Planes created with the type and no layout of their own get the seats of its template.
+//	@Tags			aircraft-types
+//	@Accept			json
+//	@Produce		json
+//	@Param			aircraft_type	body		dto.AircraftTypeRequest	true	"Aircraft type"
+//	@Success		201				{object}	dto.AircraftTypeResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		409				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/api/aircraft-types [post]
+func (h *planeHandler) CreateAircraftType(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	aircraftTypeRequest, ok := validatedModel.(*dto.AircraftTypeRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to AircraftTypeRequest", nil))
+		return
+	}
+
+	aircraftType, err := h.planeService.CreateAircraftType(aircraftTypeRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, aircraftType)
+}
+
+// UpdateAircraftType godoc
+//
+//	@Summary		Update an aircraft type
+//	@Description	Change an aircraft type's data. Planes already built keep their seats when the template changes.
+//	@Tags			aircraft-types
+//	@Accept			json
+//	@Produce		json
+//	@Param			code			path		string					true	"Aircraft type code"
+//	@Param			aircraft_type	body		dto.AircraftTypeRequest	true	"Aircraft type"
+//	@Success		200				{object}	dto.AircraftTypeResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		409				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/api/aircraft-types/{code} [put]
+func (h *planeHandler) UpdateAircraftType(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	aircraftTypeRequest, ok := validatedModel.(*dto.AircraftTypeRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to AircraftTypeRequest", nil))
+		return
+	}
+
+	aircraftType, err := h.planeService.UpdateAircraftType(c.Param("code"), aircraftTypeRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, aircraftType)
+}
+
+// DeleteAircraftType godoc
+//
+//	@Summary		Delete an aircraft type
+//	@Description	Remove an aircraft type. Refused while planes still use it or routes allow it.
+//	@Tags			aircraft-types
+//	@Param			code	path	string	true	"Aircraft type code"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/aircraft-types/{code} [delete]
+func (h *planeHandler) DeleteAircraftType(c *gin.Context) {
+	if err := h.planeService.DeleteAircraftType(c.Param("code")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
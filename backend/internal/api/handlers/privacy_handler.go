@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewPrivacyHandler(privacyService service.PrivacyService) PrivacyHandler {
+	if privacyService == nil {
+		panic("Missing required privacy service")
+	}
+	return &privacyHandler{privacyService: privacyService}
+}
+
+type privacyHandler struct {
+	privacyService service.PrivacyService
+}
+
+// ExportPassengerData godoc
+//
+//	@Summary		Export passenger data
+//	@Description	Download everything held about a passenger, matched on email or ID card, as a JSON archive
+//	@Tags			privacy
+//	@Produce		json
+//	@Param			email	query		string	false	"Passenger email"
+//	@Param			id_card	query		string	false	"Passenger ID card number"
+//	@Success		200		{object}	dto.PassengerDataExport
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/privacy/export [get]
+func (h *privacyHandler) ExportPassengerData(c *gin.Context) {
+	// The archive is unmasked, so it is limited to roles that may view PII
+	if !canViewPII(c) {
+		_ = c.Error(e.NewAppError(e.FORBIDDEN, "Exporting passenger data requires permission to view PII", nil))
+		return
+	}
+
+	export, err := h.privacyService.ExportPassengerData(c.Query("email"), c.Query("id_card"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	filename := fmt.Sprintf("passenger-data-%s.json", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.IndentedJSON(http.StatusOK, export)
+}
+
+// CreateErasureRequest godoc
+//
+//	@Summary		Request erasure of passenger data
+//	@Description	Anonymize a passenger's profiles immediately and their tickets once past the hold period.
+//	@Description	Prices and statuses are kept so revenue reports stay intact.
+//	@Tags			privacy
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.ErasureRequestRequest	true	"Data subject"
+//	@Success		201		{object}	dto.ErasureRequestResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/privacy/erasure-requests [post]
+func (h *privacyHandler) CreateErasureRequest(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	erasureRequest, ok := validatedModel.(*dto.ErasureRequestRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to ErasureRequestRequest", nil))
+		return
+	}
+
+	response, err := h.privacyService.RequestErasure(erasureRequest, c.GetString("username"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetErasureRequests godoc
+//
+//	@Summary		List erasure requests
+//	@Description	Retrieve all erasure requests with the number of tickets still waiting for anonymization
+//	@Tags			privacy
+//	@Produce		json
+//	@Success		200	{array}		dto.ErasureRequestResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/privacy/erasure-requests [get]
+func (h *privacyHandler) GetErasureRequests(c *gin.Context) {
+	requests, err := h.privacyService.GetErasureRequests()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, requests)
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewRoleHandler(roleService service.RoleService) RoleHandler {
+	if roleService == nil {
+		panic("Missing required role service")
+	}
+	return &roleHandler{roleService: roleService}
+}
+
+type roleHandler struct {
+	roleService service.RoleService
+}
+
+// GetAllPermissions godoc
+//
+//	@Summary		List permissions
+//	@Description	Retrieve every permission that roles can grant
+//	@Tags			roles
+//	@Produce		json
+//	@Success		200	{array}	string
+//	@Router			/api/permissions [get]
+func (h *roleHandler) GetAllPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.roleService.GetAllPermissions())
+}
+
+// GetAllRoles godoc
+//
+//	@Summary		List roles
+//	@Description	Retrieve the roles with their permissions
+//	@Tags			roles
+//	@Produce		json
+//	@Success		200	{array}		dto.RoleResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/roles [get]
+func (h *roleHandler) GetAllRoles(c *gin.Context) {
+	roles, err := h.roleService.GetAllRoles()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// GetRole godoc
+//
+//	@Summary		Get a role
+//	@Description	Retrieve a role with its permissions
+//	@Tags			roles
+//	@Produce		json
+//	@Param			name	path		string	true	"Role name"
+//	@Success		200		{object}	dto.RoleResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/roles/{name} [get]
+func (h *roleHandler) GetRole(c *gin.Context) {
+	role, err := h.roleService.GetRole(models.Role(c.Param("name")))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+// CreateRole godoc
+//
+//	@Summary		Create a role
+//	@Description	Add a role granting a set of permissions. Only super admins can grant permissions they do not hold.
+//	@Tags			roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			role	body		dto.CreateRoleRequest	true	"Role"
+//	@Success		201		{object}	dto.RoleResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/roles [post]
+func (h *roleHandler) CreateRole(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	roleRequest, ok := validatedModel.(*dto.CreateRoleRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to CreateRoleRequest", nil))
+		return
+	}
+
+	caller, ok := callerRole(c)
+	if !ok {
+		return
+	}
+
+	role, err := h.roleService.CreateRole(roleRequest, caller)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, role)
+}
+
+// UpdateRole godoc
+//
+//	@Summary		Update a role
+//	@Description	Replace the description and permissions of a role. Its users are held to them from their next request. The super admin role cannot be changed, and only super admins can add permissions they do not hold.
+//	@Tags			roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string			true	"Role name"
+//	@Param			role	body		dto.RoleRequest	true	"Role"
+//	@Success		200		{object}	dto.RoleResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/roles/{name} [put]
+func (h *roleHandler) UpdateRole(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	roleRequest, ok := validatedModel.(*dto.RoleRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to RoleRequest", nil))
+		return
+	}
+
+	caller, ok := callerRole(c)
+	if !ok {
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(models.Role(c.Param("name")), roleRequest, caller)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+// DeleteRole godoc
+//
+//	@Summary		Delete a role
+//	@Description	Remove a role. Built-in roles and roles given to users cannot be deleted.
+//	@Tags			roles
+//	@Param			name	path	string	true	"Role name"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/roles/{name} [delete]
+func (h *roleHandler) DeleteRole(c *gin.Context) {
+	if err := h.roleService.DeleteRole(models.Role(c.Param("name"))); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
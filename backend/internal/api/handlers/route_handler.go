@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewRouteHandler(routeService service.RouteService) RouteHandler {
+	if routeService == nil {
+		panic("Missing required route service")
+	}
+	return &routeHandler{routeService: routeService}
+}
+
+type routeHandler struct {
+	routeService service.RouteService
+}
+
+// GetAllRoutes godoc
+//
+//	@Summary		List routes
+//	@Description	Retrieve the approved routes with their defaults, allowed aircraft types and parameter overrides
+//	@Tags			routes
+//	@Produce		json
+//	@Success		200	{array}		dto.RouteResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/routes [get]
+func (h *routeHandler) GetAllRoutes(c *gin.Context) {
+	routes, err := h.routeService.GetAllRoutes()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, routes)
+}
+
+// GetRoute godoc
+//
+//	@Summary		Get a route
+//	@Description	Retrieve a route by its ID
+//	@Tags			routes
+//	@Produce		json
+//	@Param			id	path		int	true	"Route ID"
+//	@Success		200	{object}	dto.RouteResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/routes/{id} [get]
+func (h *routeHandler) GetRoute(c *gin.Context) {
+	id, ok := parseRouteID(c)
+	if !ok {
+		return
+	}
+	route, err := h.routeService.GetRoute(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, route)
+}
+
+// GetRouteNetwork godoc
+//
+//	@Summary		Route network
+//	@Description	Return the network as a directed graph, with airports as nodes and routes as edges.
+//	@Description	When an aircraft type is given, only the routes it may fly are included.
+//	@Tags			routes
+//	@Produce		json
+//	@Param			aircraft_type	query		string	false	"Aircraft type code"
+//	@Success		200				{object}	dto.RouteNetworkResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/api/routes/network [get]
+func (h *routeHandler) GetRouteNetwork(c *gin.Context) {
+	var query dto.RouteNetworkQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid network query", err))
+		return
+	}
+
+	network, err := h.routeService.GetNetwork(&query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, network)
+}
+
+// CreateRoute godoc
+//
+//	@Summary		Create a route
+//	@Description	Approve flights from one airport to another. Flights can only be scheduled on approved routes.
+//	@Description	Allowed aircraft types without the range for the route are returned in warnings.
+//	@Tags			routes
+//	@Accept			json
+//	@Produce		json
+//	@Param			route	body		dto.RouteRequest	true	"Route"
+//	@Success		201		{object}	dto.RouteResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/routes [post]
+func (h *routeHandler) CreateRoute(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	routeRequest, ok := validatedModel.(*dto.RouteRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to RouteRequest", nil))
+		return
+	}
+
+	route, err := h.routeService.CreateRoute(routeRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, route)
+}
+
+// UpdateRoute godoc
+//
+//	@Summary		Update a route
+//	@Description	Change a route's defaults, allowed aircraft types and overrides. Its airports cannot change once flights use it.
+//	@Tags			routes
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Route ID"
+//	@Param			route	body		dto.RouteRequest	true	"Route"
+//	@Success		200		{object}	dto.RouteResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/routes/{id} [put]
+func (h *routeHandler) UpdateRoute(c *gin.Context) {
+	id, ok := parseRouteID(c)
+	if !ok {
+		return
+	}
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	routeRequest, ok := validatedModel.(*dto.RouteRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to RouteRequest", nil))
+		return
+	}
+
+	route, err := h.routeService.UpdateRoute(id, routeRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, route)
+}
+
+// DeleteRoute godoc
+//
+//	@Summary		Delete a route
+//	@Description	Withdraw a route. Refused while flights use it.
+//	@Tags			routes
+//	@Param			id	path	int	true	"Route ID"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/routes/{id} [delete]
+func (h *routeHandler) DeleteRoute(c *gin.Context) {
+	id, ok := parseRouteID(c)
+	if !ok {
+		return
+	}
+	if err := h.routeService.DeleteRoute(id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func parseRouteID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid route ID format", err))
+		return 0, false
+	}
+	return uint(id), true
+}
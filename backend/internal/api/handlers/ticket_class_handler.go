@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewTicketClassHandler(ticketClassService service.TicketClassService) TicketClassHandler {
+	if ticketClassService == nil {
+		panic("Missing required ticket class service")
+	}
+	return &ticketClassHandler{ticketClassService: ticketClassService}
+}
+
+type ticketClassHandler struct {
+	ticketClassService service.TicketClassService
+}
+
+// GetAllTicketClasses godoc
+//
+//	@Summary		List ticket classes
+//	@Description	Retrieve the ticket classes in display order
+//	@Tags			ticket-classes
+//	@Produce		json
+//	@Success		200	{array}		dto.TicketClassResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/ticket-classes [get]
+func (h *ticketClassHandler) GetAllTicketClasses(c *gin.Context) {
+	ticketClasses, err := h.ticketClassService.GetAllTicketClasses()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, ticketClasses)
+}
+
+// GetTicketClass godoc
+//
+//	@Summary		Get a ticket class
+//	@Description	Retrieve a ticket class by its ID
+//	@Tags			ticket-classes
+//	@Produce		json
+//	@Param			id	path		int	true	"Ticket class ID"
+//	@Success		200	{object}	dto.TicketClassResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/ticket-classes/{id} [get]
+func (h *ticketClassHandler) GetTicketClass(c *gin.Context) {
+	id, ok := parseTicketClassID(c)
+	if !ok {
+		return
+	}
+	ticketClass, err := h.ticketClassService.GetTicketClass(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, ticketClass)
+}
+
+// CreateTicketClass godoc
+//
+//	@Summary		Create a ticket class
+//	@Description	Add a ticket class. Refused once the number of classes reaches max_ticket_classes.
+//	@Tags			ticket-classes
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticket_class	body		dto.TicketClassRequest	true	"Ticket class"
+//	@Success		201				{object}	dto.TicketClassResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		409				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/api/ticket-classes [post]
+func (h *ticketClassHandler) CreateTicketClass(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	ticketClassRequest, ok := validatedModel.(*dto.TicketClassRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to TicketClassRequest", nil))
+		return
+	}
+
+	ticketClass, err := h.ticketClassService.CreateTicketClass(ticketClassRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, ticketClass)
+}
+
+// UpdateTicketClass godoc
+//
+//	@Summary		Update a ticket class
+//	@Description	Change a ticket class. Seat templates of aircraft types follow a rename.
+//	@Tags			ticket-classes
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		int						true	"Ticket class ID"
+//	@Param			ticket_class	body		dto.TicketClassRequest	true	"Ticket class"
+//	@Success		200				{object}	dto.TicketClassResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		409				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/api/ticket-classes/{id} [put]
+func (h *ticketClassHandler) UpdateTicketClass(c *gin.Context) {
+	id, ok := parseTicketClassID(c)
+	if !ok {
+		return
+	}
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	ticketClassRequest, ok := validatedModel.(*dto.TicketClassRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to TicketClassRequest", nil))
+		return
+	}
+
+	ticketClass, err := h.ticketClassService.UpdateTicketClass(id, ticketClassRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, ticketClass)
+}
+
+// DeleteTicketClass godoc
+//
+//	@Summary		Delete a ticket class
+//	@Description	Remove a ticket class. Refused while seats or seat templates use it.
+//	@Tags			ticket-classes
+//	@Param			id	path	int	true	"Ticket class ID"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/ticket-classes/{id} [delete]
+func (h *ticketClassHandler) DeleteTicketClass(c *gin.Context) {
+	id, ok := parseTicketClassID(c)
+	if !ok {
+		return
+	}
+	if err := h.ticketClassService.DeleteTicketClass(id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func parseTicketClassID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid ticket class ID format", err))
+		return 0, false
+	}
+	return uint(id), true
+}
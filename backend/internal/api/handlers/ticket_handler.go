@@ -6,6 +6,8 @@ import (
 
 	"github.com/aprilboiz/flight-management/internal/dto"
 	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/middleware"
+	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -16,19 +18,36 @@ type ticketHandler struct {
 
 // GetAllTickets godoc
 //	@Summary		Get all tickets
-//	@Description	Retrieve a list of all tickets in the system
+//	@Description	Retrieve a list of all tickets in the system, optionally only those issued to an ID card
+//	@Description	or booked under a booking reference.
+//	@Description	Passenger identifiers are masked unless the caller's role may view them.
 //	@Tags			tickets
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{array}		dto.TicketResponse
-//	@Failure		500	{object}	exceptions.AppError
+//	@Param			id_card				query		string	false	"ID card number"
+//	@Param			booking_reference	query		string	false	"Booking reference"
+//	@Success		200					{array}		dto.TicketResponse
+//	@Failure		500					{object}	exceptions.AppError
 //	@Router			/tickets [get]
 func (t *ticketHandler) GetAllTickets(c *gin.Context) {
-	tickets, err := t.ticketService.GetAllTickets()
+	var tickets []*dto.TicketResponse
+	var err error
+	if idCard, ok := c.GetQuery("id_card"); ok {
+		tickets, err = t.ticketService.GetTicketsByIDCard(idCard)
+	} else if reference, ok := c.GetQuery("booking_reference"); ok {
+		tickets, err = t.ticketService.GetTicketsByBookingReference(reference)
+	} else {
+		tickets, err = t.ticketService.GetAllTickets()
+	}
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
+	if !canViewPII(c) {
+		for _, ticket := range tickets {
+			ticket.MaskPII()
+		}
+	}
 	c.JSON(http.StatusOK, tickets)
 }
 
@@ -56,12 +75,16 @@ func (t *ticketHandler) GetTicketByID(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
+	if !canViewPII(c) {
+		ticket.MaskPII()
+	}
 	c.JSON(http.StatusOK, ticket)
 }
 
 // CreateTicket godoc
 //	@Summary		Create a new ticket
-//	@Description	Create a new ticket with the provided information
+//	@Description	Create a new ticket with the provided information. When seat_number is omitted, a free
+//	@Description	seat of ticket_class is assigned, honoring seat_preference where possible.
 //	@Tags			tickets
 //	@Accept			json
 //	@Produce		json
@@ -87,9 +110,50 @@ func (t *ticketHandler) CreateTicket(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
+	if !canViewPII(c) {
+		ticket.MaskPII()
+	}
 	c.JSON(http.StatusCreated, ticket)
 }
 
+// CreateGroupBooking godoc
+//	@Summary		Book tickets for a group
+//	@Description	Book seats of one ticket class for passengers travelling together. Seats are assigned
+//	@Description	side by side where possible, and every ticket shares the returned booking reference.
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		json
+//	@Param			booking	body		dto.GroupTicketRequest	true	"Group booking"
+//	@Success		201		{object}	dto.GroupTicketResponse
+//	@Failure		400		{object}	exceptions.AppError
+//	@Failure		409		{object}	exceptions.AppError
+//	@Failure		500		{object}	exceptions.AppError
+//	@Router			/tickets/group [post]
+func (t *ticketHandler) CreateGroupBooking(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	groupRequest, ok := validatedModel.(*dto.GroupTicketRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to GroupTicketRequest", nil))
+		return
+	}
+
+	booking, err := t.ticketService.CreateGroup(groupRequest)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !canViewPII(c) {
+		for _, ticket := range booking.Tickets {
+			ticket.MaskPII()
+		}
+	}
+	c.JSON(http.StatusCreated, booking)
+}
+
 // UpdateTicketStatus godoc
 //
 //	@Summary		Update ticket status
@@ -128,6 +192,9 @@ func (t *ticketHandler) UpdateTicketStatus(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
+	if !canViewPII(c) {
+		ticket.MaskPII()
+	}
 	c.JSON(http.StatusOK, ticket)
 }
 
@@ -189,6 +256,31 @@ func (t *ticketHandler) GetBookingTypes(c *gin.Context) {
 	})
 }
 
+// RotatePIIEncryption godoc
+//
+//	@Summary		Re-encrypt passenger data
+//	@Description	Re-encrypt ticket PII that is not yet sealed with the active encryption key
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	dto.PIIRotationResponse
+//	@Failure		403	{object}	exceptions.AppError
+//	@Failure		500	{object}	exceptions.AppError
+//	@Router			/tickets/pii/rotate [post]
+func (t *ticketHandler) RotatePIIEncryption(c *gin.Context) {
+	result, err := t.ticketService.RotatePIIEncryption()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// canViewPII reports whether the caller's role grants seeing unmasked passenger data.
+func canViewPII(c *gin.Context) bool {
+	return middleware.HasPermission(c, models.PermissionTicketsReadPII)
+}
+
 func NewTicketHandler(ticketService service.TicketService) TicketHandler {
 	return &ticketHandler{ticketService: ticketService}
 }
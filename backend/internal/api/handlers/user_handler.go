@@ -3,10 +3,13 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/aprilboiz/flight-management/internal/dto"
 	e "github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/internal/service"
+	"github.com/aprilboiz/flight-management/pkg/auth"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -26,13 +29,14 @@ func NewUserHandler(userService service.UserService, logger *zap.Logger) UserHan
 // Register godoc
 //
 //	@Summary		Register a new user
-//	@Description	Register a new user with username, password, and email
+//	@Description	Register a new user with username, password, and email. The password must meet the password policy. Depending on the registration mode, an invitation token or an email in an allowed domain is required.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
 //	@Param			user	body		dto.RegisterRequest	true	"User registration information"
 //	@Success		200		{object}	dto.AuthResponse
 //	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
 //	@Failure		409		{object}	dto.ErrorResponse
 //	@Router			/auth/register [post]
 func (h *userHandler) Register(c *gin.Context) {
@@ -44,9 +48,20 @@ func (h *userHandler) Register(c *gin.Context) {
 
 	response, err := h.userService.Register(req)
 	if err != nil {
+		var appErr *e.AppError
 		switch {
+		case errors.As(err, &appErr):
+			_ = c.Error(err)
 		case errors.Is(err, service.ErrUserExists):
 			_ = c.Error(e.NewAppError(e.CONFLICT, "User already exists", nil))
+		case errors.Is(err, service.ErrRegistrationClosed):
+			_ = c.Error(e.NewAppError(e.FORBIDDEN, "Registration is closed", nil))
+		case errors.Is(err, service.ErrInvitationRequired):
+			_ = c.Error(e.NewAppError(e.FORBIDDEN, "An invitation is required to register", nil))
+		case errors.Is(err, service.ErrEmailDomainNotAllowed):
+			_ = c.Error(e.NewAppError(e.FORBIDDEN, "Email domain is not allowed to register", nil))
+		case errors.Is(err, service.ErrInvalidInvitation):
+			_ = c.Error(e.NewAppError(e.BadRequest, "Invitation is invalid, expired or already used", nil))
 		default:
 			h.logger.Error("Failed to register user", zap.Error(err))
 			_ = c.Error(e.InternalError("Failed to register user", err))
@@ -60,7 +75,7 @@ func (h *userHandler) Register(c *gin.Context) {
 // Login godoc
 //
 //	@Summary		Login user
-//	@Description	Login with username and password
+//	@Description	Login with username and password. Failed attempts slow down further attempts and eventually lock out the username or IP address.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
@@ -68,6 +83,8 @@ func (h *userHandler) Register(c *gin.Context) {
 //	@Success		200		{object}	dto.AuthResponse
 //	@Failure		400		{object}	dto.ErrorResponse
 //	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		429		{object}	dto.ErrorResponse
 //	@Router			/auth/login [post]
 func (h *userHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -76,11 +93,17 @@ func (h *userHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.userService.Login(req)
+	response, err := h.userService.Login(req, c.ClientIP())
 	if err != nil {
+		var throttled *service.LoginThrottledError
 		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(throttled.RetryAfter.Seconds())))
+			_ = c.Error(e.NewAppError(e.TooManyRequests, "Too many failed login attempts, try again later", nil))
 		case errors.Is(err, service.ErrInvalidCredentials):
 			_ = c.Error(e.NewAppError(e.UNAUTHORIZED, "Invalid credentials", nil))
+		case errors.Is(err, service.ErrUserDisabled):
+			_ = c.Error(e.NewAppError(e.FORBIDDEN, "User account is disabled", nil))
 		default:
 			h.logger.Error("Failed to login user", zap.Error(err))
 			_ = c.Error(e.InternalError("Failed to login", err))
@@ -90,3 +113,563 @@ func (h *userHandler) Login(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// Refresh godoc
+//
+//	@Summary		Refresh tokens
+//	@Description	Exchange a refresh token for a new access token and refresh token. Each refresh token works once; reusing one revokes its session.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	body		dto.RefreshRequest	true	"Refresh token"
+//	@Success		200		{object}	dto.AuthResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Router			/auth/refresh [post]
+func (h *userHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(e.BadRequestError("Invalid request body", err))
+		return
+	}
+
+	response, err := h.userService.Refresh(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRefreshToken):
+			_ = c.Error(e.NewAppError(e.UNAUTHORIZED, "Refresh token is invalid, expired or revoked", nil))
+		case errors.Is(err, service.ErrUserDisabled):
+			_ = c.Error(e.NewAppError(e.FORBIDDEN, "User account is disabled", nil))
+		default:
+			h.logger.Error("Failed to refresh tokens", zap.Error(err))
+			_ = c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Logout godoc
+//
+//	@Summary		Log out
+//	@Description	Revoke the current session. Its access and refresh tokens stop working immediately.
+//	@Tags			auth
+//	@Success		204
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/auth/logout [post]
+func (h *userHandler) Logout(c *gin.Context) {
+	if err := h.userService.Logout(c.GetUint("sessionID")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+//
+//	@Summary		Log out all sessions
+//	@Description	Revoke every session of the current user, on every device
+//	@Tags			auth
+//	@Success		204
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/auth/logout-all [post]
+func (h *userHandler) LogoutAll(c *gin.Context) {
+	if err := h.userService.LogoutAll(c.GetUint("userID")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change password
+//	@Description	Change the current user's password. The new password must meet the password policy. Wrong current passwords count as failed logins. Other sessions of the user are revoked.
+//	@Tags			auth
+//	@Accept			json
+//	@Param			password	body	dto.ChangePasswordRequest	true	"Current and new password"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		429	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/auth/change-password [post]
+func (h *userHandler) ChangePassword(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	passwordRequest, ok := validatedModel.(*dto.ChangePasswordRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to ChangePasswordRequest", nil))
+		return
+	}
+
+	err := h.userService.ChangePassword(c.GetUint("userID"), c.GetUint("sessionID"), passwordRequest, c.ClientIP())
+	if err != nil {
+		var throttled *service.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(throttled.RetryAfter.Seconds())))
+			_ = c.Error(e.NewAppError(e.TooManyRequests, "Too many failed attempts, try again later", nil))
+		case errors.Is(err, service.ErrIncorrectPassword):
+			_ = c.Error(e.NewAppError(e.BadRequest, "Current password is incorrect", nil))
+		default:
+			_ = c.Error(err)
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RequestPasswordReset godoc
+//
+//	@Summary		Request a password reset
+//	@Description	Mail a single-use password reset link to the user with this email. The response is the same whether or not the email is registered.
+//	@Tags			auth
+//	@Accept			json
+//	@Param			email	body	dto.PasswordResetRequest	true	"Email of the account"
+//	@Success		202
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Router			/api/auth/password-reset [post]
+func (h *userHandler) RequestPasswordReset(c *gin.Context) {
+	var req dto.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(e.BadRequestError("Invalid request body", err))
+		return
+	}
+
+	h.userService.RequestPasswordReset(&req)
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset password
+//	@Description	Set a new password with the token of a password reset link. The new password must meet the password policy. Every session of the user is revoked and a login lockout is lifted.
+//	@Tags			auth
+//	@Accept			json
+//	@Param			reset	body	dto.PasswordResetConfirmRequest	true	"Reset token and new password"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/auth/password-reset/confirm [post]
+func (h *userHandler) ResetPassword(c *gin.Context) {
+	var req dto.PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(e.BadRequestError("Invalid request body", err))
+		return
+	}
+
+	if err := h.userService.ResetPassword(&req, c.ClientIP()); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidResetToken):
+			_ = c.Error(e.NewAppError(e.BadRequest, "Password reset token is invalid, expired or already used", nil))
+		case errors.Is(err, service.ErrUserDisabled):
+			_ = c.Error(e.NewAppError(e.FORBIDDEN, "User account is disabled", nil))
+		default:
+			h.logger.Error("Failed to reset password", zap.Error(err))
+			_ = c.Error(err)
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetJWKS godoc
+//
+//	@Summary		Get the token verification keys
+//	@Description	Public keys, as a JSON Web Key Set, that other services use to verify access tokens
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	auth.JWKSet
+//	@Router			/.well-known/jwks.json [get]
+func (h *userHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, auth.GetKeySet().JWKS())
+}
+
+// GetMe godoc
+//
+//	@Summary		Get the current user
+//	@Description	Describe the caller with the permissions of their role, so clients can hide actions they are not allowed
+//	@Tags			users
+//	@Produce		json
+//	@Success		200	{object}	dto.MeResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/me [get]
+func (h *userHandler) GetMe(c *gin.Context) {
+	me, err := h.userService.GetMe(c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, me)
+}
+
+// GetUsers godoc
+//
+//	@Summary		List users
+//	@Description	List users ordered by username, optionally searching usernames and emails or filtering by role
+//	@Tags			users
+//	@Produce		json
+//	@Param			search	query		string	false	"Part of the username or email"
+//	@Param			role	query		string	false	"Role"	Enums(SUPER_ADMIN, ADMIN, STAFF)
+//	@Success		200		{array}		dto.UserResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/users [get]
+func (h *userHandler) GetUsers(c *gin.Context) {
+	var query dto.UserSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid user search query", err))
+		return
+	}
+
+	users, err := h.userService.ListUsers(&query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// GetUser godoc
+//
+//	@Summary		Get a user
+//	@Description	Retrieve a user by ID
+//	@Tags			users
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	dto.UserResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/users/{id} [get]
+func (h *userHandler) GetUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	user, err := h.userService.GetUser(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// CreateUser godoc
+//
+//	@Summary		Create a user
+//	@Description	Create a user. Only super admins can create super admins, and others only users of roles whose permissions they hold.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			user	body		dto.CreateUserRequest	true	"User"
+//	@Success		201		{object}	dto.UserResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/users [post]
+func (h *userHandler) CreateUser(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	userRequest, ok := validatedModel.(*dto.CreateUserRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to CreateUserRequest", nil))
+		return
+	}
+
+	role, ok := callerRole(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.CreateUser(userRequest, role)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
+
+// ChangeUserRole godoc
+//
+//	@Summary		Change a user's role
+//	@Description	Give a user another role. The last enabled super admin cannot be demoted. Callers who are not super admins can only move users between roles whose permissions they hold.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"User ID"
+//	@Param			role	body		dto.UserRoleRequest	true	"New role"
+//	@Success		200		{object}	dto.UserResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/api/users/{id}/role [put]
+func (h *userHandler) ChangeUserRole(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	roleRequest, ok := validatedModel.(*dto.UserRoleRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to UserRoleRequest", nil))
+		return
+	}
+
+	role, ok := callerRole(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.ChangeRole(id, roleRequest.Role, role)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// DisableUser godoc
+//
+//	@Summary		Disable a user
+//	@Description	Stop a user from logging in. The last enabled super admin cannot be disabled. Only super admins can disable super admins, and others only users in roles whose permissions they hold.
+//	@Tags			users
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	dto.UserResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/users/{id}/disable [post]
+func (h *userHandler) DisableUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	role, ok := callerRole(c)
+	if !ok {
+		return
+	}
+	user, err := h.userService.DisableUser(id, role)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// EnableUser godoc
+//
+//	@Summary		Enable a user
+//	@Description	Let a disabled user log in again. Only super admins can enable super admins, and others only users in roles whose permissions they hold.
+//	@Tags			users
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	dto.UserResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/users/{id}/enable [post]
+func (h *userHandler) EnableUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	role, ok := callerRole(c)
+	if !ok {
+		return
+	}
+	user, err := h.userService.EnableUser(id, role)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// UnlockUser godoc
+//
+//	@Summary		Unlock a user
+//	@Description	Lift the lockout of a user after failed logins. The unlock is recorded in the audit log. Only super admins can unlock super admins, and others only users in roles whose permissions they hold.
+//	@Tags			users
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	dto.UserResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/users/{id}/unlock [post]
+func (h *userHandler) UnlockUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	role, ok := callerRole(c)
+	if !ok {
+		return
+	}
+	user, err := h.userService.UnlockUser(id, role, c.GetString("username"), c.ClientIP())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser godoc
+//
+//	@Summary		Delete a user
+//	@Description	Delete a user. The last enabled super admin cannot be deleted. Only super admins can delete super admins, and others only users in roles whose permissions they hold.
+//	@Tags			users
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/users/{id} [delete]
+func (h *userHandler) DeleteUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	role, ok := callerRole(c)
+	if !ok {
+		return
+	}
+	if err := h.userService.DeleteUser(id, role); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateInvitation godoc
+//
+//	@Summary		Issue an invitation
+//	@Description	Issue a single-use invitation to register with a preassigned role. The token is only returned in this response. Only super admins can invite super admins, and others only into roles whose permissions they hold.
+//	@Tags			invitations
+//	@Accept			json
+//	@Produce		json
+//	@Param			invitation	body		dto.InvitationRequest	true	"Invitation"
+//	@Success		201			{object}	dto.InvitationResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/api/invitations [post]
+func (h *userHandler) CreateInvitation(c *gin.Context) {
+	validatedModel, exists := c.Get("validatedModel")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot find validated model in context", nil))
+		return
+	}
+	invitationRequest, ok := validatedModel.(*dto.InvitationRequest)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast validated model to InvitationRequest", nil))
+		return
+	}
+	issuerRole, ok := callerRole(c)
+	if !ok {
+		return
+	}
+
+	invitation, err := h.userService.CreateInvitation(invitationRequest, c.GetString("username"), issuerRole)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// GetInvitations godoc
+//
+//	@Summary		List invitations
+//	@Description	List invitations, newest first, with whether they are pending, used or expired
+//	@Tags			invitations
+//	@Produce		json
+//	@Success		200	{array}		dto.InvitationResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/invitations [get]
+func (h *userHandler) GetInvitations(c *gin.Context) {
+	invitations, err := h.userService.ListInvitations()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation godoc
+//
+//	@Summary		Revoke an invitation
+//	@Description	Withdraw an invitation that has not been used
+//	@Tags			invitations
+//	@Param			id	path	int	true	"Invitation ID"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/api/invitations/{id} [delete]
+func (h *userHandler) RevokeInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid invitation ID format", err))
+		return
+	}
+	if err := h.userService.RevokeInvitation(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// callerRole returns the role of the authenticated user, reporting an error when it is missing.
+func callerRole(c *gin.Context) (models.Role, bool) {
+	value, exists := c.Get("role")
+	if !exists {
+		_ = c.Error(e.NewAppError(e.UNAUTHORIZED, "User role not found in context", nil))
+		return "", false
+	}
+	role, ok := value.(models.Role)
+	if !ok {
+		_ = c.Error(e.NewAppError(e.INTERNAL, "Cannot cast user role", nil))
+		return "", false
+	}
+	return role, true
+}
+
+func parseUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(e.NewAppError(e.BadRequest, "Invalid user ID format", err))
+		return 0, false
+	}
+	return uint(id), true
+}
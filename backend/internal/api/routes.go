@@ -38,22 +38,36 @@ func SetupRoutes(router *gin.Engine, h Handlers) {
 		{
 			authRoutes.POST("/register", h.UserHandler.Register)
 			authRoutes.POST("/login", h.UserHandler.Login)
+			authRoutes.POST("/refresh", h.UserHandler.Refresh)
+			authRoutes.POST("/password-reset", h.UserHandler.RequestPasswordReset)
+			authRoutes.POST("/password-reset/confirm", h.UserHandler.ResetPassword)
 		}
 
 		// Protected routes
 		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(h.TokenValidator), middleware.PermissionLoader(h.PermissionResolver))
 		{
+			// Session routes
+			sessionRoutes := protected.Group("/auth")
+			{
+				sessionRoutes.POST("/logout", h.UserHandler.Logout)
+				sessionRoutes.POST("/logout-all", h.UserHandler.LogoutAll)
+				sessionRoutes.POST("/change-password", middleware.ValidateRequest(&dto.ChangePasswordRequest{}), h.UserHandler.ChangePassword)
+			}
+
 			// Flight routes
 			flightRoutes := protected.Group("/flights")
 			{
 				flightRoutes.GET("", h.FlightHandler.GetAllFlights)
 				flightRoutes.GET("/list", h.FlightHandler.GetAllFlightsInList)
+				flightRoutes.GET("/duration-suggestion", h.FlightHandler.SuggestFlightDuration)
 				flightRoutes.GET("/:code", h.FlightHandler.GetFlightByCode)
+				flightRoutes.GET("/:code/seats", h.FlightHandler.GetFlightSeats)
+				flightRoutes.GET("/:code/parameters", h.FlightHandler.GetFlightParameters)
 
 				// Higher level roles
 				adminFlightOps := flightRoutes.Group("")
-				adminFlightOps.Use(middleware.RoleMiddleware(models.RoleAdmin, models.RoleSuperAdmin))
+				adminFlightOps.Use(middleware.PermissionMiddleware(models.PermissionFlightsWrite))
 				{
 					adminFlightOps.POST("", middleware.ValidateRequest(&dto.FlightRequest{}), h.FlightHandler.CreateFlight)
 					adminFlightOps.PUT("/:code", middleware.ValidateRequest(&dto.FlightRequest{}), h.FlightHandler.UpdateFlight)
@@ -66,29 +80,150 @@ func SetupRoutes(router *gin.Engine, h Handlers) {
 			{
 				planeRoutes.GET("", h.PlaneHandler.GetAllPlanes)
 				planeRoutes.GET("/:code", h.PlaneHandler.GetPlaneByCode)
+				planeRoutes.GET("/:code/maintenance", h.MaintenanceHandler.GetMaintenanceWindows)
+
+				// Fleet management
+				adminPlaneOps := planeRoutes.Group("")
+				adminPlaneOps.Use(middleware.PermissionMiddleware(models.PermissionFleetWrite))
+				{
+					adminPlaneOps.POST("", middleware.ValidateRequest(&dto.PlaneRequest{}), h.PlaneHandler.CreatePlane)
+					adminPlaneOps.PUT("/:code", middleware.ValidateRequest(&dto.PlaneRequest{}), h.PlaneHandler.UpdatePlane)
+					adminPlaneOps.PUT("/:code/seat-map", middleware.ValidateRequest(&dto.SeatLayoutDTO{}), h.PlaneHandler.BuildSeatMap)
+					adminPlaneOps.POST("/:code/retire", h.PlaneHandler.RetirePlane)
+
+					// Maintenance windows
+					adminPlaneOps.POST("/:code/maintenance", middleware.ValidateRequest(&dto.MaintenanceWindowRequest{}), h.MaintenanceHandler.CreateMaintenanceWindow)
+					adminPlaneOps.DELETE("/:code/maintenance/:id", h.MaintenanceHandler.DeleteMaintenanceWindow)
+					adminPlaneOps.GET("/:code/maintenance/:id/affected-flights", h.MaintenanceHandler.GetAffectedFlights)
+				}
+			}
+
+			// Aircraft type routes
+			aircraftTypeRoutes := protected.Group("/aircraft-types")
+			{
+				aircraftTypeRoutes.GET("", h.PlaneHandler.GetAllAircraftTypes)
+				aircraftTypeRoutes.GET("/:code", h.PlaneHandler.GetAircraftType)
+
+				adminAircraftTypeOps := aircraftTypeRoutes.Group("")
+				adminAircraftTypeOps.Use(middleware.PermissionMiddleware(models.PermissionFleetWrite))
+				{
+					adminAircraftTypeOps.POST("", middleware.ValidateRequest(&dto.AircraftTypeRequest{}), h.PlaneHandler.CreateAircraftType)
+					adminAircraftTypeOps.PUT("/:code", middleware.ValidateRequest(&dto.AircraftTypeRequest{}), h.PlaneHandler.UpdateAircraftType)
+					adminAircraftTypeOps.DELETE("/:code", h.PlaneHandler.DeleteAircraftType)
+				}
+			}
+
+			// Ticket class routes
+			ticketClassRoutes := protected.Group("/ticket-classes")
+			{
+				ticketClassRoutes.GET("", h.TicketClassHandler.GetAllTicketClasses)
+				ticketClassRoutes.GET("/:id", h.TicketClassHandler.GetTicketClass)
+
+				adminTicketClassOps := ticketClassRoutes.Group("")
+				adminTicketClassOps.Use(middleware.PermissionMiddleware(models.PermissionTicketClassesWrite))
+				{
+					adminTicketClassOps.POST("", middleware.ValidateRequest(&dto.TicketClassRequest{}), h.TicketClassHandler.CreateTicketClass)
+					adminTicketClassOps.PUT("/:id", middleware.ValidateRequest(&dto.TicketClassRequest{}), h.TicketClassHandler.UpdateTicketClass)
+					adminTicketClassOps.DELETE("/:id", h.TicketClassHandler.DeleteTicketClass)
+				}
+			}
+
+			// Route network
+			routeRoutes := protected.Group("/routes")
+			{
+				routeRoutes.GET("", h.RouteHandler.GetAllRoutes)
+				routeRoutes.GET("/network", h.RouteHandler.GetRouteNetwork)
+				routeRoutes.GET("/:id", h.RouteHandler.GetRoute)
+
+				adminRouteOps := routeRoutes.Group("")
+				adminRouteOps.Use(middleware.PermissionMiddleware(models.PermissionRoutesWrite))
+				{
+					adminRouteOps.POST("", middleware.ValidateRequest(&dto.RouteRequest{}), h.RouteHandler.CreateRoute)
+					adminRouteOps.PUT("/:id", middleware.ValidateRequest(&dto.RouteRequest{}), h.RouteHandler.UpdateRoute)
+					adminRouteOps.DELETE("/:id", h.RouteHandler.DeleteRoute)
+				}
+			}
+
+			// Fleet routes
+			fleetRoutes := protected.Group("/fleet")
+			{
+				fleetRoutes.GET("/availability", h.MaintenanceHandler.GetFleetAvailability)
 			}
 
 			// Airport routes
 			airportRoutes := protected.Group("/airports")
 			{
 				airportRoutes.GET("", h.AirportHandler.GetAllAirports)
+				airportRoutes.GET("/search", h.AirportHandler.SearchAirports)
 				airportRoutes.GET("/:code", h.AirportHandler.GetAirportByCode)
+
+				adminAirportOps := airportRoutes.Group("")
+				adminAirportOps.Use(middleware.PermissionMiddleware(models.PermissionAirportsWrite))
+				{
+					adminAirportOps.POST("", middleware.ValidateRequest(&dto.AirportRequest{}), h.AirportHandler.CreateAirport)
+					adminAirportOps.POST("/import", middleware.ValidateRequest(&dto.AirportImportRequest{}), h.AirportHandler.ImportAirports)
+					adminAirportOps.PUT("/:code", middleware.ValidateRequest(&dto.AirportRequest{}), h.AirportHandler.UpdateAirport)
+					adminAirportOps.DELETE("/:code", h.AirportHandler.DeleteAirport)
+				}
 			}
 
 			// Parameter routes
-			adminRoutes := protected.Group("")
-			adminRoutes.Use(middleware.RoleMiddleware(models.RoleSuperAdmin))
+			paramHandler := protected.Group("/params")
+			paramHandler.Use(middleware.PermissionMiddleware(models.PermissionParamsManage))
 			{
-				paramHandler := adminRoutes.Group("/params")
-				{
-					paramHandler.GET("", h.ParameterHandler.GetAllParameters)
-					paramHandler.PUT("", middleware.ValidateRequest(&models.Parameter{}), h.ParameterHandler.UpdateParameters)
-				}
+				paramHandler.GET("", h.ParameterHandler.GetAllParameters)
+				paramHandler.PUT("", middleware.ValidateRequest(&dto.ParameterRequest{}), h.ParameterHandler.UpdateParameters)
+				paramHandler.POST("/impact", middleware.ValidateRequest(&dto.ParameterRequest{}), h.ParameterHandler.AnalyzeParameters)
+				paramHandler.GET("/versions", h.ParameterHandler.GetParameterVersions)
+				paramHandler.GET("/versions/:version", h.ParameterHandler.GetParameterVersion)
+				paramHandler.POST("/versions/:version/rollback", h.ParameterHandler.RollbackParameters)
+				paramHandler.DELETE("/versions/:version", h.ParameterHandler.CancelParameterVersion)
+			}
+
+			// The caller and their permissions
+			protected.GET("/me", h.UserHandler.GetMe)
+
+			// User administration
+			userRoutes := protected.Group("/users")
+			userRoutes.Use(middleware.PermissionMiddleware(models.PermissionUsersManage))
+			{
+				userRoutes.GET("", h.UserHandler.GetUsers)
+				userRoutes.GET("/:id", h.UserHandler.GetUser)
+				userRoutes.POST("", middleware.ValidateRequest(&dto.CreateUserRequest{}), h.UserHandler.CreateUser)
+				userRoutes.PUT("/:id/role", middleware.ValidateRequest(&dto.UserRoleRequest{}), h.UserHandler.ChangeUserRole)
+				userRoutes.POST("/:id/disable", h.UserHandler.DisableUser)
+				userRoutes.POST("/:id/enable", h.UserHandler.EnableUser)
+				userRoutes.POST("/:id/unlock", h.UserHandler.UnlockUser)
+				userRoutes.DELETE("/:id", h.UserHandler.DeleteUser)
+			}
+
+			// Security audit log
+			protected.GET("/audit-logs", middleware.PermissionMiddleware(models.PermissionAuditView), h.AuditHandler.GetAuditLog)
+
+			// Roles and the permissions they grant
+			roleRoutes := protected.Group("")
+			roleRoutes.Use(middleware.PermissionMiddleware(models.PermissionRolesManage))
+			{
+				roleRoutes.GET("/permissions", h.RoleHandler.GetAllPermissions)
+				roleRoutes.GET("/roles", h.RoleHandler.GetAllRoles)
+				roleRoutes.GET("/roles/:name", h.RoleHandler.GetRole)
+				roleRoutes.POST("/roles", middleware.ValidateRequest(&dto.CreateRoleRequest{}), h.RoleHandler.CreateRole)
+				roleRoutes.PUT("/roles/:name", middleware.ValidateRequest(&dto.RoleRequest{}), h.RoleHandler.UpdateRole)
+				roleRoutes.DELETE("/roles/:name", h.RoleHandler.DeleteRole)
+			}
+
+			// Invitations to register
+			invitationRoutes := protected.Group("/invitations")
+			invitationRoutes.Use(middleware.PermissionMiddleware(models.PermissionInvitationsManage))
+			{
+				invitationRoutes.GET("", h.UserHandler.GetInvitations)
+				invitationRoutes.POST("", middleware.ValidateRequest(&dto.InvitationRequest{}), h.UserHandler.CreateInvitation)
+				invitationRoutes.DELETE("/:id", h.UserHandler.RevokeInvitation)
 			}
 
 			// Report routes
 			reportRoutes := protected.Group("")
-			reportRoutes.Use(middleware.RoleMiddleware(models.RoleAdmin, models.RoleSuperAdmin))
+			reportRoutes.Use(middleware.PermissionMiddleware(models.PermissionReportsView))
 			{
 				reportHandler := reportRoutes.Group("/reports")
 				{
@@ -103,15 +238,48 @@ func SetupRoutes(router *gin.Engine, h Handlers) {
 			{
 				ticketRoutes.GET("", h.TicketHandler.GetAllTickets)
 				ticketRoutes.GET("/:id", h.TicketHandler.GetTicketByID)
-				ticketRoutes.POST("", middleware.ValidateRequest(&dto.TicketRequest{}), h.TicketHandler.CreateTicket)
-				ticketRoutes.PUT("/:id/status", middleware.ValidateRequest(&dto.TicketStatusUpdateRequest{}), h.TicketHandler.UpdateTicketStatus)
-				ticketRoutes.DELETE("/:id", h.TicketHandler.DeleteTicket)
+				ticketRoutes.POST("", middleware.PermissionMiddleware(models.PermissionTicketsWrite), middleware.ValidateRequest(&dto.TicketRequest{}), h.TicketHandler.CreateTicket)
+				ticketRoutes.POST("/group", middleware.PermissionMiddleware(models.PermissionTicketsWrite), middleware.ValidateRequest(&dto.GroupTicketRequest{}), h.TicketHandler.CreateGroupBooking)
+				ticketRoutes.PUT("/:id/status", middleware.PermissionMiddleware(models.PermissionTicketsWrite), middleware.ValidateRequest(&dto.TicketStatusUpdateRequest{}), h.TicketHandler.UpdateTicketStatus)
+				ticketRoutes.DELETE("/:id", middleware.PermissionMiddleware(models.PermissionTicketsWrite), h.TicketHandler.DeleteTicket)
 				ticketRoutes.GET("/statuses", h.TicketHandler.GetTicketStatuses)
 				ticketRoutes.GET("/booking-types", h.TicketHandler.GetBookingTypes)
+				ticketRoutes.POST("/pii/rotate", middleware.PermissionMiddleware(models.PermissionPIIRotate), h.TicketHandler.RotatePIIEncryption)
+			}
+
+			// Passenger profiles
+			passengerRoutes := protected.Group("/passengers")
+			{
+				passengerRoutes.GET("/search", h.PassengerHandler.SearchPassengers)
+				passengerRoutes.GET("/:id", h.PassengerHandler.GetPassengerByID)
+				passengerRoutes.GET("/:id/tickets", h.PassengerHandler.GetPassengerHistory)
+				passengerRoutes.POST("", middleware.PermissionMiddleware(models.PermissionPassengersWrite), middleware.ValidateRequest(&dto.PassengerRequest{}), h.PassengerHandler.CreatePassenger)
+				passengerRoutes.PUT("/:id", middleware.PermissionMiddleware(models.PermissionPassengersWrite), middleware.ValidateRequest(&dto.PassengerRequest{}), h.PassengerHandler.UpdatePassenger)
+				passengerRoutes.POST("/:id/documents", middleware.PermissionMiddleware(models.PermissionPassengersWrite), middleware.ValidateRequest(&dto.TravelDocumentDTO{}), h.PassengerHandler.AddPassengerDocument)
+			}
+
+			// Loyalty program
+			loyaltyRoutes := protected.Group("/loyalty/accounts")
+			{
+				loyaltyRoutes.POST("", middleware.PermissionMiddleware(models.PermissionLoyaltyWrite), middleware.ValidateRequest(&dto.LoyaltyEnrollRequest{}), h.LoyaltyHandler.EnrollMember)
+				loyaltyRoutes.GET("/:number", h.LoyaltyHandler.GetAccount)
+				loyaltyRoutes.GET("/:number/transactions", h.LoyaltyHandler.GetTransactions)
+			}
+
+			// Personal data export and erasure
+			privacyRoutes := protected.Group("/privacy")
+			privacyRoutes.Use(middleware.PermissionMiddleware(models.PermissionPrivacyManage))
+			{
+				privacyRoutes.GET("/export", h.PrivacyHandler.ExportPassengerData)
+				privacyRoutes.GET("/erasure-requests", h.PrivacyHandler.GetErasureRequests)
+				privacyRoutes.POST("/erasure-requests", middleware.ValidateRequest(&dto.ErasureRequestRequest{}), h.PrivacyHandler.CreateErasureRequest)
 			}
 		}
 	}
 
+	// Keys verifying access tokens, for other services
+	router.GET("/.well-known/jwks.json", h.UserHandler.GetJWKS)
+
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
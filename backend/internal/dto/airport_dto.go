@@ -1,8 +1,54 @@
 package dto
 
+type AirportRequest struct {
+	AirportCode string   `json:"airport_code" binding:"required,len=3,alpha"` // IATA code
+	IcaoCode    string   `json:"icao_code" binding:"omitempty,len=4,alphanum"`
+	AirportName string   `json:"airport_name" binding:"required,max=255"`
+	CityName    string   `json:"city_name" binding:"required,max=255"`
+	CountryName string   `json:"country_name" binding:"required,max=255"`
+	CountryCode string   `json:"country_code" binding:"omitempty,len=2,alpha"` // ISO 3166-1 alpha-2 code
+	Latitude    *float64 `json:"latitude" binding:"omitempty,latitude"`
+	Longitude   *float64 `json:"longitude" binding:"omitempty,longitude"`
+	ElevationFt *int     `json:"elevation_ft" binding:"omitempty,min=-1500,max=30000"`
+}
+
 type AirportResponse struct {
-	AirportCode string `json:"airport_code"`
-	AirportName string `json:"airport_name"`
-	CityName    string `json:"city_name"`
-	CountryName string `json:"country_name"`
+	AirportCode string   `json:"airport_code"`
+	IcaoCode    string   `json:"icao_code,omitempty"`
+	AirportName string   `json:"airport_name"`
+	CityName    string   `json:"city_name"`
+	CountryName string   `json:"country_name"`
+	CountryCode string   `json:"country_code,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	ElevationFt *int     `json:"elevation_ft,omitempty"`
+}
+
+// AirportImportRequest imports airports from a CSV file in the OurAirports format
+// (https://ourairports.com/data/). Files are read from the configured import directory.
+type AirportImportRequest struct {
+	File          string   `json:"file" binding:"required"`        // airports.csv, relative to the import directory
+	CountriesFile string   `json:"countries_file"`                 // countries.csv naming the countries, required to create airports
+	Countries     []string `json:"countries" binding:"dive,len=2"` // ISO country codes to import, all when empty
+	Types         []string `json:"types"`                          // Airport types to import, large and medium airports when empty
+	DryRun        bool     `json:"dry_run"`                        // Validate and report without saving
+}
+
+type AirportImportResponse struct {
+	Created int  `json:"created"`
+	Updated int  `json:"updated"`
+	Skipped int  `json:"skipped"` // Rows filtered out or without an IATA code
+	DryRun  bool `json:"dry_run"`
+}
+
+// AirportImportError describes a CSV row that failed validation.
+type AirportImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// AirportSearchQuery searches airports by code, name, city or country for type-ahead.
+type AirportSearchQuery struct {
+	Q     string `form:"q" binding:"required,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"` // Defaults to 10
 }
@@ -0,0 +1,20 @@
+package dto
+
+import "github.com/aprilboiz/flight-management/internal/models"
+
+// AuditLogQuery filters the audit log. The latest 100 entries are returned unless limit is set.
+type AuditLogQuery struct {
+	Action  models.AuditAction `form:"action"`
+	Subject string             `form:"subject"`
+	Limit   int                `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+type AuditLogResponse struct {
+	ID        uint               `json:"id"`
+	Action    models.AuditAction `json:"action"`
+	Actor     string             `json:"actor"`
+	Subject   string             `json:"subject,omitempty"`
+	IPAddress string             `json:"ip_address,omitempty"`
+	Details   string             `json:"details,omitempty"`
+	CreatedAt string             `json:"created_at"`
+}
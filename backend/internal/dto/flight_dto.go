@@ -1,13 +1,19 @@
 package dto
 
+import "github.com/aprilboiz/flight-management/internal/models"
+
 type FlightRequest struct {
 	DepartureAirport  string                `json:"departure_airport"`
 	ArrivalAirport    string                `json:"arrival_airport"`
-	Duration          int                   `json:"duration"`
-	BasePrice         float64               `json:"base_price"`
+	Duration          int                   `json:"duration"`   // The route's default duration when zero
+	BasePrice         float64               `json:"base_price"` // The route's default base price when zero
 	DepartureDateTime string                `json:"departure_date"`
 	PlaneCode         string                `json:"plane_code"`
 	IntermediateStop  []IntermediateStopDTO `json:"intermediate_stops"`
+
+	// Overrides of the route's or global parameters for this flight only
+	LatestTicketPurchaseTime *int `json:"latest_ticket_purchase_time" binding:"omitempty,min=0"` // Days before departure
+	TicketCancellationTime   *int `json:"ticket_cancellation_time" binding:"omitempty,min=0"`    // Days before departure
 }
 
 type IntermediateStopDTO struct {
@@ -29,29 +35,67 @@ type SeatInfo struct {
 	ClassName  string  `json:"class_name"`
 	IsBooked   bool    `json:"is_booked"`
 	BookedBy   string  `json:"booked_by,omitempty"`
-	Price      float64 `json:"price"`
+	Price      float64 `json:"price"` // Class fare plus the seat surcharge
+	SeatAttributes
+}
+
+// SeatMap lays out a flight's seats as a grid, one cabin per row range of a ticket class.
+type SeatMap struct {
+	Columns     []string       `json:"columns"`
+	AislesAfter []string       `json:"aisles_after"`
+	Cabins      []CabinSeatMap `json:"cabins"`
+}
+
+type CabinSeatMap struct {
+	ClassName string       `json:"class_name"`
+	FromRow   int          `json:"from_row"`
+	ToRow     int          `json:"to_row"`
+	Rows      []SeatMapRow `json:"rows"`
+}
+
+type SeatMapRow struct {
+	Row       int         `json:"row"`
+	IsExitRow bool        `json:"is_exit_row"`
+	Seats     []*SeatInfo `json:"seats"` // Aligned with the map columns, null where the cabin has no seat
+}
+
+// SeatFilter narrows down the seats offered when choosing a seat on a flight.
+type SeatFilter struct {
+	ClassName     string              `form:"class"`
+	Position      models.SeatPosition `form:"position" binding:"omitempty,oneof=WINDOW AISLE MIDDLE"`
+	ExitRow       *bool               `form:"exit_row"`
+	ExtraLegroom  *bool               `form:"extra_legroom"`
+	Bassinet      *bool               `form:"bassinet"`
+	AvailableOnly bool                `form:"available"` // Hide booked and blocked seats
+	MaxSurcharge  *float64            `form:"max_surcharge" binding:"omitempty,min=0"`
 }
 
 type FlightResponse struct {
 	FlightCode        string                `json:"flight_code"`
 	DepartureAirport  string                `json:"departure_airport"`
 	ArrivalAirport    string                `json:"arrival_airport"`
-	Duration          int                   `json:"duration"`
-	BasePrice         float64               `json:"base_price"`
+	Duration          int                   `json:"duration"`   // The route's default duration when zero
+	BasePrice         float64               `json:"base_price"` // The route's default base price when zero
 	DepartureDateTime string                `json:"departure_date_time"`
 	PlaneCode         string                `json:"plane_code"`
 	IntermediateStop  []IntermediateStopDTO `json:"intermediate_stop"`
 	EmptySeats        int                   `json:"empty_seats"`
 	BookedSeats       int                   `json:"booked_seats"`
 	TotalSeats        int                   `json:"total_seats"`
+	DistanceKm        float64               `json:"distance_km,omitempty"` // Great-circle distance over all segments
+	Segments          []FlightSegmentDTO    `json:"segments,omitempty"`
+	Warnings          []string              `json:"warnings,omitempty"` // Legs beyond the plane's range, implausible durations
+
+	LatestTicketPurchaseTime *int `json:"latest_ticket_purchase_time,omitempty"` // Flight override
+	TicketCancellationTime   *int `json:"ticket_cancellation_time,omitempty"`    // Flight override
 }
 
 type FlightResponseDetailed struct {
 	FlightCode        string                `json:"flight_code"`
 	DepartureAirport  string                `json:"departure_airport"`
 	ArrivalAirport    string                `json:"arrival_airport"`
-	Duration          int                   `json:"duration"`
-	BasePrice         float64               `json:"base_price"`
+	Duration          int                   `json:"duration"`   // The route's default duration when zero
+	BasePrice         float64               `json:"base_price"` // The route's default base price when zero
 	DepartureDateTime string                `json:"departure_date_time"`
 	PlaneCode         string                `json:"plane_code"`
 	IntermediateStop  []IntermediateStopDTO `json:"intermediate_stop"`
@@ -60,6 +104,19 @@ type FlightResponseDetailed struct {
 	TotalSeats        int                   `json:"total_seats"`
 	SeatClassInfo     []SeatClassInfo       `json:"seat_class_info"`
 	Seats             []SeatInfo            `json:"seats"`
+	SeatMap           *SeatMap              `json:"seat_map,omitempty"` // Only for planes built from a seat layout
+	DistanceKm        float64               `json:"distance_km,omitempty"`
+	Segments          []FlightSegmentDTO    `json:"segments,omitempty"`
+
+	LatestTicketPurchaseTime *int `json:"latest_ticket_purchase_time,omitempty"` // Flight override
+	TicketCancellationTime   *int `json:"ticket_cancellation_time,omitempty"`    // Flight override
+}
+
+// FlightSegmentDTO is the part of a flight between two consecutive airports.
+type FlightSegmentDTO struct {
+	From       string  `json:"from"`
+	To         string  `json:"to"`
+	DistanceKm float64 `json:"distance_km,omitempty"` // Left out when an airport has no coordinates
 }
 
 // FlightListResponse represents a flight in the list view
@@ -81,34 +138,69 @@ type FlightListResponse struct {
 	TotalSeats        int     `json:"total_seats"`
 	HasStops          bool    `json:"has_stops"`
 	StopCount         int     `json:"stop_count"`
+	DistanceKm        float64 `json:"distance_km,omitempty"`
 }
 
 type FlightRevenueReport struct {
-	FlightCode string  `json:"flightCode"`
-	Tickets    int     `json:"tickets"`
-	Revenue    float64 `json:"revenue"`
-	Ratio      float64 `json:"ratio"` // Ratio of actual revenue to potential revenue
+	FlightCode       string  `json:"flightCode"`
+	Tickets          int     `json:"tickets"`
+	Revenue          float64 `json:"revenue"`
+	Ratio            float64 `json:"ratio"`                      // Ratio of actual revenue to potential revenue
+	DistanceKm       float64 `json:"distanceKm,omitempty"`       // Left out when an airport has no coordinates
+	SeatKm           float64 `json:"seatKm,omitempty"`           // Available seat-kilometers
+	RevenuePerSeatKm float64 `json:"revenuePerSeatKm,omitempty"` // Revenue per available seat-kilometer
 }
 
 type MonthlyRevenueReport struct {
-	Month        string                `json:"month"` // Format: "YYYY-MM"
-	Flights      []FlightRevenueReport `json:"flights"`
-	TotalRevenue float64               `json:"totalRevenue"`
-	TotalTickets int                   `json:"totalTickets"`
-	AverageRatio float64               `json:"averageRatio"`
+	Month            string                `json:"month"` // Format: "YYYY-MM"
+	Flights          []FlightRevenueReport `json:"flights"`
+	TotalRevenue     float64               `json:"totalRevenue"`
+	TotalTickets     int                   `json:"totalTickets"`
+	AverageRatio     float64               `json:"averageRatio"`
+	TotalSeatKm      float64               `json:"totalSeatKm"`
+	RevenuePerSeatKm float64               `json:"revenuePerSeatKm"` // Over the flights with a known distance
 }
 
 type MonthlyRevenueSummary struct {
-	Month       string  `json:"month"` // Format: "YYYY-MM"
-	FlightCount int     `json:"flightCount"`
-	Revenue     float64 `json:"revenue"`
-	Ratio       float64 `json:"ratio"` // Average ratio across all flights
+	Month            string  `json:"month"` // Format: "YYYY-MM"
+	FlightCount      int     `json:"flightCount"`
+	Revenue          float64 `json:"revenue"`
+	Ratio            float64 `json:"ratio"` // Average ratio across all flights
+	SeatKm           float64 `json:"seatKm"`
+	RevenuePerSeatKm float64 `json:"revenuePerSeatKm"`
 }
 
 type YearlyRevenueReport struct {
-	Year         string                  `json:"year"` // Format: "YYYY"
-	Months       []MonthlyRevenueSummary `json:"months"`
-	TotalRevenue float64                 `json:"totalRevenue"`
-	TotalFlights int                     `json:"totalFlights"`
-	AverageRatio float64                 `json:"averageRatio"`
+	Year             string                  `json:"year"` // Format: "YYYY"
+	Months           []MonthlyRevenueSummary `json:"months"`
+	TotalRevenue     float64                 `json:"totalRevenue"`
+	TotalFlights     int                     `json:"totalFlights"`
+	AverageRatio     float64                 `json:"averageRatio"`
+	TotalSeatKm      float64                 `json:"totalSeatKm"`
+	RevenuePerSeatKm float64                 `json:"revenuePerSeatKm"`
+}
+
+// DurationSuggestionQuery describes a route to estimate the flight duration for. The cruise
+// speed is taken from the plane's aircraft type, or from the aircraft type given directly.
+type DurationSuggestionQuery struct {
+	DepartureAirport string `form:"departure_airport" binding:"required"`
+	ArrivalAirport   string `form:"arrival_airport" binding:"required"`
+	Stops            string `form:"stops"` // Comma-separated stop airport codes, in order
+	PlaneCode        string `form:"plane_code" binding:"required_without=AircraftType"`
+	AircraftType     string `form:"aircraft_type"`
+}
+
+type DurationSuggestionResponse struct {
+	DistanceKm        float64          `json:"distance_km"`
+	CruiseSpeedKmh    int              `json:"cruise_speed_kmh"`
+	SuggestedDuration int              `json:"suggested_duration"` // Minutes in the air, stops excluded
+	Legs              []RouteLegDetail `json:"legs"`
+	Warnings          []string         `json:"warnings,omitempty"`
+}
+
+type RouteLegDetail struct {
+	From       string  `json:"from"`
+	To         string  `json:"to"`
+	DistanceKm float64 `json:"distance_km"`
+	Duration   int     `json:"duration"` // Minutes
 }
@@ -0,0 +1,30 @@
+package dto
+
+import "github.com/aprilboiz/flight-management/internal/models"
+
+type LoyaltyEnrollRequest struct {
+	PassengerID uint `json:"passenger_id" binding:"required"`
+}
+
+type LoyaltyAccountResponse struct {
+	MemberNumber     string             `json:"member_number"`
+	PassengerID      uint               `json:"passenger_id"`
+	PassengerName    string             `json:"passenger_name"`
+	Tier             models.LoyaltyTier `json:"tier"`
+	Balance          int                `json:"balance"`
+	QualifyingPoints int                `json:"qualifying_points"` // Points earned over the last 12 months
+	NextTier         models.LoyaltyTier `json:"next_tier,omitempty"`
+	PointsToNextTier int                `json:"points_to_next_tier,omitempty"`
+	RedemptionValue  float64            `json:"redemption_value"` // Fare discount the balance is worth
+	MemberSince      string             `json:"member_since"`
+}
+
+type LoyaltyTransactionResponse struct {
+	ID           uint                          `json:"id"`
+	TicketID     *uint                         `json:"ticket_id,omitempty"`
+	Type         models.LoyaltyTransactionType `json:"type"`
+	Points       int                           `json:"points"`
+	BalanceAfter int                           `json:"balance_after"`
+	Description  string                        `json:"description"`
+	CreatedAt    string                        `json:"created_at"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+// MaintenanceWindowRequest takes a plane out of service between two local date times.
+type MaintenanceWindowRequest struct {
+	StartTime string `json:"start_time" binding:"required,datetime=2006-01-02 15:04:05"` // Format: "YYYY-MM-DD HH:MM:SS"
+	EndTime   string `json:"end_time" binding:"required,datetime=2006-01-02 15:04:05"`
+	Reason    string `json:"reason" binding:"required,max=255"`
+}
+
+type MaintenanceWindowResponse struct {
+	ID        uint   `json:"id"`
+	PlaneCode string `json:"plane_code"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Reason    string `json:"reason"`
+	CreatedBy string `json:"created_by,omitempty"`
+	// AffectedFlights lists the flights already scheduled inside the window when it was added
+	AffectedFlights []AffectedFlightResponse `json:"affected_flights,omitempty"`
+}
+
+// AffectedFlightResponse is a flight that overlaps a maintenance window and must be moved
+// to another plane or rescheduled.
+type AffectedFlightResponse struct {
+	FlightCode        string `json:"flight_code"`
+	DepartureAirport  string `json:"departure_airport"`
+	ArrivalAirport    string `json:"arrival_airport"`
+	DepartureDateTime string `json:"departure_date_time"`
+	ArrivalDateTime   string `json:"arrival_date_time"`
+	ActiveTickets     int    `json:"active_tickets"`
+}
+
+// FleetAvailabilityQuery selects the days shown in the fleet availability calendar.
+type FleetAvailabilityQuery struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"` // Format: "YYYY-MM-DD", defaults to today
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`   // Format: "YYYY-MM-DD", inclusive
+}
+
+type PlaneDayStatus string
+
+const (
+	PlaneDayAvailable   PlaneDayStatus = "AVAILABLE"   // No flights or maintenance
+	PlaneDayScheduled   PlaneDayStatus = "SCHEDULED"   // Flying at least one flight
+	PlaneDayMaintenance PlaneDayStatus = "MAINTENANCE" // In maintenance for part or all of the day
+	PlaneDayRetired     PlaneDayStatus = "RETIRED"     // Retired from the fleet
+)
+
+type FleetAvailabilityResponse struct {
+	From   string              `json:"from"`
+	To     string              `json:"to"`
+	Planes []PlaneAvailability `json:"planes"`
+}
+
+type PlaneAvailability struct {
+	PlaneCode string                 `json:"plane_code"`
+	PlaneName string                 `json:"plane_name"`
+	Days      []PlaneDayAvailability `json:"days"`
+}
+
+type PlaneDayAvailability struct {
+	Date        string         `json:"date"`
+	Status      PlaneDayStatus `json:"status"`
+	Flights     []string       `json:"flights,omitempty"`
+	Maintenance []string       `json:"maintenance,omitempty"` // Reasons of the windows open that day
+}
@@ -0,0 +1,90 @@
+package dto
+
+import "github.com/aprilboiz/flight-management/internal/models"
+
+// ParameterRequest creates a new parameter version. Parameters left out keep the value of the
+// version being replaced.
+type ParameterRequest struct {
+	NumberOfAirports            *int `json:"number_of_airports" binding:"omitempty,min=2"`
+	MinFlightDuration           *int `json:"min_flight_duration" binding:"omitempty,min=1"` // Minutes
+	MaxIntermediateStops        *int `json:"max_intermediate_stops" binding:"omitempty,min=0"`
+	MinIntermediateStopDuration *int `json:"min_intermediate_stop_duration" binding:"omitempty,min=0"` // Minutes
+	MaxIntermediateStopDuration *int `json:"max_intermediate_stop_duration" binding:"omitempty,min=0"` // Minutes
+	MaxTicketClasses            *int `json:"max_ticket_classes" binding:"omitempty,min=1"`
+	LatestTicketPurchaseTime    *int `json:"latest_ticket_purchase_time" binding:"omitempty,min=0"` // Days before departure
+	TicketCancellationTime      *int `json:"ticket_cancellation_time" binding:"omitempty,min=0"`    // Days before departure
+
+	EffectiveFrom string `json:"effective_from"` // Local date time (YYYY-MM-DD HH:MM:SS), immediately when empty
+	Comment       string `json:"comment" binding:"max=500"`
+}
+
+// ParameterChangeQuery applies a parameter change even when existing flights or routes break
+// its rules.
+type ParameterChangeQuery struct {
+	Force bool `form:"force"`
+}
+
+type ParameterVersionResponse struct {
+	Version         int                      `json:"version"`
+	Status          string                   `json:"status"` // SCHEDULED, IN_FORCE or SUPERSEDED
+	EffectiveFrom   string                   `json:"effective_from"`
+	CreatedBy       string                   `json:"created_by"`
+	CreatedAt       string                   `json:"created_at"`
+	Comment         string                   `json:"comment,omitempty"`
+	RestoredVersion *int                     `json:"restored_version,omitempty"`
+	Changes         []models.ParameterChange `json:"changes"`
+	Impact          *ParameterImpactResponse `json:"impact,omitempty"` // What a forced change broke
+
+	NumberOfAirports            int `json:"number_of_airports"`
+	MinFlightDuration           int `json:"min_flight_duration"`
+	MaxIntermediateStops        int `json:"max_intermediate_stops"`
+	MinIntermediateStopDuration int `json:"min_intermediate_stop_duration"`
+	MaxIntermediateStopDuration int `json:"max_intermediate_stop_duration"`
+	MaxTicketClasses            int `json:"max_ticket_classes"`
+	LatestTicketPurchaseTime    int `json:"latest_ticket_purchase_time"`
+	TicketCancellationTime      int `json:"ticket_cancellation_time"`
+}
+
+// ParameterImpactResponse lists the existing data that breaks the rules of a parameter change
+// but not those of the version it replaces.
+type ParameterImpactResponse struct {
+	Violations      []string                `json:"violations"` // Limits already exceeded, e.g. the number of airports
+	Routes          []ParameterRouteImpact  `json:"routes"`
+	Flights         []ParameterFlightImpact `json:"flights"`
+	Tickets         []ParameterTicketImpact `json:"tickets"` // Deadlines that pass when the change takes effect
+	AffectedFlights int                     `json:"affected_flights"`
+	AffectedTickets int                     `json:"affected_tickets"`
+}
+
+type ParameterRouteImpact struct {
+	Route      string   `json:"route"`
+	Violations []string `json:"violations"`
+}
+
+type ParameterFlightImpact struct {
+	FlightCode        string   `json:"flight_code"`
+	DepartureDateTime string   `json:"departure_date_time"`
+	Violations        []string `json:"violations"`
+	TicketIDs         []uint   `json:"ticket_ids"` // Active tickets on the flight
+}
+
+type ParameterTicketImpact struct {
+	TicketID   uint     `json:"ticket_id"`
+	FlightCode string   `json:"flight_code"`
+	Violations []string `json:"violations"`
+}
+
+// EffectiveParametersResponse shows the parameters a flight is held to and where each value
+// comes from.
+type EffectiveParametersResponse struct {
+	FlightCode string               `json:"flight_code"`
+	Route      string               `json:"route,omitempty"` // Left out for flights scheduled before routes existed
+	Version    int                  `json:"version"`         // Parameter version in force
+	Parameters []EffectiveParameter `json:"parameters"`
+}
+
+type EffectiveParameter struct {
+	Name   string `json:"name"`
+	Value  int    `json:"value"`
+	Source string `json:"source"` // GLOBAL, ROUTE or FLIGHT
+}
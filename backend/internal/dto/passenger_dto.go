@@ -0,0 +1,36 @@
+package dto
+
+import "github.com/aprilboiz/flight-management/internal/models"
+
+type TravelDocumentDTO struct {
+	DocumentType   models.DocumentType `json:"document_type" binding:"required,oneof=PASSPORT ID_CARD"`
+	DocumentNumber string              `json:"document_number" binding:"required"`
+	IssuingCountry string              `json:"issuing_country"`
+	ExpiryDate     string              `json:"expiry_date,omitempty"` // Format: "YYYY-MM-DD"
+}
+
+type PassengerRequest struct {
+	FullName    string              `json:"full_name" binding:"required"`
+	DateOfBirth string              `json:"date_of_birth"` // Format: "YYYY-MM-DD"
+	Nationality string              `json:"nationality"`
+	PhoneNumber string              `json:"phone_number"`
+	Email       string              `json:"email" binding:"omitempty,email"`
+	Documents   []TravelDocumentDTO `json:"documents" binding:"dive"`
+}
+
+type PassengerResponse struct {
+	ID          uint                `json:"id"`
+	FullName    string              `json:"full_name"`
+	DateOfBirth string              `json:"date_of_birth,omitempty"`
+	Nationality string              `json:"nationality,omitempty"`
+	PhoneNumber string              `json:"phone_number,omitempty"`
+	Email       string              `json:"email,omitempty"`
+	Documents   []TravelDocumentDTO `json:"documents"`
+}
+
+type PassengerHistoryResponse struct {
+	Passenger    PassengerResponse `json:"passenger"`
+	Tickets      []TicketResponse  `json:"tickets"`
+	TotalTickets int               `json:"total_tickets"`
+	TotalSpent   float64           `json:"total_spent"`
+}
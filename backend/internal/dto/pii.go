@@ -0,0 +1,55 @@
+package dto
+
+import "strings"
+
+type PIIRotationResponse struct {
+	ActiveKeyID           string `json:"active_key_id"`
+	ReencryptedTickets    int    `json:"reencrypted_tickets"`
+	ReencryptedPassengers int    `json:"reencrypted_passengers"`
+	ReencryptedDocuments  int    `json:"reencrypted_documents"`
+}
+
+// MaskPII hides the passenger's identifiers, keeping only enough to recognize them.
+func (t *TicketResponse) MaskPII() {
+	t.IDCard = maskValue(t.IDCard, 4)
+	t.PhoneNumber = maskValue(t.PhoneNumber, 3)
+	t.Email = maskEmail(t.Email)
+}
+
+func (p *PassengerResponse) MaskPII() {
+	p.PhoneNumber = maskValue(p.PhoneNumber, 3)
+	p.Email = maskEmail(p.Email)
+	for i := range p.Documents {
+		p.Documents[i].DocumentNumber = maskValue(p.Documents[i].DocumentNumber, 4)
+	}
+}
+
+func (h *PassengerHistoryResponse) MaskPII() {
+	h.Passenger.MaskPII()
+	for i := range h.Tickets {
+		h.Tickets[i].MaskPII()
+	}
+}
+
+// maskValue replaces all but the last visible characters with asterisks.
+// Values too short to keep anything hidden are masked entirely.
+func maskValue(value string, visible int) string {
+	runes := []rune(value)
+	if len(runes) <= visible*2 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+// maskEmail keeps the first character of the local part and the domain.
+func maskEmail(email string) string {
+	local, domain, found := strings.Cut(email, "@")
+	if !found {
+		return maskValue(email, 0)
+	}
+	runes := []rune(local)
+	if len(runes) <= 1 {
+		return "*@" + domain
+	}
+	return string(runes[:1]) + strings.Repeat("*", len(runes)-1) + "@" + domain
+}
@@ -1,17 +1,84 @@
 package dto
 
+import "github.com/aprilboiz/flight-management/internal/models"
+
+type PlaneRequest struct {
+	PlaneCode    string         `json:"plane_code" binding:"required"`
+	PlaneName    string         `json:"plane_name" binding:"required"`
+	AircraftType string         `json:"aircraft_type,omitempty"` // New planes take their seats from the type's template
+	SeatLayout   *SeatLayoutDTO `json:"seat_layout,omitempty"`   // Regenerates the seats when present
+}
+
+type AircraftTypeRequest struct {
+	TypeCode       string         `json:"type_code" binding:"required,max=10"`
+	Manufacturer   string         `json:"manufacturer" binding:"required"`
+	Model          string         `json:"model" binding:"required"`
+	RangeKm        int            `json:"range_km" binding:"required,min=1"`
+	CruiseSpeedKmh int            `json:"cruise_speed_kmh" binding:"required,min=1"`
+	SeatLayout     *SeatLayoutDTO `json:"seat_layout,omitempty"` // Default seat configuration for new planes
+}
+
+type AircraftTypeResponse struct {
+	TypeCode       string         `json:"type_code"`
+	Manufacturer   string         `json:"manufacturer"`
+	Model          string         `json:"model"`
+	RangeKm        int            `json:"range_km"`
+	CruiseSpeedKmh int            `json:"cruise_speed_kmh"`
+	SeatLayout     *SeatLayoutDTO `json:"seat_layout,omitempty"`
+}
+
+// SeatLayoutDTO describes the seats to generate for a plane. Seat numbers are the row
+// followed by the column letter, e.g. "12C".
+type SeatLayoutDTO struct {
+	Columns      []string         `json:"columns" binding:"required,min=1,dive,len=1,alpha"`
+	AislesAfter  []string         `json:"aisles_after" binding:"dive,len=1,alpha"`
+	Cabins       []CabinLayoutDTO `json:"cabins" binding:"required,min=1,dive"`
+	BlockedSeats []string         `json:"blocked_seats"`
+
+	ExitRows         []int    `json:"exit_rows,omitempty" binding:"dive,min=1"`
+	ExtraLegroomRows []int    `json:"extra_legroom_rows,omitempty" binding:"dive,min=1"`
+	BassinetSeats    []string `json:"bassinet_seats,omitempty"`
+	// Surcharges are keyed by WINDOW, AISLE, EXIT_ROW, EXTRA_LEGROOM or BASSINET
+	Surcharges map[models.SeatAttribute]float64 `json:"surcharges,omitempty" binding:"dive,keys,oneof=WINDOW AISLE EXIT_ROW EXTRA_LEGROOM BASSINET,endkeys,min=0"`
+}
+
+type CabinLayoutDTO struct {
+	TicketClass string   `json:"ticket_class" binding:"required"`
+	FromRow     int      `json:"from_row" binding:"required,min=1"`
+	ToRow       int      `json:"to_row" binding:"required,gtefield=FromRow"`
+	Columns     []string `json:"columns,omitempty" binding:"dive,len=1,alpha"`
+}
+
 type PlaneResponse struct {
-	PlaneCode string `json:"plane_code"`
-	PlaneName string `json:"plane_name"`
+	PlaneCode    string `json:"plane_code"`
+	PlaneName    string `json:"plane_name"`
+	AircraftType string `json:"aircraft_type,omitempty"`
+	RetiredAt    string `json:"retired_at,omitempty"`
 }
 
 type PlaneResponseDetails struct {
-	PlaneCode string         `json:"plane_code"`
-	PlaneName string         `json:"plane_name"`
-	Seats     []SeatResponse `json:"seats"`
+	PlaneCode    string                `json:"plane_code"`
+	PlaneName    string                `json:"plane_name"`
+	AircraftType *AircraftTypeResponse `json:"aircraft_type,omitempty"`
+	RetiredAt    string                `json:"retired_at,omitempty"`
+	SeatLayout   *SeatLayoutDTO        `json:"seat_layout,omitempty"`
+	Seats        []SeatResponse        `json:"seats"`
 }
 
 type SeatResponse struct {
 	SeatNumber  string `json:"seat_number"`
 	TicketClass string `json:"ticket_class"`
+	SeatAttributes
+}
+
+// SeatAttributes describe where a seat is and what it offers.
+type SeatAttributes struct {
+	Row          int                 `json:"row,omitempty"`
+	Column       string              `json:"column,omitempty"`
+	Position     models.SeatPosition `json:"position,omitempty"`
+	IsExitRow    bool                `json:"is_exit_row"`
+	ExtraLegroom bool                `json:"extra_legroom"`
+	HasBassinet  bool                `json:"has_bassinet"`
+	IsBlocked    bool                `json:"is_blocked"`
+	Surcharge    float64             `json:"surcharge"`
 }
@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/aprilboiz/flight-management/internal/models"
+
+// ErasureRequestRequest identifies the data subject by email, ID card number or both.
+type ErasureRequestRequest struct {
+	Email  string `json:"email" binding:"required_without=IDCard,omitempty,email"`
+	IDCard string `json:"id_card" binding:"required_without=Email"`
+}
+
+type ErasureRequestResponse struct {
+	ID                   uint                        `json:"id"`
+	Status               models.ErasureRequestStatus `json:"status"`
+	RequestedBy          string                      `json:"requested_by"`
+	TicketsAnonymized    int                         `json:"tickets_anonymized"`
+	PendingTickets       int64                       `json:"pending_tickets"` // Matching tickets still inside the hold period
+	PassengersAnonymized int                         `json:"passengers_anonymized,omitempty"`
+	CreatedAt            string                      `json:"created_at"`
+	CompletedAt          string                      `json:"completed_at,omitempty"`
+}
+
+// PassengerDataExport is everything held about a data subject, as returned to them on request.
+type PassengerDataExport struct {
+	GeneratedAt string            `json:"generated_at"`
+	Tickets     []TicketExport    `json:"tickets"`
+	Passengers  []PassengerExport `json:"passengers"`
+}
+
+type TicketExport struct {
+	TicketResponse
+	TicketClass       string `json:"ticket_class"`
+	DepartureAirport  string `json:"departure_airport"`
+	ArrivalAirport    string `json:"arrival_airport"`
+	DepartureDateTime string `json:"departure_date_time"`
+	AnonymizedAt      string `json:"anonymized_at,omitempty"`
+}
+
+type PassengerExport struct {
+	PassengerResponse
+	LoyaltyAccount      *LoyaltyAccountResponse       `json:"loyalty_account,omitempty"`
+	LoyaltyTransactions []*LoyaltyTransactionResponse `json:"loyalty_transactions,omitempty"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/aprilboiz/flight-management/internal/models"
+
+// CreateRoleRequest adds a role. Names are upper case letters, digits and underscores.
+type CreateRoleRequest struct {
+	Name        models.Role         `json:"name" binding:"required,max=50"`
+	Description string              `json:"description"`
+	Permissions []models.Permission `json:"permissions" binding:"dive,required"`
+}
+
+// RoleRequest replaces the description and permissions of a role.
+type RoleRequest struct {
+	Description string              `json:"description"`
+	Permissions []models.Permission `json:"permissions" binding:"dive,required"`
+}
+
+type RoleResponse struct {
+	Name        models.Role         `json:"name"`
+	Description string              `json:"description"`
+	BuiltIn     bool                `json:"built_in"`
+	Permissions []models.Permission `json:"permissions"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+// RouteRequest approves flights between two airports in one direction. The parameter overrides
+// are optional; a missing one keeps the global parameter.
+type RouteRequest struct {
+	DepartureAirport     string   `json:"departure_airport" binding:"required,len=3"`
+	ArrivalAirport       string   `json:"arrival_airport" binding:"required,len=3,nefield=DepartureAirport"`
+	DefaultDuration      int      `json:"default_duration" binding:"required,min=1"` // Minutes
+	DefaultBasePrice     float64  `json:"default_base_price" binding:"required,gt=0"`
+	AllowedAircraftTypes []string `json:"allowed_aircraft_types" binding:"dive,required,max=10"` // Any type when empty
+
+	MinFlightDuration           *int `json:"min_flight_duration" binding:"omitempty,min=1"`
+	MaxIntermediateStops        *int `json:"max_intermediate_stops" binding:"omitempty,min=0"`
+	MinIntermediateStopDuration *int `json:"min_intermediate_stop_duration" binding:"omitempty,min=0"`
+	MaxIntermediateStopDuration *int `json:"max_intermediate_stop_duration" binding:"omitempty,min=0"`
+	LatestTicketPurchaseTime    *int `json:"latest_ticket_purchase_time" binding:"omitempty,min=0"` // Days before departure
+	TicketCancellationTime      *int `json:"ticket_cancellation_time" binding:"omitempty,min=0"`    // Days before departure
+}
+
+type RouteResponse struct {
+	ID                   uint     `json:"id"`
+	DepartureAirport     string   `json:"departure_airport"`
+	ArrivalAirport       string   `json:"arrival_airport"`
+	DistanceKm           float64  `json:"distance_km,omitempty"`
+	DefaultDuration      int      `json:"default_duration"`
+	DefaultBasePrice     float64  `json:"default_base_price"`
+	AllowedAircraftTypes []string `json:"allowed_aircraft_types"`
+
+	MinFlightDuration           *int `json:"min_flight_duration,omitempty"`
+	MaxIntermediateStops        *int `json:"max_intermediate_stops,omitempty"`
+	MinIntermediateStopDuration *int `json:"min_intermediate_stop_duration,omitempty"`
+	MaxIntermediateStopDuration *int `json:"max_intermediate_stop_duration,omitempty"`
+	LatestTicketPurchaseTime    *int `json:"latest_ticket_purchase_time,omitempty"`
+	TicketCancellationTime      *int `json:"ticket_cancellation_time,omitempty"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// RouteNetworkQuery narrows the network to the routes an aircraft type may fly.
+type RouteNetworkQuery struct {
+	AircraftType string `form:"aircraft_type" binding:"omitempty,max=10"`
+}
+
+// RouteNetworkResponse is the route network as a directed graph: airports are the nodes and
+// routes the edges between them.
+type RouteNetworkResponse struct {
+	Nodes []RouteNetworkNode `json:"nodes"`
+	Edges []RouteNetworkEdge `json:"edges"`
+}
+
+type RouteNetworkNode struct {
+	AirportCode string   `json:"airport_code"`
+	AirportName string   `json:"airport_name"`
+	CityName    string   `json:"city_name"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	Outbound    int      `json:"outbound"` // Routes departing from the airport
+	Inbound     int      `json:"inbound"`  // Routes arriving at the airport
+}
+
+type RouteNetworkEdge struct {
+	RouteID              uint     `json:"route_id"`
+	From                 string   `json:"from"`
+	To                   string   `json:"to"`
+	DistanceKm           float64  `json:"distance_km,omitempty"`
+	DefaultDuration      int      `json:"default_duration"`
+	AllowedAircraftTypes []string `json:"allowed_aircraft_types"`
+}
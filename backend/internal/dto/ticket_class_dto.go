@@ -0,0 +1,16 @@
+package dto
+
+type TicketClassRequest struct {
+	Name            string  `json:"name" binding:"required,max=50"`
+	PricePercentage float64 `json:"price_percentage" binding:"required,gt=0"` // Multiplier of the base price, e.g. 1.05
+	CabinCode       string  `json:"cabin_code" binding:"required,oneof=F J W Y"`
+	DisplayOrder    int     `json:"display_order" binding:"min=0"`
+}
+
+type TicketClassResponse struct {
+	ID              uint    `json:"id"`
+	Name            string  `json:"name"`
+	PricePercentage float64 `json:"price_percentage"`
+	CabinCode       string  `json:"cabin_code"`
+	DisplayOrder    int     `json:"display_order"`
+}
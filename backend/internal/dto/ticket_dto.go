@@ -2,18 +2,54 @@ package dto
 
 import "github.com/aprilboiz/flight-management/internal/models"
 
+// TicketRequest books a seat for a passenger. When SeatNumber is omitted a free seat of
+// TicketClass is assigned, honoring SeatPreference where possible.
 type TicketRequest struct {
-	FlightCode  string             `json:"flight_code" binding:"required"`
-	SeatNumber  string             `json:"seat_number" binding:"required"`
-	FullName    string             `json:"full_name" binding:"required"`
-	IDCard      string             `json:"id_card" binding:"required"`
-	PhoneNumber string             `json:"phone_number" binding:"required"`
-	Email       string             `json:"email" binding:"required,email"`
-	BookingType models.BookingType `json:"booking_type" binding:"required,oneof=TICKET PLACE_ORDER"`
+	FlightCode     string              `json:"flight_code" binding:"required"`
+	SeatNumber     string              `json:"seat_number" binding:"required_without=TicketClass"`
+	TicketClass    string              `json:"ticket_class,omitempty"`
+	SeatPreference models.SeatPosition `json:"seat_preference,omitempty" binding:"omitempty,oneof=WINDOW AISLE MIDDLE"`
+	BookingType    models.BookingType  `json:"booking_type" binding:"required,oneof=TICKET PLACE_ORDER"`
+	TicketPassengerDTO
+}
+
+// TicketPassengerDTO holds the passenger details of a booking. When PassengerID refers to a
+// saved passenger profile, the contact fields may be omitted and are taken from the profile;
+// an IDCard given with it must be one of the profile's travel documents.
+type TicketPassengerDTO struct {
+	PassengerID *uint  `json:"passenger_id,omitempty"`
+	FullName    string `json:"full_name" binding:"required_without=PassengerID"`
+	IDCard      string `json:"id_card" binding:"required_without=PassengerID"`
+	PhoneNumber string `json:"phone_number" binding:"required_without=PassengerID"`
+	Email       string `json:"email" binding:"required_without=PassengerID,omitempty,email"`
+	// Passport is required when the flight crosses a country border
+	Passport *PassportDTO `json:"passport,omitempty"`
+	// RedeemPoints spends the passenger's loyalty points against the fare
+	RedeemPoints int `json:"redeem_points,omitempty" binding:"omitempty,min=1"`
+}
+
+// GroupTicketRequest books seats of one ticket class for several passengers travelling
+// together. The passengers are seated side by side where the cabin allows it.
+type GroupTicketRequest struct {
+	FlightCode  string              `json:"flight_code" binding:"required"`
+	TicketClass string              `json:"ticket_class" binding:"required"`
+	BookingType models.BookingType  `json:"booking_type" binding:"required,oneof=TICKET PLACE_ORDER"`
+	Passengers  []GroupPassengerDTO `json:"passengers" binding:"required,min=2,max=9,dive"`
+}
+
+type GroupPassengerDTO struct {
+	SeatPreference models.SeatPosition `json:"seat_preference,omitempty" binding:"omitempty,oneof=WINDOW AISLE MIDDLE"`
+	TicketPassengerDTO
+}
+
+type PassportDTO struct {
+	Number      string `json:"number"`
+	Nationality string `json:"nationality"`
+	ExpiryDate  string `json:"expiry_date"` // Format: "YYYY-MM-DD"
 }
 
 type TicketStatusUpdateRequest struct {
-	Status models.TicketStatus `json:"status" binding:"required,oneof=ACTIVE CANCELLED EXPIRED"`
+	Status models.TicketStatus `json:"status" binding:"required,oneof=ACTIVE CANCELLED EXPIRED USED REFUNDED"`
 }
 
 type TicketResponse struct {
@@ -27,6 +63,14 @@ type TicketResponse struct {
 	Email        string              `json:"email"`
 	TicketStatus models.TicketStatus `json:"ticket_status"`
 	BookingType  models.BookingType  `json:"booking_type"`
+	PassengerID  *uint               `json:"passenger_id,omitempty"`
+	// BookingReference is shared by the tickets booked together
+	BookingReference string `json:"booking_reference,omitempty"`
+}
+
+type GroupTicketResponse struct {
+	BookingReference string            `json:"booking_reference"`
+	Tickets          []*TicketResponse `json:"tickets"`
 }
 
 type TicketStatusesResponse struct {
@@ -5,18 +5,86 @@ import (
 )
 
 type UserResponse struct {
+	ID         uint        `json:"id"`
+	Username   string      `json:"username"`
+	Email      string      `json:"email"`
+	Role       models.Role `json:"role"`
+	DisabledAt string      `json:"disabled_at,omitempty"`
+	CreatedAt  string      `json:"created_at"`
+	UpdatedAt  string      `json:"updated_at"`
+}
+
+// UserSearchQuery filters the user list. Search matches part of the username or email.
+type UserSearchQuery struct {
+	Search string      `form:"search"`
+	Role   models.Role `form:"role"`
+}
+
+// CreateUserRequest creates a user with any role, unlike self-registration.
+type CreateUserRequest struct {
+	Username string      `json:"username" binding:"required"`
+	Password string      `json:"password" binding:"required"`
+	Email    string      `json:"email" binding:"required,email"`
+	Role     models.Role `json:"role" binding:"required,max=50"`
+}
+
+type UserRoleRequest struct {
+	Role models.Role `json:"role" binding:"required,max=50"`
+}
+
+// MeResponse describes the caller, with the permissions of their role so that clients can hide
+// what they are not allowed to do.
+type MeResponse struct {
+	User        UserResponse        `json:"user"`
+	Permissions []models.Permission `json:"permissions"`
+}
+
+type RegisterRequest struct {
+	Username        string `json:"username" binding:"required"`
+	Password        string `json:"password" binding:"required"`
+	Email           string `json:"email" binding:"required,email"`
+	InvitationToken string `json:"invitation_token"`
+}
+
+// InvitationRequest issues an invitation. Without an email anyone holding the token may
+// redeem it; without expires_in_hours it is valid for the configured time.
+type InvitationRequest struct {
+	Email          string      `json:"email" binding:"omitempty,email"`
+	Role           models.Role `json:"role" binding:"required,max=50"`
+	ExpiresInHours int         `json:"expires_in_hours" binding:"omitempty,min=1"`
+}
+
+// InvitationResponse describes an invitation. The token is only returned when the invitation
+// is issued.
+type InvitationResponse struct {
 	ID        uint        `json:"id"`
-	Username  string      `json:"username"`
-	Email     string      `json:"email"`
+	Token     string      `json:"token,omitempty"`
+	Email     string      `json:"email,omitempty"`
 	Role      models.Role `json:"role"`
+	Status    string      `json:"status"` // PENDING, USED or EXPIRED
+	CreatedBy string      `json:"created_by"`
 	CreatedAt string      `json:"created_at"`
-	UpdatedAt string      `json:"updated_at"`
+	ExpiresAt string      `json:"expires_at"`
+	UsedAt    string      `json:"used_at,omitempty"`
+	UsedBy    string      `json:"used_by,omitempty"`
 }
 
-type RegisterRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
+// ChangePasswordRequest changes the caller's password. The new password must meet the
+// password policy.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// PasswordResetRequest asks for a reset link to be mailed to the account with this email.
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PasswordResetConfirmRequest sets a new password with the token of a reset link.
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
 
 type LoginRequest struct {
@@ -24,7 +92,16 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// AuthResponse carries a short-lived access token in token and the refresh token to exchange
+// for the next pair.
 type AuthResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token                 string       `json:"token"`
+	TokenExpiresAt        string       `json:"token_expires_at"`
+	RefreshToken          string       `json:"refresh_token"`
+	RefreshTokenExpiresAt string       `json:"refresh_token_expires_at"`
+	User                  UserResponse `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
@@ -3,15 +3,17 @@ package middleware
 import (
 	"strings"
 
-	"slices"
-
 	"github.com/aprilboiz/flight-management/internal/exceptions"
-	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/pkg/auth"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// TokenValidator checks that a valid access token has not been revoked since it was issued.
+type TokenValidator interface {
+	ValidateAccessToken(claims *auth.Claims) error
+}
+
+func AuthMiddleware(validator TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -36,31 +38,18 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Set user information in the context
-		c.Set("userID", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
-
-		c.Next()
-	}
-}
-
-func RoleMiddleware(roles ...models.Role) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		userRole, exists := c.Get("role")
-		if !exists {
-			_ = c.Error(exceptions.NewAppError(exceptions.UNAUTHORIZED, "User role not found in context", nil))
+		// Reject tokens of revoked sessions and of users disabled or given another role since
+		if err := validator.ValidateAccessToken(claims); err != nil {
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
 
-		hasRole := slices.Contains(roles, userRole.(models.Role))
-
-		if !hasRole {
-			_ = c.Error(exceptions.NewAppError(exceptions.FORBIDDEN, "Insufficient permissions", nil))
-			c.Abort()
-			return
-		}
+		// Set user information in the context
+		c.Set("userID", claims.UserID)
+		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Set("sessionID", claims.SessionID)
 
 		c.Next()
 	}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionResolver returns the permissions a role grants.
+type PermissionResolver interface {
+	RolePermissions(role models.Role) ([]models.Permission, error)
+}
+
+// PermissionLoader puts the permissions of the caller's role in the context. It runs after
+// AuthMiddleware and reads them on every request, so permission changes apply immediately.
+func PermissionLoader(resolver PermissionResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists {
+			_ = c.Error(exceptions.NewAppError(exceptions.UNAUTHORIZED, "User role not found in context", nil))
+			c.Abort()
+			return
+		}
+
+		permissions, err := resolver.RolePermissions(role.(models.Role))
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Set("permissions", permissions)
+
+		c.Next()
+	}
+}
+
+// PermissionMiddleware lets through callers holding all the given permissions.
+func PermissionMiddleware(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, permission := range permissions {
+			if !HasPermission(c, permission) {
+				_ = c.Error(exceptions.NewAppError(exceptions.FORBIDDEN, "Insufficient permissions",
+					map[string]any{"required": permission}))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// HasPermission reports whether the caller holds a permission.
+func HasPermission(c *gin.Context, permission models.Permission) bool {
+	permissions, exists := c.Get("permissions")
+	if !exists {
+		return false
+	}
+	held, ok := permissions.([]models.Permission)
+	return ok && slices.Contains(held, permission)
+}
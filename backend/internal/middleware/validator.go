@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"reflect"
 
 	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/pkg/validator"
@@ -9,10 +10,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// ValidateRequest binds the JSON body into a fresh instance of the model's type for every
+// request, so optional fields never carry over from a previous request.
 func ValidateRequest(model any) gin.HandlerFunc {
 	v := validator.New()
+	modelType := reflect.TypeOf(model).Elem()
 
 	return func(c *gin.Context) {
+		model := reflect.New(modelType).Interface()
 		if err := c.ShouldBindJSON(model); err != nil {
 			response := exceptions.NewErrorResponse(
 				http.StatusBadRequest,
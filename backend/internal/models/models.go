@@ -1,8 +1,10 @@
 package models
 
 import (
+	"errors"
 	"time"
 
+	"github.com/aprilboiz/flight-management/pkg/encryption"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -16,6 +18,75 @@ const (
 	RoleStaff      Role = "STAFF"
 )
 
+// Permission names an action that roles grant, as "<resource>:<action>". Reading flights,
+// airports and the other reference data only requires being logged in.
+type Permission string
+
+const (
+	PermissionFlightsWrite       Permission = "flights:write"
+	PermissionFleetWrite         Permission = "fleet:write" // Planes, aircraft types and maintenance
+	PermissionTicketClassesWrite Permission = "ticket_classes:write"
+	PermissionRoutesWrite        Permission = "routes:write"
+	PermissionAirportsWrite      Permission = "airports:write"
+	PermissionTicketsWrite       Permission = "tickets:write"
+	PermissionTicketsReadPII     Permission = "tickets:read_pii" // Unmasked passenger data
+	PermissionPIIRotate          Permission = "pii:rotate"
+	PermissionPassengersWrite    Permission = "passengers:write"
+	PermissionLoyaltyWrite       Permission = "loyalty:write"
+	PermissionReportsView        Permission = "reports:view"
+	PermissionPrivacyManage      Permission = "privacy:manage"
+	PermissionParamsManage       Permission = "params:manage"
+	PermissionUsersManage        Permission = "users:manage"
+	PermissionInvitationsManage  Permission = "invitations:manage"
+	PermissionRolesManage        Permission = "roles:manage"
+	PermissionAuditView          Permission = "audit:view"
+)
+
+// AllPermissions lists every permission. Super admins always hold all of them.
+var AllPermissions = []Permission{
+	PermissionFlightsWrite,
+	PermissionFleetWrite,
+	PermissionTicketClassesWrite,
+	PermissionRoutesWrite,
+	PermissionAirportsWrite,
+	PermissionTicketsWrite,
+	PermissionTicketsReadPII,
+	PermissionPIIRotate,
+	PermissionPassengersWrite,
+	PermissionLoyaltyWrite,
+	PermissionReportsView,
+	PermissionPrivacyManage,
+	PermissionParamsManage,
+	PermissionUsersManage,
+	PermissionInvitationsManage,
+	PermissionRolesManage,
+	PermissionAuditView,
+}
+
+// DefaultRolePermissions are the permissions built-in roles start with.
+var DefaultRolePermissions = map[Role][]Permission{
+	RoleStaff: {
+		PermissionTicketsWrite,
+		PermissionPassengersWrite,
+		PermissionLoyaltyWrite,
+	},
+	RoleAdmin: {
+		PermissionFlightsWrite,
+		PermissionFleetWrite,
+		PermissionTicketClassesWrite,
+		PermissionRoutesWrite,
+		PermissionAirportsWrite,
+		PermissionTicketsWrite,
+		PermissionPassengersWrite,
+		PermissionLoyaltyWrite,
+		PermissionReportsView,
+		PermissionPrivacyManage,
+		PermissionInvitationsManage,
+		PermissionAuditView,
+	},
+	RoleSuperAdmin: AllPermissions,
+}
+
 // TicketStatus Ticket status constants
 type TicketStatus string
 
@@ -35,28 +106,122 @@ const (
 	BookingTypePlaceOrder BookingType = "PLACE_ORDER" // Temporary place order
 )
 
+// DocumentType Travel document type constants
+type DocumentType string
+
+const (
+	DocumentTypePassport DocumentType = "PASSPORT" // Passport issued by a country
+	DocumentTypeIDCard   DocumentType = "ID_CARD"  // National identity card
+)
+
+// LoyaltyTier Frequent-flyer tier constants, from lowest to highest
+type LoyaltyTier string
+
+const (
+	LoyaltyTierBasic    LoyaltyTier = "BASIC"
+	LoyaltyTierSilver   LoyaltyTier = "SILVER"
+	LoyaltyTierGold     LoyaltyTier = "GOLD"
+	LoyaltyTierPlatinum LoyaltyTier = "PLATINUM"
+)
+
+// LoyaltyTransactionType Points ledger entry type constants
+type LoyaltyTransactionType string
+
+const (
+	LoyaltyTransactionEarn           LoyaltyTransactionType = "EARN"            // Points earned for a used ticket
+	LoyaltyTransactionRedeem         LoyaltyTransactionType = "REDEEM"          // Points spent against a fare
+	LoyaltyTransactionEarnReversal   LoyaltyTransactionType = "EARN_REVERSAL"   // Earned points taken back after a refund
+	LoyaltyTransactionRedeemReversal LoyaltyTransactionType = "REDEEM_REVERSAL" // Redeemed points returned after a refund or cancellation
+)
+
+// ErrLedgerAppendOnly is returned when something tries to modify or remove a ledger entry
+var ErrLedgerAppendOnly = errors.New("loyalty ledger is append-only")
+
+type SeatPosition string
+
+const (
+	SeatPositionWindow SeatPosition = "WINDOW"
+	SeatPositionAisle  SeatPosition = "AISLE"
+	SeatPositionMiddle SeatPosition = "MIDDLE"
+)
+
+// SeatAttribute names a seat feature that can carry a surcharge.
+type SeatAttribute string
+
+const (
+	SeatAttributeWindow       SeatAttribute = "WINDOW"
+	SeatAttributeAisle        SeatAttribute = "AISLE"
+	SeatAttributeExitRow      SeatAttribute = "EXIT_ROW"
+	SeatAttributeExtraLegroom SeatAttribute = "EXTRA_LEGROOM"
+	SeatAttributeBassinet     SeatAttribute = "BASSINET"
+)
+
+// AircraftType holds the performance data and default cabin of an aircraft model.
+type AircraftType struct {
+	gorm.Model
+	TypeCode       string      `gorm:"unique;not null"` // ICAO type designator, e.g. A321
+	Manufacturer   string      `gorm:"not null"`
+	ModelName      string      `gorm:"not null"`
+	RangeKm        int         `gorm:"not null"`
+	CruiseSpeedKmh int         `gorm:"not null"`
+	SeatLayout     *SeatLayout `gorm:"serializer:json"` // Seat template for new planes of this type
+
+	Planes []Plane `gorm:"foreignKey:AircraftTypeID;references:ID"`
+}
+
 type Plane struct {
 	gorm.Model
-	PlaneCode string `gorm:"unique;not null"`
-	PlaneName string `gorm:"not null"`
+	PlaneCode      string      `gorm:"unique;not null"`
+	PlaneName      string      `gorm:"not null"`
+	AircraftTypeID *uint       `gorm:"index"`
+	SeatLayout     *SeatLayout `gorm:"serializer:json"` // Layout the seats were generated from, if any
+	RetiredAt      *time.Time  // Retired planes cannot be scheduled on new flights
+
+	AircraftType *AircraftType `gorm:"foreignKey:AircraftTypeID;references:ID"`
+	Seats        []Seat        `gorm:"foreignKey:PlaneID;references:ID"`
+}
+
+// SeatLayout describes a cabin configuration from which a plane's seats are generated.
+type SeatLayout struct {
+	Columns      []string      `json:"columns"`       // Seat letters from left to right, e.g. A B C D E F
+	AislesAfter  []string      `json:"aisles_after"`  // Columns directly followed by an aisle
+	Cabins       []CabinLayout `json:"cabins"`        // Row ranges and their ticket class
+	BlockedSeats []string      `json:"blocked_seats"` // Seats that cannot be sold, e.g. "12C"
+
+	ExitRows         []int                     `json:"exit_rows,omitempty"`
+	ExtraLegroomRows []int                     `json:"extra_legroom_rows,omitempty"`
+	BassinetSeats    []string                  `json:"bassinet_seats,omitempty"`
+	Surcharges       map[SeatAttribute]float64 `json:"surcharges,omitempty"` // Added to the fare of seats with the attribute
+}
 
-	Seats []Seat `gorm:"foreignKey:PlaneID;references:ID"`
+type CabinLayout struct {
+	TicketClass string   `json:"ticket_class"`
+	FromRow     int      `json:"from_row"`
+	ToRow       int      `json:"to_row"`
+	Columns     []string `json:"columns,omitempty"` // Subset of the layout columns when the cabin is narrower
 }
 
 type TicketClass struct {
 	gorm.Model
-	TicketClassName string  `gorm:"not null"`
-	PricePercentage float64 `gorm:"not null"`
+	TicketClassName string  `gorm:"not null"`                    // Unique among classes not deleted, ignoring case
+	PricePercentage float64 `gorm:"not null"`                    // Multiplier applied to the flight's base price
+	CabinCode       string  `gorm:"size:1;not null;default:'Y'"` // IATA cabin: F first, J business, W premium economy, Y economy
+	DisplayOrder    int     `gorm:"not null;default:0"`          // Classes are listed in ascending order
 
 	Seats []Seat `gorm:"foreignKey:TicketClassID;references:ID"`
 }
 
 type Airport struct {
 	gorm.Model
-	AirportCode string `gorm:"not null"`
-	AirportName string `gorm:"not null"`
-	CityName    string `gorm:"not null"`
-	CountryName string `gorm:"not null"`
+	AirportCode string   `gorm:"not null"` // IATA code, unique among airports not deleted
+	IcaoCode    *string  // Unique among airports not deleted
+	AirportName string   `gorm:"not null"`
+	CityName    string   `gorm:"not null"`
+	CountryName string   `gorm:"not null"`
+	CountryCode string   // ISO 3166-1 alpha-2 code, empty for airports entered without one
+	Latitude    *float64 // Decimal degrees, north positive
+	Longitude   *float64 // Decimal degrees, east positive
+	ElevationFt *int     // Feet above mean sea level
 
 	Flights []Flight `gorm:"foreignKey:DepartureAirportID;references:ID"`
 }
@@ -66,25 +231,76 @@ type Seat struct {
 	SeatNumber    string `gorm:"not null"`
 	PlaneID       uint   `gorm:"not null"`
 	TicketClassID uint   `gorm:"not null"`
+	RowNumber     int    // Zero for seats that were not generated from a layout
+	ColumnLetter  string
+	IsBlocked     bool `gorm:"not null;default:false"` // Blocked seats are never sold
+	Position      SeatPosition
+	IsExitRow     bool    `gorm:"not null;default:false"`
+	ExtraLegroom  bool    `gorm:"not null;default:false"`
+	HasBassinet   bool    `gorm:"not null;default:false"` // Bassinet attachment point for infants
+	Surcharge     float64 `gorm:"not null;default:0"`     // Added to the class fare for this seat
 
 	TicketClass TicketClass `gorm:"foreignKey:TicketClassID;references:ID"`
 	Plane       Plane       `gorm:"foreignKey:PlaneID;references:ID"`
 	Tickets     []Ticket    `gorm:"foreignKey:SeatID;references:ID"`
 }
 
+// MaintenanceWindow takes a plane out of service. Flights cannot be scheduled on the plane
+// while a window is open.
+type MaintenanceWindow struct {
+	gorm.Model
+	PlaneID   uint      `gorm:"not null;index"`
+	StartTime time.Time `gorm:"not null"`
+	EndTime   time.Time `gorm:"not null"`
+	Reason    string    `gorm:"not null"`
+	CreatedBy string
+
+	Plane Plane `gorm:"foreignKey:PlaneID;references:ID"`
+}
+
+// Route is an airport pair the airline is approved to fly, in one direction. Flights can only
+// be scheduled on a route, which supplies their default duration and price and may tighten the
+// global parameters.
+type Route struct {
+	gorm.Model
+	DepartureAirportID uint    `gorm:"not null"` // Unique with ArrivalAirportID among routes not deleted
+	ArrivalAirportID   uint    `gorm:"not null"`
+	DefaultDuration    int     `gorm:"not null"` // Minutes, used when a flight gives no duration
+	DefaultBasePrice   float64 `gorm:"not null"` // Used when a flight gives no base price
+
+	// Parameter overrides, nil when the global parameter applies
+	MinFlightDuration           *int
+	MaxIntermediateStops        *int
+	MinIntermediateStopDuration *int
+	MaxIntermediateStopDuration *int
+	LatestTicketPurchaseTime    *int
+	TicketCancellationTime      *int
+
+	DepartureAirport     Airport        `gorm:"foreignKey:DepartureAirportID;references:ID"`
+	ArrivalAirport       Airport        `gorm:"foreignKey:ArrivalAirportID;references:ID"`
+	AllowedAircraftTypes []AircraftType `gorm:"many2many:route_aircraft_types"` // Any type may fly the route when empty
+	Flights              []Flight       `gorm:"foreignKey:RouteID;references:ID"`
+}
+
 type Flight struct {
 	gorm.Model
 	FlightCode         string    `gorm:"unique;not null"`
 	PlaneID            uint      `gorm:"not null"`
+	RouteID            *uint     `gorm:"index"` // Nil for flights scheduled before routes existed
 	DepartureAirportID uint      `gorm:"not null"`
 	ArrivalAirportID   uint      `gorm:"not null"`
 	DepartureDateTime  time.Time `gorm:"not null"`
 	FlightDuration     int       `gorm:"not null"`
 	BasePrice          float64   `gorm:"not null"`
 
+	// Parameter overrides taking precedence over the route's, nil when the route's or global parameter applies
+	LatestTicketPurchaseTime *int
+	TicketCancellationTime   *int
+
 	DepartureAirport  Airport `gorm:"foreignKey:DepartureAirportID;references:ID"`
 	ArrivalAirport    Airport `gorm:"foreignKey:ArrivalAirportID;references:ID"`
 	Plane             Plane   `gorm:"foreignKey:PlaneID;references:ID"`
+	Route             *Route  `gorm:"foreignKey:RouteID;references:ID"`
 	IntermediateStops []IntermediateStop
 	Tickets           []Ticket
 }
@@ -101,43 +317,180 @@ type IntermediateStop struct {
 }
 
 type Ticket struct {
-	ID           uint         `gorm:"primaryKey"`
-	FlightID     uint         `gorm:"primaryKey"`
-	SeatID       uint         `gorm:"primaryKey"`
-	Price        float64      `gorm:"not null"`
-	FullName     string       `gorm:"not null"`
-	IDCard       string       `gorm:"not null"`
-	PhoneNumber  string       `gorm:"not null"`
-	Email        string       `gorm:"not null"`
-	TicketStatus TicketStatus `gorm:"not null;default:'ACTIVE'"` // Status of the ticket
-	BookingType  BookingType  `gorm:"not null;default:'TICKET'"` // Type of booking
+	ID               uint         `gorm:"primaryKey"`
+	FlightID         uint         `gorm:"primaryKey"`
+	SeatID           uint         `gorm:"primaryKey"`
+	Price            float64      `gorm:"not null"`
+	FullName         string       `gorm:"not null"`
+	IDCard           string       `gorm:"not null;serializer:encrypted"`
+	IDCardIndex      string       `gorm:"index"` // Blind index of IDCard for equality lookups
+	PhoneNumber      string       `gorm:"not null;serializer:encrypted"`
+	Email            string       `gorm:"not null;serializer:encrypted"`
+	EmailIndex       string       `gorm:"index"`                     // Blind index of Email for equality lookups
+	TicketStatus     TicketStatus `gorm:"not null;default:'ACTIVE'"` // Status of the ticket
+	BookingType      BookingType  `gorm:"not null;default:'TICKET'"` // Type of booking
+	PassengerID      *uint        `gorm:"index"`                     // Passenger profile the ticket was issued to
+	BookingReference string       `gorm:"index"`                     // Shared by the tickets booked together
+	AnonymizedAt     *time.Time   // Set once the passenger's personal data has been erased
+	CreatedAt        time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP"` // Booking time; the parameters in force then govern the ticket
 
-	Flight Flight `gorm:"foreignKey:FlightID;references:ID"`
-	Seat   Seat   `gorm:"foreignKey:SeatID;references:ID"`
+	Flight    Flight     `gorm:"foreignKey:FlightID;references:ID"`
+	Seat      Seat       `gorm:"foreignKey:SeatID;references:ID"`
+	Passenger *Passenger `gorm:"foreignKey:PassengerID;references:ID"`
+}
+
+// BeforeSave keeps the blind indexes in step with the encrypted values.
+func (t *Ticket) BeforeSave(tx *gorm.DB) error {
+	if t.IDCard != "" {
+		t.IDCardIndex = encryption.GetKeyring().BlindIndex(t.IDCard)
+	}
+	if t.Email != "" {
+		t.EmailIndex = encryption.GetKeyring().BlindIndex(t.Email)
+	}
+	return nil
 }
 
+// AnonymizedName replaces the passenger's name on anonymized tickets and profiles.
+const AnonymizedName = "ANONYMIZED"
+
+type ErasureRequestStatus string
+
+const (
+	ErasureRequestPending   ErasureRequestStatus = "PENDING"   // Some tickets are still inside the hold period
+	ErasureRequestCompleted ErasureRequestStatus = "COMPLETED" // Every matching ticket has been anonymized
+)
+
+// ErasureRequest records a passenger's request to have their personal data erased.
+// The subject is kept only as blind indexes, so the request itself holds no readable PII.
+type ErasureRequest struct {
+	gorm.Model
+	EmailIndex        string               `gorm:"index"`
+	IDCardIndex       string               `gorm:"index"`
+	Status            ErasureRequestStatus `gorm:"not null;default:'PENDING'"`
+	RequestedBy       string               `gorm:"not null"`
+	TicketsAnonymized int                  `gorm:"not null;default:0"`
+	CompletedAt       *time.Time
+}
+
+// Passenger is a traveler profile shared by all tickets issued to the same person.
+// Passengers are deduplicated by the number of their travel documents. Identifying details
+// are encrypted; the blind indexes allow exact lookups.
+type Passenger struct {
+	gorm.Model
+	FullName         string `gorm:"not null"` // Plaintext, like the name on tickets, so it can be searched
+	DateOfBirth      *time.Time
+	Nationality      string
+	PhoneNumber      string     `gorm:"serializer:encrypted"`
+	PhoneNumberIndex string     `gorm:"index"` // Blind index of PhoneNumber for equality lookups
+	Email            string     `gorm:"serializer:encrypted"`
+	EmailIndex       string     `gorm:"index"` // Blind index of Email for equality lookups
+	AnonymizedAt     *time.Time // Set once the profile's personal data has been erased
+
+	Documents []TravelDocument `gorm:"foreignKey:PassengerID;references:ID"`
+	Tickets   []Ticket         `gorm:"foreignKey:PassengerID;references:ID"`
+}
+
+// BeforeSave keeps the blind indexes in step with the encrypted values.
+func (p *Passenger) BeforeSave(tx *gorm.DB) error {
+	keyring := encryption.GetKeyring()
+	p.PhoneNumberIndex = keyring.BlindIndex(p.PhoneNumber)
+	p.EmailIndex = keyring.BlindIndex(p.Email)
+	return nil
+}
+
+// TravelDocument is a passport or ID card of a passenger. A document number belongs to one
+// passenger only, which is enforced on its blind index.
+type TravelDocument struct {
+	gorm.Model
+	PassengerID         uint         `gorm:"not null;index"`
+	DocumentType        DocumentType `gorm:"not null"`
+	DocumentNumber      string       `gorm:"not null;serializer:encrypted"`
+	DocumentNumberIndex string       `gorm:"index"` // Blind index of DocumentNumber for equality lookups
+	IssuingCountry      string
+	ExpiryDate          *time.Time
+
+	Passenger Passenger `gorm:"foreignKey:PassengerID;references:ID"`
+}
+
+// BeforeSave keeps the blind index in step with the encrypted document number.
+func (d *TravelDocument) BeforeSave(tx *gorm.DB) error {
+	d.DocumentNumberIndex = encryption.GetKeyring().BlindIndex(d.DocumentNumber)
+	return nil
+}
+
+// Parameter is a version of the business rules. Versions are never changed once saved: every
+// update, rollback and scheduled change adds a new one, and the version in force at a given
+// time is the latest to have taken effect by then.
 type Parameter struct {
 	gorm.Model                  `json:"-"`
-	NumberOfAirports            int    `gorm:"not null" json:"number_of_airports"`
-	MinFlightDuration           int    `gorm:"not null" json:"min_flight_duration"`
-	MaxIntermediateStops        int    `gorm:"not null" json:"max_intermediate_stops"`
-	MinIntermediateStopDuration int    `gorm:"not null" json:"min_intermediate_stop_duration"`
-	MaxIntermediateStopDuration int    `gorm:"not null" json:"max_intermediate_stop_duration"`
-	MaxTicketClasses            int    `gorm:"not null" json:"max_ticket_classes"`
-	LatestTicketPurchaseTime    int    `gorm:"not null" json:"latest_ticket_purchase_time"`
-	TicketCancellationTime      int    `gorm:"not null" json:"ticket_cancellation_time"`
-	lock                        string `gorm:"type:char(1);unique;not null;default:'X';check:lock='X'"`
+	Version                     int               `gorm:"uniqueIndex;not null;default:1" json:"version"` // The default numbers the row kept from before versioning
+	EffectiveFrom               time.Time         `gorm:"not null;index;default:CURRENT_TIMESTAMP" json:"effective_from"`
+	CreatedBy                   string            `json:"created_by"`
+	Comment                     string            `json:"comment,omitempty"`
+	RestoredVersion             *int              `json:"restored_version,omitempty"`               // Set on rollbacks to the version whose values were restored
+	Changes                     []ParameterChange `gorm:"serializer:json" json:"changes,omitempty"` // Differences from the version it replaced
+	NumberOfAirports            int               `gorm:"not null" json:"number_of_airports"`
+	MinFlightDuration           int               `gorm:"not null" json:"min_flight_duration"`
+	MaxIntermediateStops        int               `gorm:"not null" json:"max_intermediate_stops"`
+	MinIntermediateStopDuration int               `gorm:"not null" json:"min_intermediate_stop_duration"`
+	MaxIntermediateStopDuration int               `gorm:"not null" json:"max_intermediate_stop_duration"`
+	MaxTicketClasses            int               `gorm:"not null" json:"max_ticket_classes"`
+	LatestTicketPurchaseTime    int               `gorm:"not null" json:"latest_ticket_purchase_time"`
+	TicketCancellationTime      int               `gorm:"not null" json:"ticket_cancellation_time"`
+}
+
+// ParameterChange is the change of one business rule between two parameter versions.
+type ParameterChange struct {
+	Field    string `json:"field"` // JSON name of the parameter
+	OldValue int    `json:"old_value"`
+	NewValue int    `json:"new_value"`
+}
+
+// LoyaltyAccount is a frequent-flyer membership held by a passenger. The points balance is
+// not stored on the account; it is the running total of the account's ledger entries.
+type LoyaltyAccount struct {
+	gorm.Model
+	PassengerID  uint        `gorm:"uniqueIndex;not null"`
+	MemberNumber string      `gorm:"uniqueIndex;not null"`
+	Tier         LoyaltyTier `gorm:"not null;default:'BASIC'"`
+
+	Passenger    Passenger            `gorm:"foreignKey:PassengerID;references:ID"`
+	Transactions []LoyaltyTransaction `gorm:"foreignKey:AccountID;references:ID"`
+}
+
+// LoyaltyTransaction is an entry in the append-only points ledger. Corrections are made by
+// appending reversal entries, never by changing existing ones.
+type LoyaltyTransaction struct {
+	ID           uint                   `gorm:"primarykey"`
+	AccountID    uint                   `gorm:"not null;index"`
+	TicketID     *uint                  `gorm:"index"`
+	Type         LoyaltyTransactionType `gorm:"not null"`
+	Points       int                    `gorm:"not null"` // Signed change to the balance
+	BalanceAfter int                    `gorm:"not null"`
+	Description  string
+	CreatedAt    time.Time
+
+	Account LoyaltyAccount `gorm:"foreignKey:AccountID;references:ID"`
+}
+
+func (t *LoyaltyTransaction) BeforeUpdate(*gorm.DB) error {
+	return ErrLedgerAppendOnly
+}
+
+func (t *LoyaltyTransaction) BeforeDelete(*gorm.DB) error {
+	return ErrLedgerAppendOnly
 }
 
 type User struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	Username  string         `gorm:"uniqueIndex;not null" json:"username"`
-	Password  string         `gorm:"not null" json:"-"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
-	Role      Role           `gorm:"not null;default:'STAFF'" json:"role"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID         uint           `gorm:"primarykey" json:"id"`
+	Username   string         `gorm:"not null" json:"username"` // Unique among users not deleted
+	Password   string         `gorm:"not null" json:"-"`
+	Email      string         `gorm:"not null" json:"email"` // Unique among users not deleted
+	Role       Role           `gorm:"not null;default:'STAFF'" json:"role"`
+	DisabledAt *time.Time     `json:"disabled_at,omitempty"` // Disabled users cannot log in
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 func (u *User) HashPassword() error {
@@ -152,3 +505,102 @@ func (u *User) HashPassword() error {
 func (u *User) CheckPassword(password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
 }
+
+// RoleDefinition is a role users can be given, with the permissions it grants. The permissions
+// of the super admin role are fixed to all permissions.
+type RoleDefinition struct {
+	Name        Role   `gorm:"primaryKey;size:50"`
+	Description string `gorm:"not null;default:''"`
+	BuiltIn     bool   `gorm:"not null;default:false"` // Built-in roles cannot be deleted
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Permissions []RolePermission `gorm:"foreignKey:Role;references:Name;constraint:OnDelete:CASCADE"`
+}
+
+func (RoleDefinition) TableName() string {
+	return "roles"
+}
+
+type RolePermission struct {
+	Role       Role       `gorm:"primaryKey;size:50"`
+	Permission Permission `gorm:"primaryKey;size:50"`
+}
+
+// Invitation lets one person register with a role chosen by the admin who issued it. Only a
+// hash of the token is stored; the token itself is shown once, when the invitation is issued.
+type Invitation struct {
+	gorm.Model
+	TokenHash string    `gorm:"uniqueIndex;not null"` // Hex SHA-256 of the token
+	Email     string    // The invitee must register with this email when set
+	Role      Role      `gorm:"not null"`
+	CreatedBy string    `gorm:"not null"` // Username of the issuing admin
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	UsedByID  *uint
+
+	UsedBy *User `gorm:"foreignKey:UsedByID;references:ID"`
+}
+
+// Session is one login of a user, kept alive by rotating refresh tokens. Access tokens carry
+// the session ID and stop working as soon as the session is revoked.
+type Session struct {
+	gorm.Model
+	UserID    uint `gorm:"index;not null"`
+	User      *User
+	RevokedAt *time.Time
+
+	RefreshTokens []RefreshToken `gorm:"foreignKey:SessionID"`
+}
+
+// RefreshToken can be exchanged once for a new access token and refresh token. Only a hash of
+// the token is stored. Presenting a used token again revokes the whole session.
+type RefreshToken struct {
+	gorm.Model
+	SessionID uint      `gorm:"index;not null"`
+	Session   *Session  `gorm:"foreignKey:SessionID"`
+	TokenHash string    `gorm:"uniqueIndex;not null"` // Hex SHA-256 of the token
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
+// PasswordResetToken lets a user who forgot their password set a new one, once, before it
+// expires. Only a hash of the token is stored; the token itself is only mailed to the user.
+type PasswordResetToken struct {
+	gorm.Model
+	UserID    uint      `gorm:"index;not null"`
+	User      *User     `gorm:"foreignKey:UserID"`
+	TokenHash string    `gorm:"uniqueIndex;not null"` // Hex SHA-256 of the token
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
+// AuditAction is a security-relevant event recorded in the audit log
+type AuditAction string
+
+const (
+	AuditLoginLockout   AuditAction = "LOGIN_LOCKOUT"    // Too many failed logins for a username or an IP address
+	AuditAccountUnlock  AuditAction = "ACCOUNT_UNLOCKED" // An admin lifted the lockout of an account
+	AuditPasswordChange AuditAction = "PASSWORD_CHANGED" // A user changed their own password
+	AuditPasswordReset  AuditAction = "PASSWORD_RESET"   // A user set a new password with a reset link
+)
+
+// AuditLog is an append-only record of a security-relevant event.
+type AuditLog struct {
+	ID        uint        `gorm:"primarykey"`
+	CreatedAt time.Time   `gorm:"index"`
+	Action    AuditAction `gorm:"not null;index"`
+	Actor     string      `gorm:"not null"` // Username of the user who acted, "system" for automatic events
+	Subject   string      `gorm:"index"`    // What the event is about, e.g. a username or an IP address
+	IPAddress string
+	Details   string
+}
+
+// LoginThrottle counts the recent failed logins of a username or an IP address, keyed as
+// "user:<username>" or "ip:<address>". Usernames are tracked whether or not they exist.
+type LoginThrottle struct {
+	Key           string    `gorm:"primaryKey"`
+	Failures      int       `gorm:"not null;default:0"` // Failed logins since the last success or lockout
+	LastFailureAt time.Time `gorm:"not null"`
+	LockedUntil   *time.Time
+}
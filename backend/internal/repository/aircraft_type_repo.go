@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type aircraftTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewAircraftTypeRepository(db *gorm.DB) AircraftTypeRepository {
+	return &aircraftTypeRepository{db: db}
+}
+
+func (a aircraftTypeRepository) GetAll() ([]*models.AircraftType, error) {
+	aircraftTypes := make([]*models.AircraftType, 0)
+	result := a.db.Order("type_code").Find(&aircraftTypes)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get all aircraft types", result.Error)
+	}
+	return aircraftTypes, nil
+}
+
+func (a aircraftTypeRepository) GetByCode(code string) (*models.AircraftType, error) {
+	var aircraftType models.AircraftType
+	result := a.db.Where("type_code = ?", code).First(&aircraftType)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("aircraft type", code)
+		}
+		return nil, exceptions.InternalError("failed to get aircraft type by code", result.Error)
+	}
+	return &aircraftType, nil
+}
+
+func (a aircraftTypeRepository) Create(aircraftType *models.AircraftType) (*models.AircraftType, error) {
+	result := a.db.Create(aircraftType)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to create aircraft type", result.Error)
+	}
+	return aircraftType, nil
+}
+
+func (a aircraftTypeRepository) Update(aircraftType *models.AircraftType) (*models.AircraftType, error) {
+	result := a.db.Save(aircraftType)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to update aircraft type", result.Error)
+	}
+	return aircraftType, nil
+}
+
+func (a aircraftTypeRepository) Delete(aircraftType *models.AircraftType) error {
+	result := a.db.Delete(aircraftType)
+	if result.Error != nil {
+		return exceptions.InternalError("failed to delete aircraft type", result.Error)
+	}
+	return nil
+}
+
+func (a aircraftTypeRepository) CountPlanes(aircraftTypeID uint) (int64, error) {
+	var count int64
+	result := a.db.Model(&models.Plane{}).Where("aircraft_type_id = ?", aircraftTypeID).Count(&count)
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to count planes by aircraft type", result.Error)
+	}
+	return count, nil
+}
+
+// CountRoutes counts the routes that restrict their aircraft to a list including the type.
+func (a aircraftTypeRepository) CountRoutes(aircraftTypeID uint) (int64, error) {
+	var count int64
+	result := a.db.Table("route_aircraft_types").
+		Joins("JOIN routes ON routes.id = route_aircraft_types.route_id AND routes.deleted_at IS NULL").
+		Where("route_aircraft_types.aircraft_type_id = ?", aircraftTypeID).
+		Count(&count)
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to count routes by aircraft type", result.Error)
+	}
+	return count, nil
+}
+
+func (a aircraftTypeRepository) GetDB() *gorm.DB {
+	return a.db
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/models"
@@ -55,6 +56,110 @@ func (a airportRepository) GetByCodes(codes []string) (map[string]*models.Airpor
 	return airportMap, nil
 }
 
+func (a airportRepository) GetByIcaoCode(code string) (*models.Airport, error) {
+	var airport models.Airport
+	result := a.db.Where("icao_code = ?", code).First(&airport)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("airport", code)
+		}
+		return nil, exceptions.InternalError("failed to get airport by ICAO code", result.Error)
+	}
+	return &airport, nil
+}
+
+func (a airportRepository) Count() (int64, error) {
+	var count int64
+	if err := a.db.Model(&models.Airport{}).Count(&count).Error; err != nil {
+		return 0, exceptions.InternalError("failed to count airports", err)
+	}
+	return count, nil
+}
+
+// IsInUse reports whether any flight departs from, arrives at or stops at the airport, or
+// any route starts or ends there.
+func (a airportRepository) IsInUse(id uint) (bool, error) {
+	var count int64
+	result := a.db.Model(&models.Flight{}).
+		Where("departure_airport_id = ? OR arrival_airport_id = ?", id, id).
+		Count(&count)
+	if result.Error != nil {
+		return false, exceptions.InternalError("failed to count flights of airport", result.Error)
+	}
+	if count > 0 {
+		return true, nil
+	}
+	result = a.db.Model(&models.Route{}).
+		Where("departure_airport_id = ? OR arrival_airport_id = ?", id, id).
+		Count(&count)
+	if result.Error != nil {
+		return false, exceptions.InternalError("failed to count routes of airport", result.Error)
+	}
+	if count > 0 {
+		return true, nil
+	}
+	result = a.db.Model(&models.IntermediateStop{}).Where("airport_id = ?", id).Count(&count)
+	if result.Error != nil {
+		return false, exceptions.InternalError("failed to count intermediate stops of airport", result.Error)
+	}
+	return count > 0, nil
+}
+
+func (a airportRepository) Update(airport *models.Airport) (*models.Airport, error) {
+	result := a.db.Omit("Flights").Save(airport)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return nil, airportCodeTakenError()
+		}
+		return nil, exceptions.InternalError("failed to update airport", result.Error)
+	}
+	return airport, nil
+}
+
+func (a airportRepository) Delete(airport *models.Airport) error {
+	if err := a.db.Delete(airport).Error; err != nil {
+		return exceptions.InternalError("failed to delete airport", err)
+	}
+	return nil
+}
+
+// Upsert saves the airports in one transaction, updating those with an ID and creating the
+// others. Nothing is saved when the airport count would exceed maxAirports.
+func (a airportRepository) Upsert(airports []*models.Airport, maxAirports int) error {
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		// Concurrent imports and creations must not push the count over the limit together
+		if err := tx.Exec("LOCK TABLE airports IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return exceptions.InternalError("failed to lock airports", err)
+		}
+		for _, airport := range airports {
+			if err := tx.Omit("Flights").Save(airport).Error; err != nil {
+				if isUniqueViolation(err) {
+					return airportCodeTakenError()
+				}
+				return exceptions.InternalError("failed to save airport", err)
+			}
+		}
+
+		var count int64
+		if err := tx.Model(&models.Airport{}).Count(&count).Error; err != nil {
+			return exceptions.InternalError("failed to count airports", err)
+		}
+		if count > int64(maxAirports) {
+			return airportLimitError(maxAirports)
+		}
+		return nil
+	})
+}
+
+func airportCodeTakenError() error {
+	return exceptions.NewAppError(exceptions.CONFLICT, "airport IATA or ICAO code is already in use", nil)
+}
+
+func airportLimitError(maxAirports int) error {
+	return exceptions.NewAppError(exceptions.CONFLICT,
+		fmt.Sprintf("the number of airports is limited to %d", maxAirports), nil)
+}
+
 func (a airportRepository) GetDB() *gorm.DB {
 	return a.db
 }
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(entry *models.AuditLog) error {
+	if err := r.db.Create(entry).Error; err != nil {
+		return exceptions.InternalError("failed to write audit log", err)
+	}
+	return nil
+}
+
+// Search returns the latest entries, newest first, optionally of one action or subject.
+func (r *auditRepository) Search(action models.AuditAction, subject string, limit int) ([]*models.AuditLog, error) {
+	entries := make([]*models.AuditLog, 0)
+	query := r.db.Order("created_at DESC, id DESC").Limit(limit)
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if subject != "" {
+		query = query.Where("subject = ?", subject)
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, exceptions.InternalError("failed to search audit log", err)
+	}
+	return entries, nil
+}
+
+func (r *auditRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type erasureRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewErasureRequestRepository(db *gorm.DB) ErasureRequestRepository {
+	return &erasureRequestRepository{db: db}
+}
+
+func (e *erasureRequestRepository) Create(request *models.ErasureRequest) (*models.ErasureRequest, error) {
+	result := e.db.Create(request)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to create erasure request", result.Error)
+	}
+	return request, nil
+}
+
+func (e *erasureRequestRepository) Update(request *models.ErasureRequest) (*models.ErasureRequest, error) {
+	result := e.db.Save(request)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to update erasure request", result.Error)
+	}
+	return request, nil
+}
+
+func (e *erasureRequestRepository) GetAll() ([]*models.ErasureRequest, error) {
+	var requests []*models.ErasureRequest
+	result := e.db.Order("created_at DESC").Find(&requests)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get erasure requests", result.Error)
+	}
+	return requests, nil
+}
+
+func (e *erasureRequestRepository) GetPending() ([]*models.ErasureRequest, error) {
+	var requests []*models.ErasureRequest
+	result := e.db.
+		Where("status = ?", models.ErasureRequestPending).
+		Order("created_at").
+		Find(&requests)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get pending erasure requests", result.Error)
+	}
+	return requests, nil
+}
+
+func (e *erasureRequestRepository) GetDB() *gorm.DB {
+	return e.db
+}
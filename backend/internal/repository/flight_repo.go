@@ -38,6 +38,7 @@ func (f *flightRepository) GetByID(id uint) (*models.Flight, error) {
 		Preload("DepartureAirport").
 		Preload("ArrivalAirport").
 		Preload("Plane").
+		Preload("Route").
 		Preload("IntermediateStops.Airport").
 		Where("id = ?", id).
 		First(&flight)
@@ -57,6 +58,7 @@ func (f *flightRepository) GetByCode(code string) (*models.Flight, error) {
 		Preload("DepartureAirport").
 		Preload("ArrivalAirport").
 		Preload("Plane").
+		Preload("Route").
 		Preload("IntermediateStops.Airport").
 		Where("flight_code = ?", code).
 		First(&flight)
@@ -142,3 +144,42 @@ func (f *flightRepository) GetFlightsByDateRange(startDate, endDate time.Time) (
 	}
 	return flights, nil
 }
+
+// GetDepartingAfter returns the flights departing after the given time with their route and
+// intermediate stops, in departure order.
+func (f *flightRepository) GetDepartingAfter(at time.Time) ([]*models.Flight, error) {
+	var flights []*models.Flight
+	result := f.db.
+		Preload("DepartureAirport").
+		Preload("ArrivalAirport").
+		Preload("Route").
+		Preload("IntermediateStops.Airport").
+		Where("departure_date_time > ?", at).
+		Order("departure_date_time").
+		Find(&flights)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get flights departing after date", result.Error)
+	}
+	return flights, nil
+}
+
+// GetByPlanesInRange returns the flights of the given planes that are in the air at any time
+// between from and to, in departure order.
+func (f *flightRepository) GetByPlanesInRange(planeIDs []uint, from, to time.Time) ([]*models.Flight, error) {
+	var flights []*models.Flight
+	if len(planeIDs) == 0 {
+		return flights, nil
+	}
+	result := f.db.
+		Preload("Plane").
+		Preload("DepartureAirport").
+		Preload("ArrivalAirport").
+		Where("plane_id IN ?", planeIDs).
+		Where("departure_date_time < ? AND departure_date_time + flight_duration * INTERVAL '1 minute' > ?", to, from).
+		Order("departure_date_time").
+		Find(&flights)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get flights by plane and date range", result.Error)
+	}
+	return flights, nil
+}
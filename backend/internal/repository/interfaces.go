@@ -18,12 +18,20 @@ type FlightRepository interface {
 	DeleteIntermediateStops(flightID uint) error
 	GetDB() *gorm.DB
 	GetFlightsByDateRange(startDate, endDate time.Time) ([]*models.Flight, error)
+	GetByPlanesInRange(planeIDs []uint, from, to time.Time) ([]*models.Flight, error)
+	GetDepartingAfter(at time.Time) ([]*models.Flight, error)
 }
 
 type AirportRepository interface {
 	GetAll() ([]*models.Airport, error)
 	GetByCode(code string) (*models.Airport, error)
 	GetByCodes(codes []string) (map[string]*models.Airport, error)
+	GetByIcaoCode(code string) (*models.Airport, error)
+	Count() (int64, error)
+	IsInUse(id uint) (bool, error)
+	Update(airport *models.Airport) (*models.Airport, error)
+	Delete(airport *models.Airport) error
+	Upsert(airports []*models.Airport, maxAirports int) error
 	GetDB() *gorm.DB
 }
 
@@ -32,18 +40,66 @@ type PlaneRepository interface {
 	GetByID(id uint) (*models.Plane, error)
 	GetByCode(code string) (*models.Plane, error)
 	GetSeatByNumberAndPlaneCode(seatNumber, planeCode string) (*models.Seat, error)
+	GetSeatsByPlaneID(planeID uint) ([]*models.Seat, error)
+	Create(plane *models.Plane) (*models.Plane, error)
+	Update(plane *models.Plane) (*models.Plane, error)
+	GetUpcomingFlightCodes(planeID uint, departingAfter time.Time) ([]string, error)
+	SaveSeatMap(plane *models.Plane, seats []*models.Seat, removedSeatIDs, affectedSeatIDs []uint, check func(activeSeatIDs map[uint]bool) error) error
+	GetDB() *gorm.DB
+}
+
+type AircraftTypeRepository interface {
+	GetAll() ([]*models.AircraftType, error)
+	GetByCode(code string) (*models.AircraftType, error)
+	Create(aircraftType *models.AircraftType) (*models.AircraftType, error)
+	Update(aircraftType *models.AircraftType) (*models.AircraftType, error)
+	Delete(aircraftType *models.AircraftType) error
+	CountPlanes(aircraftTypeID uint) (int64, error)
+	CountRoutes(aircraftTypeID uint) (int64, error)
+	GetDB() *gorm.DB
+}
+
+type RouteRepository interface {
+	GetAll() ([]*models.Route, error)
+	GetByID(id uint) (*models.Route, error)
+	GetByAirportCodes(departureCode, arrivalCode string) (*models.Route, error)
+	Create(route *models.Route) (*models.Route, error)
+	Update(route *models.Route) (*models.Route, error)
+	Delete(route *models.Route) error
+	CountFlights(routeID uint) (int64, error)
+	GetDB() *gorm.DB
+}
+
+type MaintenanceRepository interface {
+	Create(window *models.MaintenanceWindow) (*models.MaintenanceWindow, error)
+	GetByID(id uint) (*models.MaintenanceWindow, error)
+	GetByPlaneID(planeID uint) ([]*models.MaintenanceWindow, error)
+	GetOverlapping(planeID uint, from, to time.Time) ([]*models.MaintenanceWindow, error)
+	GetInRange(from, to time.Time) ([]*models.MaintenanceWindow, error)
+	Delete(id uint) error
 	GetDB() *gorm.DB
 }
 
 type TicketClassRepository interface {
+	GetAll() ([]*models.TicketClass, error)
+	GetByID(id uint) (*models.TicketClass, error)
 	GetByName(name string) (*models.TicketClass, error)
 	GetByNames(names []string) (map[string]*models.TicketClass, error)
+	Create(ticketClass *models.TicketClass, maxTicketClasses int) (*models.TicketClass, error)
+	Update(ticketClass *models.TicketClass, templates []*models.AircraftType) (*models.TicketClass, error)
+	Delete(ticketClass *models.TicketClass) error
+	Count() (int64, error)
+	CountSeats(ticketClassID uint) (int64, error)
 	GetDB() *gorm.DB
 }
 
 type ParameterRepository interface {
 	GetAllParams() (*models.Parameter, error)
-	UpdateParams(params *models.Parameter) (*models.Parameter, error)
+	GetParamsAt(at time.Time) (*models.Parameter, error)
+	GetVersions() ([]*models.Parameter, error)
+	GetVersion(version int) (*models.Parameter, error)
+	CreateVersion(params *models.Parameter) (*models.Parameter, error)
+	DeleteVersion(params *models.Parameter) error
 	GetDB() *gorm.DB
 }
 
@@ -62,6 +118,105 @@ type TicketRepository interface {
 	Delete(id uint) error
 	GetDB() *gorm.DB
 	GetTicketsByFlightID(flightID uint) ([]*models.Ticket, error)
+	GetByPassengerID(passengerID uint) ([]*models.Ticket, error)
+	GetByIDCardIndex(index string) ([]*models.Ticket, error)
+	ReencryptPII(activePrefix string, batchSize int) (int, error)
+	GetBySubject(emailIndex, idCardIndex string) ([]*models.Ticket, error)
+	CountUnanonymizedBySubject(emailIndex, idCardIndex string) (int64, error)
+	AnonymizeBySubject(emailIndex, idCardIndex string, departedBefore time.Time) (int64, error)
+	AnonymizeDepartedBefore(departedBefore time.Time) (int64, error)
+	CountActiveByFlightIDs(flightIDs []uint) (map[uint]int, error)
+	GetActiveByFlightIDs(flightIDs []uint) (map[uint][]*models.Ticket, error)
+	GetByBookingReference(reference string) ([]*models.Ticket, error)
+	CreateWithSeats(flightID uint, tickets []*models.Ticket, assign func(tx *gorm.DB, occupied map[uint]bool) error) error
+}
+
+type PassengerRepository interface {
+	Create(passenger *models.Passenger) (*models.Passenger, error)
+	Update(passenger *models.Passenger) (*models.Passenger, error)
+	GetByID(id uint) (*models.Passenger, error)
+	GetByDocument(documentType models.DocumentType, documentNumberIndex string) (*models.Passenger, error)
+	Search(name, index string, limit int) ([]*models.Passenger, error)
+	SaveDocument(document *models.TravelDocument) (*models.TravelDocument, error)
+	FindBySubject(emailIndex, documentNumberIndex string) ([]*models.Passenger, error)
+	Anonymize(id uint) error
+	GetInactiveIDs(inactiveSince time.Time) ([]uint, error)
+	ReencryptPII(activePrefix string, batchSize int) (int, int, error)
+	GetDB() *gorm.DB
+}
+
+type ErasureRequestRepository interface {
+	Create(request *models.ErasureRequest) (*models.ErasureRequest, error)
+	Update(request *models.ErasureRequest) (*models.ErasureRequest, error)
+	GetAll() ([]*models.ErasureRequest, error)
+	GetPending() ([]*models.ErasureRequest, error)
+	GetDB() *gorm.DB
+}
+
+type LoyaltyRepository interface {
+	CreateAccount(account *models.LoyaltyAccount) (*models.LoyaltyAccount, error)
+	GetAccountByMemberNumber(memberNumber string) (*models.LoyaltyAccount, error)
+	GetAccountByPassengerID(passengerID uint) (*models.LoyaltyAccount, error)
+	GetBalance(accountID uint) (int, error)
+	GetQualifyingPoints(accountID uint, since time.Time) (int, error)
+	GetTransactions(accountID uint) ([]*models.LoyaltyTransaction, error)
+	GetTicketPoints(ticketID uint) (map[models.LoyaltyTransactionType]int, error)
+	AppendTransaction(entry *models.LoyaltyTransaction, qualifyingSince time.Time, tierFor func(qualifyingPoints int) models.LoyaltyTier) (*models.LoyaltyTransaction, error)
+	RefreshTiers(qualifyingSince time.Time, tierFor func(qualifyingPoints int) models.LoyaltyTier) (int, error)
+	GetDB() *gorm.DB
+}
+
+type LoginThrottleRepository interface {
+	GetByKeys(keys ...string) ([]*models.LoginThrottle, error)
+	RecordFailure(key string, at, windowStart time.Time) (*models.LoginThrottle, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) (bool, error)
+	GetDB() *gorm.DB
+}
+
+type AuditRepository interface {
+	Create(entry *models.AuditLog) error
+	Search(action models.AuditAction, subject string, limit int) ([]*models.AuditLog, error)
+	GetDB() *gorm.DB
+}
+
+type RoleRepository interface {
+	GetAll() ([]*models.RoleDefinition, error)
+	GetByName(name models.Role) (*models.RoleDefinition, error)
+	GetPermissions(name models.Role) ([]models.Permission, error)
+	Create(role *models.RoleDefinition) (*models.RoleDefinition, error)
+	Update(role *models.RoleDefinition) (*models.RoleDefinition, error)
+	Delete(role *models.RoleDefinition) error
+	GetDB() *gorm.DB
+}
+
+type SessionRepository interface {
+	Create(session *models.Session, refreshToken *models.RefreshToken) error
+	GetActive(id uint) (*models.Session, error)
+	GetRefreshToken(tokenHash string) (*models.RefreshToken, error)
+	Rotate(used, next *models.RefreshToken) (bool, error)
+	Revoke(id uint) error
+	RevokeAllForUser(userID uint) (int64, error)
+	RevokeOthersForUser(userID, keepSessionID uint) (int64, error)
+	GetDB() *gorm.DB
+}
+
+type PasswordResetRepository interface {
+	Create(token *models.PasswordResetToken) error
+	GetLatestForUser(userID uint) (*models.PasswordResetToken, error)
+	GetByTokenHash(tokenHash string) (*models.PasswordResetToken, error)
+	Redeem(token *models.PasswordResetToken, passwordHash string) (bool, error)
+	GetDB() *gorm.DB
+}
+
+type InvitationRepository interface {
+	GetAll() ([]*models.Invitation, error)
+	GetByID(id uint) (*models.Invitation, error)
+	GetByTokenHash(tokenHash string) (*models.Invitation, error)
+	Create(invitation *models.Invitation) (*models.Invitation, error)
+	Delete(invitation *models.Invitation) error
+	Redeem(invitation *models.Invitation, user *models.User) (bool, error)
+	GetDB() *gorm.DB
 }
 
 // UserRepository defines the interface for user-related database operations
@@ -70,7 +225,11 @@ type UserRepository interface {
 	GetByUsername(username string) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	GetByID(id uint) (*models.User, error)
+	Search(search string, role models.Role) ([]*models.User, error)
+	CountEnabledByRole(role models.Role) (int64, error)
 	Update(user *models.User) error
+	UpdateKeepingSuperAdmin(user *models.User) error
 	Delete(id uint) error
+	DeleteKeepingSuperAdmin(user *models.User) error
 	GetDB() *gorm.DB
 }
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+var errInvitationUsed = errors.New("invitation already used")
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) GetAll() ([]*models.Invitation, error) {
+	invitations := make([]*models.Invitation, 0)
+	result := r.db.Preload("UsedBy").Order("created_at DESC").Find(&invitations)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get all invitations", result.Error)
+	}
+	return invitations, nil
+}
+
+func (r *invitationRepository) GetByID(id uint) (*models.Invitation, error) {
+	var invitation models.Invitation
+	result := r.db.Preload("UsedBy").First(&invitation, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("invitation", strconv.FormatUint(uint64(id), 10))
+		}
+		return nil, exceptions.InternalError("failed to get invitation by id", result.Error)
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) GetByTokenHash(tokenHash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	result := r.db.Where("token_hash = ?", tokenHash).First(&invitation)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("invitation", "token")
+		}
+		return nil, exceptions.InternalError("failed to get invitation by token", result.Error)
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) Create(invitation *models.Invitation) (*models.Invitation, error) {
+	result := r.db.Omit("UsedBy").Create(invitation)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to create invitation", result.Error)
+	}
+	return invitation, nil
+}
+
+func (r *invitationRepository) Delete(invitation *models.Invitation) error {
+	result := r.db.Delete(invitation)
+	if result.Error != nil {
+		return exceptions.InternalError("failed to delete invitation", result.Error)
+	}
+	return nil
+}
+
+// Redeem creates the user and marks the invitation used in one transaction. It reports false,
+// creating no user, when the invitation was already used, possibly by a concurrent request.
+func (r *invitationRepository) Redeem(invitation *models.Invitation, user *models.User) (bool, error) {
+	redeemed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			if isUniqueViolation(err) {
+				return userExistsError()
+			}
+			return err
+		}
+		now := time.Now()
+		result := tx.Model(&models.Invitation{}).
+			Where("id = ? AND used_at IS NULL", invitation.ID).
+			Updates(map[string]any{"used_at": now, "used_by_id": user.ID})
+		if result.Error != nil {
+			return exceptions.InternalError("failed to redeem invitation", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// Roll back the user
+			return errInvitationUsed
+		}
+		invitation.UsedAt = &now
+		invitation.UsedByID = &user.ID
+		redeemed = true
+		return nil
+	})
+	if errors.Is(err, errInvitationUsed) {
+		return false, nil
+	}
+	return redeemed, err
+}
+
+func (r *invitationRepository) GetDB() *gorm.DB {
+	return r.db
+}
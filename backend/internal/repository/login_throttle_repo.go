@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+// GetByKeys returns the throttles of the keys that have any.
+func (r *loginThrottleRepository) GetByKeys(keys ...string) ([]*models.LoginThrottle, error) {
+	throttles := make([]*models.LoginThrottle, 0, len(keys))
+	result := r.db.Where("key IN ?", keys).Find(&throttles)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get login throttles", result.Error)
+	}
+	return throttles, nil
+}
+
+// RecordFailure counts a failed login for a key in a single statement, so concurrent attempts
+// are all counted. Failures before windowStart are forgotten.
+func (r *loginThrottleRepository) RecordFailure(key string, at, windowStart time.Time) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	result := r.db.Raw(`INSERT INTO login_throttles (key, failures, last_failure_at) VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING key, failures, last_failure_at, locked_until`, key, at, windowStart).Scan(&throttle)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to record failed login", result.Error)
+	}
+	return &throttle, nil
+}
+
+// Lock locks a key out until the given time and starts counting its failures afresh.
+func (r *loginThrottleRepository) Lock(key string, until time.Time) error {
+	result := r.db.Model(&models.LoginThrottle{}).
+		Where("key = ?", key).
+		Updates(map[string]any{"locked_until": until, "failures": 0})
+	if result.Error != nil {
+		return exceptions.InternalError("failed to lock out login", result.Error)
+	}
+	return nil
+}
+
+// Reset forgets the failures and any lockout of a key, and reports whether it had any.
+func (r *loginThrottleRepository) Reset(key string) (bool, error) {
+	result := r.db.Where("key = ?", key).Delete(&models.LoginThrottle{})
+	if result.Error != nil {
+		return false, exceptions.InternalError("failed to reset login throttle", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *loginThrottleRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loyaltyRepository struct {
+	db *gorm.DB
+}
+
+func NewLoyaltyRepository(db *gorm.DB) LoyaltyRepository {
+	return &loyaltyRepository{db: db}
+}
+
+func (l *loyaltyRepository) CreateAccount(account *models.LoyaltyAccount) (*models.LoyaltyAccount, error) {
+	result := l.db.Create(account)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to create loyalty account", result.Error)
+	}
+	return account, nil
+}
+
+func (l *loyaltyRepository) GetAccountByMemberNumber(memberNumber string) (*models.LoyaltyAccount, error) {
+	var account models.LoyaltyAccount
+	result := l.db.
+		Preload("Passenger").
+		Where("member_number = ?", memberNumber).
+		First(&account)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("loyalty account", memberNumber)
+		}
+		return nil, exceptions.InternalError("failed to get loyalty account by member number", result.Error)
+	}
+	return &account, nil
+}
+
+func (l *loyaltyRepository) GetAccountByPassengerID(passengerID uint) (*models.LoyaltyAccount, error) {
+	var account models.LoyaltyAccount
+	result := l.db.
+		Preload("Passenger").
+		Where("passenger_id = ?", passengerID).
+		First(&account)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("loyalty account for passenger", strconv.Itoa(int(passengerID)))
+		}
+		return nil, exceptions.InternalError("failed to get loyalty account by passenger", result.Error)
+	}
+	return &account, nil
+}
+
+func (l *loyaltyRepository) GetBalance(accountID uint) (int, error) {
+	var balance int
+	result := l.db.Model(&models.LoyaltyTransaction{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("account_id = ?", accountID).
+		Scan(&balance)
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to get loyalty balance", result.Error)
+	}
+	return balance, nil
+}
+
+func (l *loyaltyRepository) GetQualifyingPoints(accountID uint, since time.Time) (int, error) {
+	var points int
+	result := l.db.Model(&models.LoyaltyTransaction{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("account_id = ? AND created_at >= ? AND type IN ?", accountID, since,
+			[]models.LoyaltyTransactionType{models.LoyaltyTransactionEarn, models.LoyaltyTransactionEarnReversal}).
+		Scan(&points)
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to get qualifying points", result.Error)
+	}
+	return points, nil
+}
+
+func (l *loyaltyRepository) GetTransactions(accountID uint) ([]*models.LoyaltyTransaction, error) {
+	transactions := make([]*models.LoyaltyTransaction, 0)
+	result := l.db.
+		Where("account_id = ?", accountID).
+		Order("id DESC").
+		Find(&transactions)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get loyalty transactions", result.Error)
+	}
+	return transactions, nil
+}
+
+func (l *loyaltyRepository) GetTicketPoints(ticketID uint) (map[models.LoyaltyTransactionType]int, error) {
+	type ticketPoints struct {
+		Type   models.LoyaltyTransactionType
+		Points int
+	}
+	var rows []ticketPoints
+	result := l.db.Model(&models.LoyaltyTransaction{}).
+		Select("type, SUM(points) AS points").
+		Where("ticket_id = ?", ticketID).
+		Group("type").
+		Find(&rows)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get ticket points", result.Error)
+	}
+
+	points := make(map[models.LoyaltyTransactionType]int, len(rows))
+	for _, row := range rows {
+		points[row.Type] = row.Points
+	}
+	return points, nil
+}
+
+// AppendTransaction writes a ledger entry while holding a lock on the account row, so that
+// concurrent entries see each other's balance and the balance never goes negative. The
+// account's tier is recomputed in the same transaction from the points earned since
+// qualifyingSince.
+func (l *loyaltyRepository) AppendTransaction(entry *models.LoyaltyTransaction, qualifyingSince time.Time, tierFor func(qualifyingPoints int) models.LoyaltyTier) (*models.LoyaltyTransaction, error) {
+	err := l.db.Transaction(func(tx *gorm.DB) error {
+		account, err := lockAccount(tx, entry.AccountID)
+		if err != nil {
+			return err
+		}
+
+		var balance int
+		if err := tx.Model(&models.LoyaltyTransaction{}).
+			Select("COALESCE(SUM(points), 0)").
+			Where("account_id = ?", entry.AccountID).
+			Scan(&balance).Error; err != nil {
+			return exceptions.InternalError("failed to get loyalty balance", err)
+		}
+
+		entry.BalanceAfter = balance + entry.Points
+		if entry.BalanceAfter < 0 {
+			return exceptions.BadRequestError("insufficient loyalty points", nil)
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return exceptions.InternalError("failed to append loyalty transaction", err)
+		}
+		_, err = refreshTier(tx, account, qualifyingSince, tierFor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// RefreshTiers recomputes the tier of the accounts above the basic tier, which drop when
+// their qualifying points age out of the window. It returns the number of accounts changed.
+func (l *loyaltyRepository) RefreshTiers(qualifyingSince time.Time, tierFor func(qualifyingPoints int) models.LoyaltyTier) (int, error) {
+	var accountIDs []uint
+	if err := l.db.Model(&models.LoyaltyAccount{}).
+		Where("tier <> ?", models.LoyaltyTierBasic).
+		Order("id").
+		Pluck("id", &accountIDs).Error; err != nil {
+		return 0, exceptions.InternalError("failed to get loyalty accounts", err)
+	}
+
+	changed := 0
+	for _, accountID := range accountIDs {
+		err := l.db.Transaction(func(tx *gorm.DB) error {
+			account, err := lockAccount(tx, accountID)
+			if err != nil {
+				return err
+			}
+			updated, err := refreshTier(tx, account, qualifyingSince, tierFor)
+			if updated {
+				changed++
+			}
+			return err
+		})
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// lockAccount loads the account and locks its row for the rest of the transaction.
+func lockAccount(tx *gorm.DB, accountID uint) (*models.LoyaltyAccount, error) {
+	var account models.LoyaltyAccount
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, accountID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("loyalty account", strconv.Itoa(int(accountID)))
+		}
+		return nil, exceptions.InternalError("failed to lock loyalty account", err)
+	}
+	return &account, nil
+}
+
+// refreshTier stores the tier earned by the locked account's qualifying points and reports
+// whether it changed.
+func refreshTier(tx *gorm.DB, account *models.LoyaltyAccount, qualifyingSince time.Time, tierFor func(qualifyingPoints int) models.LoyaltyTier) (bool, error) {
+	var qualifying int
+	if err := tx.Model(&models.LoyaltyTransaction{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("account_id = ? AND created_at >= ? AND type IN ?", account.ID, qualifyingSince,
+			[]models.LoyaltyTransactionType{models.LoyaltyTransactionEarn, models.LoyaltyTransactionEarnReversal}).
+		Scan(&qualifying).Error; err != nil {
+		return false, exceptions.InternalError("failed to get qualifying points", err)
+	}
+
+	tier := tierFor(qualifying)
+	if tier == account.Tier {
+		return false, nil
+	}
+	if err := tx.Model(account).Update("tier", tier).Error; err != nil {
+		return false, exceptions.InternalError("failed to update loyalty tier", err)
+	}
+	return true, nil
+}
+
+func (l *loyaltyRepository) GetDB() *gorm.DB {
+	return l.db
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type maintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) MaintenanceRepository {
+	return &maintenanceRepository{db: db}
+}
+
+func (m *maintenanceRepository) Create(window *models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	result := m.db.Create(window)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to create maintenance window", result.Error)
+	}
+	return window, nil
+}
+
+func (m *maintenanceRepository) GetByID(id uint) (*models.MaintenanceWindow, error) {
+	var window models.MaintenanceWindow
+	result := m.db.Preload("Plane").First(&window, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("maintenance window", strconv.Itoa(int(id)))
+		}
+		return nil, exceptions.InternalError("failed to get maintenance window by id", result.Error)
+	}
+	return &window, nil
+}
+
+func (m *maintenanceRepository) GetByPlaneID(planeID uint) ([]*models.MaintenanceWindow, error) {
+	var windows []*models.MaintenanceWindow
+	result := m.db.
+		Where("plane_id = ?", planeID).
+		Order("start_time").
+		Find(&windows)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get maintenance windows by plane id", result.Error)
+	}
+	return windows, nil
+}
+
+// GetOverlapping returns the windows of the plane that are open at any time between from and to.
+func (m *maintenanceRepository) GetOverlapping(planeID uint, from, to time.Time) ([]*models.MaintenanceWindow, error) {
+	var windows []*models.MaintenanceWindow
+	result := m.db.
+		Where("plane_id = ? AND start_time < ? AND end_time > ?", planeID, to, from).
+		Order("start_time").
+		Find(&windows)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get overlapping maintenance windows", result.Error)
+	}
+	return windows, nil
+}
+
+// GetInRange returns the windows of every plane that are open at any time between from and to.
+func (m *maintenanceRepository) GetInRange(from, to time.Time) ([]*models.MaintenanceWindow, error) {
+	var windows []*models.MaintenanceWindow
+	result := m.db.
+		Where("start_time < ? AND end_time > ?", to, from).
+		Order("plane_id, start_time").
+		Find(&windows)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get maintenance windows by date range", result.Error)
+	}
+	return windows, nil
+}
+
+func (m *maintenanceRepository) Delete(id uint) error {
+	result := m.db.Delete(&models.MaintenanceWindow{}, id)
+	if result.Error != nil {
+		return exceptions.InternalError("failed to delete maintenance window", result.Error)
+	}
+	return nil
+}
+
+func (m *maintenanceRepository) GetDB() *gorm.DB {
+	return m.db
+}
@@ -1,6 +1,10 @@
 package repository
 
 import (
+	"errors"
+	"strconv"
+	"time"
+
 	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/models"
 	"gorm.io/gorm"
@@ -14,26 +18,80 @@ type parameterRepository struct {
 	db *gorm.DB
 }
 
+// GetAllParams returns the parameter version in force now.
 func (p parameterRepository) GetAllParams() (*models.Parameter, error) {
+	return p.GetParamsAt(time.Now())
+}
+
+// GetParamsAt returns the parameter version in force at the given time: the latest to have
+// taken effect by then, or the first version for times before any took effect.
+func (p parameterRepository) GetParamsAt(at time.Time) (*models.Parameter, error) {
 	var params models.Parameter
-	result := p.db.First(&params)
+	result := p.db.Where("effective_from <= ?", at).Order("effective_from DESC, version DESC").Limit(1).Find(&params)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get params in force", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return &params, nil
+	}
+	result = p.db.Order("version").First(&params)
 	if result.Error != nil {
 		return nil, exceptions.InternalError("failed to get all params", result.Error)
 	}
 	return &params, nil
 }
 
-func (p parameterRepository) UpdateParams(params *models.Parameter) (*models.Parameter, error) {
-	var oldParams models.Parameter
-	result := p.db.First(&oldParams)
+func (p parameterRepository) GetVersions() ([]*models.Parameter, error) {
+	versions := make([]*models.Parameter, 0)
+	result := p.db.Order("version DESC").Find(&versions)
 	if result.Error != nil {
-		return nil, exceptions.InternalError("failed to get old params", result.Error)
+		return nil, exceptions.InternalError("failed to get parameter versions", result.Error)
 	}
-	result = p.db.Model(&oldParams).Updates(params)
+	return versions, nil
+}
+
+func (p parameterRepository) GetVersion(version int) (*models.Parameter, error) {
+	var params models.Parameter
+	result := p.db.Where("version = ?", version).First(&params)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("parameter version", strconv.Itoa(version))
+		}
+		return nil, exceptions.InternalError("failed to get parameter version", result.Error)
+	}
+	return &params, nil
+}
+
+// CreateVersion saves the parameters under the next version number. Numbers of cancelled
+// versions are not reused.
+func (p parameterRepository) CreateVersion(params *models.Parameter) (*models.Parameter, error) {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		// Two concurrent changes must not take the same version number
+		if err := tx.Exec("LOCK TABLE parameters IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return exceptions.InternalError("failed to lock parameters", err)
+		}
+		var latest int
+		if err := tx.Unscoped().Model(&models.Parameter{}).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return exceptions.InternalError("failed to get latest parameter version", err)
+		}
+		params.Version = latest + 1
+		if err := tx.Create(params).Error; err != nil {
+			return exceptions.InternalError("failed to create parameter version", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (p parameterRepository) DeleteVersion(params *models.Parameter) error {
+	result := p.db.Delete(params)
 	if result.Error != nil {
-		return nil, exceptions.InternalError("failed to update params", result.Error)
+		return exceptions.InternalError("failed to delete parameter version", result.Error)
 	}
-	return &oldParams, nil
+	return nil
 }
 
 func (p parameterRepository) GetDB() *gorm.DB {
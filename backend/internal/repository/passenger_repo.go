@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type passengerRepository struct {
+	db *gorm.DB
+}
+
+func NewPassengerRepository(db *gorm.DB) PassengerRepository {
+	return &passengerRepository{db: db}
+}
+
+func (p *passengerRepository) Create(passenger *models.Passenger) (*models.Passenger, error) {
+	result := p.db.Create(passenger)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to create passenger", result.Error)
+	}
+	return passenger, nil
+}
+
+func (p *passengerRepository) Update(passenger *models.Passenger) (*models.Passenger, error) {
+	result := p.db.Omit("Documents", "Tickets").Save(passenger)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to update passenger", result.Error)
+	}
+	return passenger, nil
+}
+
+func (p *passengerRepository) GetByID(id uint) (*models.Passenger, error) {
+	var passenger models.Passenger
+	result := p.db.
+		Preload("Documents").
+		Where("id = ?", id).
+		First(&passenger)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("passenger", strconv.Itoa(int(id)))
+		}
+		return nil, exceptions.InternalError("failed to get passenger by id", result.Error)
+	}
+	return &passenger, nil
+}
+
+// GetByDocument returns the passenger holding the document whose number has the blind index.
+func (p *passengerRepository) GetByDocument(documentType models.DocumentType, documentNumberIndex string) (*models.Passenger, error) {
+	var document models.TravelDocument
+	result := p.db.
+		Where("document_type = ? AND document_number_index = ? AND document_number_index <> ''", documentType, documentNumberIndex).
+		First(&document)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("passenger", string(documentType))
+		}
+		return nil, exceptions.InternalError("failed to get passenger by document", result.Error)
+	}
+	return p.GetByID(document.PassengerID)
+}
+
+// Search returns the passengers whose full name contains the name, ignoring case, or whose
+// email, phone number or travel document number has the blind index. Those values are
+// encrypted, so only whole values match them.
+func (p *passengerRepository) Search(name, index string, limit int) ([]*models.Passenger, error) {
+	passengers := make([]*models.Passenger, 0)
+	pattern := "%" + strings.ToLower(name) + "%"
+	documentMatches := p.db.Model(&models.TravelDocument{}).
+		Select("passenger_id").
+		Where("document_number_index = ? AND document_number_index <> ''", index)
+	result := p.db.
+		Preload("Documents").
+		Where("(LOWER(full_name) LIKE ? OR (email_index = ? AND email_index <> '') OR (phone_number_index = ? AND phone_number_index <> '') OR id IN (?))",
+			pattern, index, index, documentMatches).
+		Order("full_name").
+		Limit(limit).
+		Find(&passengers)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to search passengers", result.Error)
+	}
+	return passengers, nil
+}
+
+func (p *passengerRepository) SaveDocument(document *models.TravelDocument) (*models.TravelDocument, error) {
+	result := p.db.Save(document)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to save travel document", result.Error)
+	}
+	return document, nil
+}
+
+// FindBySubject returns the passengers whose email or travel document number has the blind
+// index. Empty indexes never match.
+func (p *passengerRepository) FindBySubject(emailIndex, documentNumberIndex string) ([]*models.Passenger, error) {
+	passengers := make([]*models.Passenger, 0)
+	documentMatches := p.db.Model(&models.TravelDocument{}).
+		Select("passenger_id").
+		Where("document_number_index = ? AND document_number_index <> ''", documentNumberIndex)
+	result := p.db.
+		Preload("Documents").
+		Where("(email_index = ? AND email_index <> '') OR id IN (?)", emailIndex, documentMatches).
+		Order("id").
+		Find(&passengers)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to find passengers by subject", result.Error)
+	}
+	return passengers, nil
+}
+
+// Anonymize removes the passenger's personal data and travel documents. The profile row is
+// kept so loyalty accounts and ledger entries referring to it stay consistent.
+func (p *passengerRepository) Anonymize(id uint) error {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("passenger_id = ?", id).Delete(&models.TravelDocument{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Passenger{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"full_name":          models.AnonymizedName,
+			"date_of_birth":      nil,
+			"nationality":        "",
+			"phone_number":       "",
+			"phone_number_index": "",
+			"email":              "",
+			"email_index":        "",
+			"anonymized_at":      time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return exceptions.InternalError("failed to anonymize passenger", err)
+	}
+	return nil
+}
+
+// GetInactiveIDs returns the passengers, not yet anonymized, created before inactiveSince that
+// hold no ticket on a flight departing since then. Tickets lose their passenger link when they
+// are anonymized, so profiles whose tickets were all anonymized are included.
+func (p *passengerRepository) GetInactiveIDs(inactiveSince time.Time) ([]uint, error) {
+	recent := p.db.Model(&models.Ticket{}).
+		Select("tickets.passenger_id").
+		Joins("JOIN flights ON flights.id = tickets.flight_id").
+		Where("tickets.passenger_id IS NOT NULL AND flights.departure_date_time >= ?", inactiveSince)
+	var ids []uint
+	result := p.db.Model(&models.Passenger{}).
+		Where("anonymized_at IS NULL AND created_at < ? AND id NOT IN (?)", inactiveSince, recent).
+		Order("id").
+		Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get inactive passengers", result.Error)
+	}
+	return ids, nil
+}
+
+// ReencryptPII rewrites, in batches of batchSize, every passenger and travel document holding a
+// PII value that was not encrypted with the key identified by activePrefix. It returns the
+// number of passengers and documents rewritten.
+func (p *passengerRepository) ReencryptPII(activePrefix string, batchSize int) (int, int, error) {
+	pattern := activePrefix + "%"
+	var passengers int
+	var lastID uint
+	for {
+		var batch []*models.Passenger
+		result := p.db.
+			Where("id > ?", lastID).
+			Where("(phone_number <> '' AND phone_number NOT LIKE ?) OR (email <> '' AND email NOT LIKE ?)",
+				pattern, pattern).
+			Order("id").
+			Limit(batchSize).
+			Find(&batch)
+		if result.Error != nil {
+			return passengers, 0, exceptions.InternalError("failed to load passengers for re-encryption", result.Error)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		err := p.db.Transaction(func(tx *gorm.DB) error {
+			for _, passenger := range batch {
+				if err := tx.Model(passenger).
+					Select("PhoneNumber", "PhoneNumberIndex", "Email", "EmailIndex").
+					Updates(passenger).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return passengers, 0, exceptions.InternalError("failed to re-encrypt passengers", err)
+		}
+		passengers += len(batch)
+		lastID = batch[len(batch)-1].ID
+	}
+
+	var documents int
+	lastID = 0
+	for {
+		var batch []*models.TravelDocument
+		result := p.db.
+			Where("id > ?", lastID).
+			Where("document_number <> '' AND document_number NOT LIKE ?", pattern).
+			Order("id").
+			Limit(batchSize).
+			Find(&batch)
+		if result.Error != nil {
+			return passengers, documents, exceptions.InternalError("failed to load travel documents for re-encryption", result.Error)
+		}
+		if len(batch) == 0 {
+			return passengers, documents, nil
+		}
+		err := p.db.Transaction(func(tx *gorm.DB) error {
+			for _, document := range batch {
+				if err := tx.Model(document).Select("DocumentNumber", "DocumentNumberIndex").Updates(document).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return passengers, documents, exceptions.InternalError("failed to re-encrypt travel documents", err)
+		}
+		documents += len(batch)
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+func (p *passengerRepository) GetDB() *gorm.DB {
+	return p.db
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+var errResetTokenUsed = errors.New("password reset token already used")
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create stores a reset token, invalidating the user's earlier ones so only the latest link works.
+func (r *passwordResetRepository) Create(token *models.PasswordResetToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return exceptions.InternalError("failed to invalidate password reset tokens", result.Error)
+		}
+		if err := tx.Omit("User").Create(token).Error; err != nil {
+			return exceptions.InternalError("failed to create password reset token", err)
+		}
+		return nil
+	})
+}
+
+// GetLatestForUser returns the user's most recently issued reset token.
+func (r *passwordResetRepository) GetLatestForUser(userID uint) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("password reset token", "user")
+		}
+		return nil, exceptions.InternalError("failed to get password reset token", result.Error)
+	}
+	return &token, nil
+}
+
+// GetByTokenHash returns a reset token with its user.
+func (r *passwordResetRepository) GetByTokenHash(tokenHash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	result := r.db.Preload("User").Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("password reset token", "token")
+		}
+		return nil, exceptions.InternalError("failed to get password reset token", result.Error)
+	}
+	return &token, nil
+}
+
+// Redeem marks a reset token used, sets the user's new password hash and revokes all of the
+// user's sessions in one transaction. It reports false, changing nothing, when the token was
+// already used, possibly by a concurrent request.
+func (r *passwordResetRepository) Redeem(token *models.PasswordResetToken, passwordHash string) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return exceptions.InternalError("failed to use password reset token", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errResetTokenUsed
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", token.UserID).
+			Update("password", passwordHash).Error; err != nil {
+			return exceptions.InternalError("failed to update password", err)
+		}
+		if err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", token.UserID).
+			Update("revoked_at", now).Error; err != nil {
+			return exceptions.InternalError("failed to revoke sessions", err)
+		}
+		token.UsedAt = &now
+		return nil
+	})
+	if errors.Is(err, errResetTokenUsed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *passwordResetRepository) GetDB() *gorm.DB {
+	return r.db
+}
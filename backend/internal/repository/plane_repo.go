@@ -3,10 +3,12 @@ package repository
 import (
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func NewPlaneRepository(db *gorm.DB) PlaneRepository {
@@ -36,10 +38,24 @@ func (p planeRepository) GetSeatByNumberAndPlaneCode(seatNumber, planeCode strin
 	return &seat, nil
 }
 
+// GetSeatsByPlaneID returns the plane's seats in seat map order, front row first.
+func (p planeRepository) GetSeatsByPlaneID(planeID uint) ([]*models.Seat, error) {
+	var seats []*models.Seat
+	result := p.db.
+		Preload("TicketClass").
+		Where("plane_id = ?", planeID).
+		Order("row_number, column_letter, id").
+		Find(&seats)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get seats by plane id", result.Error)
+	}
+	return seats, nil
+}
+
 func (p planeRepository) GetAll() ([]*models.Plane, error) {
 	planes := make([]*models.Plane, 0)
 
-	if err := p.db.Find(&planes).Error; err != nil {
+	if err := p.db.Preload("AircraftType").Find(&planes).Error; err != nil {
 		return nil, exceptions.InternalError("failed to get all planes", err)
 	}
 
@@ -49,6 +65,7 @@ func (p planeRepository) GetAll() ([]*models.Plane, error) {
 func (p planeRepository) GetByCode(code string) (*models.Plane, error) {
 	var plane models.Plane
 	result := p.db.Where("plane_code = ?", code).
+		Preload("AircraftType").
 		Preload("Seats.TicketClass").
 		First(&plane)
 	if result.Error != nil {
@@ -72,6 +89,87 @@ func (p planeRepository) GetByID(id uint) (*models.Plane, error) {
 	return &plane, nil
 }
 
+func (p planeRepository) Create(plane *models.Plane) (*models.Plane, error) {
+	result := p.db.Omit("Seats", "AircraftType").Create(plane)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to create plane", result.Error)
+	}
+	return plane, nil
+}
+
+func (p planeRepository) Update(plane *models.Plane) (*models.Plane, error) {
+	result := p.db.Omit("Seats", "AircraftType").Save(plane)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to update plane", result.Error)
+	}
+	return plane, nil
+}
+
+// GetUpcomingFlightCodes returns the codes of the plane's flights departing after the given
+// time, earliest first.
+func (p planeRepository) GetUpcomingFlightCodes(planeID uint, departingAfter time.Time) ([]string, error) {
+	var codes []string
+	result := p.db.Model(&models.Flight{}).
+		Where("plane_id = ? AND departure_date_time > ?", planeID, departingAfter).
+		Order("departure_date_time").
+		Pluck("flight_code", &codes)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get upcoming flights by plane", result.Error)
+	}
+	return codes, nil
+}
+
+// SaveSeatMap stores the plane's layout together with its seats in one transaction, creating
+// the plane when it is new. Seats with an ID are updated in place, the others are created, and
+// removedSeatIDs are deleted.
+//
+// The plane's upcoming flights are locked first, as bookings do, and check is given which of
+// affectedSeatIDs hold active tickets on them; the seat map is only saved when check passes.
+func (p planeRepository) SaveSeatMap(plane *models.Plane, seats []*models.Seat, removedSeatIDs, affectedSeatIDs []uint, check func(activeSeatIDs map[uint]bool) error) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		active := make(map[uint]bool)
+		if plane.ID != 0 && len(affectedSeatIDs) > 0 {
+			var flightIDs []uint
+			if err := tx.Model(&models.Flight{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("plane_id = ? AND departure_date_time > ?", plane.ID, time.Now()).
+				Pluck("id", &flightIDs).Error; err != nil {
+				return exceptions.InternalError("failed to lock upcoming flights", err)
+			}
+			if len(flightIDs) > 0 {
+				var seatIDs []uint
+				if err := tx.Model(&models.Ticket{}).
+					Distinct("seat_id").
+					Where("flight_id IN ? AND seat_id IN ? AND ticket_status = ?", flightIDs, affectedSeatIDs, models.TicketStatusActive).
+					Pluck("seat_id", &seatIDs).Error; err != nil {
+					return exceptions.InternalError("failed to get seats with active tickets", err)
+				}
+				for _, id := range seatIDs {
+					active[id] = true
+				}
+			}
+		}
+		if err := check(active); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Seats", "AircraftType").Save(plane).Error; err != nil {
+			return exceptions.InternalError("failed to save seat map", err)
+		}
+		if len(removedSeatIDs) > 0 {
+			if err := tx.Delete(&models.Seat{}, removedSeatIDs).Error; err != nil {
+				return exceptions.InternalError("failed to save seat map", err)
+			}
+		}
+		for _, seat := range seats {
+			seat.PlaneID = plane.ID
+			if err := tx.Omit("TicketClass", "Plane", "Tickets").Save(seat).Error; err != nil {
+				return exceptions.InternalError("failed to save seat map", err)
+			}
+		}
+		return nil
+	})
+}
+
 func (p planeRepository) GetDB() *gorm.DB {
 	return p.db
 }
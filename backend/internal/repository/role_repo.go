@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) GetAll() ([]*models.RoleDefinition, error) {
+	roles := make([]*models.RoleDefinition, 0)
+	result := r.db.Preload("Permissions").Order("name").Find(&roles)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get all roles", result.Error)
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetByName(name models.Role) (*models.RoleDefinition, error) {
+	var role models.RoleDefinition
+	result := r.db.Preload("Permissions").Where("name = ?", name).First(&role)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("role", string(name))
+		}
+		return nil, exceptions.InternalError("failed to get role by name", result.Error)
+	}
+	return &role, nil
+}
+
+// GetPermissions returns the permissions stored for a role, none for an unknown role.
+func (r *roleRepository) GetPermissions(name models.Role) ([]models.Permission, error) {
+	permissions := make([]models.Permission, 0)
+	result := r.db.Model(&models.RolePermission{}).
+		Where("role = ?", name).
+		Order("permission").
+		Pluck("permission", &permissions)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get role permissions", result.Error)
+	}
+	return permissions, nil
+}
+
+func (r *roleRepository) Create(role *models.RoleDefinition) (*models.RoleDefinition, error) {
+	result := r.db.Create(role)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return nil, exceptions.NewAppError(exceptions.CONFLICT,
+				fmt.Sprintf("role '%s' already exists", role.Name), nil)
+		}
+		return nil, exceptions.InternalError("failed to create role", result.Error)
+	}
+	return role, nil
+}
+
+// Update saves the description of a role and replaces its permissions.
+func (r *roleRepository) Update(role *models.RoleDefinition) (*models.RoleDefinition, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Omit("Permissions").Updates(map[string]any{"description": role.Description}).Error; err != nil {
+			return exceptions.InternalError("failed to update role", err)
+		}
+		if err := tx.Where("role = ?", role.Name).Delete(&models.RolePermission{}).Error; err != nil {
+			return exceptions.InternalError("failed to update role permissions", err)
+		}
+		if len(role.Permissions) > 0 {
+			if err := tx.Create(&role.Permissions).Error; err != nil {
+				return exceptions.InternalError("failed to update role permissions", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Delete removes a role that no user has. The users table is locked so that no user is given
+// the role while it is being deleted.
+func (r *roleRepository) Delete(role *models.RoleDefinition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return exceptions.InternalError("failed to lock users", err)
+		}
+		var users int64
+		if err := tx.Model(&models.User{}).Where("role = ?", role.Name).Count(&users).Error; err != nil {
+			return exceptions.InternalError("failed to count users of role", err)
+		}
+		if users > 0 {
+			return exceptions.NewAppError(exceptions.CONFLICT,
+				fmt.Sprintf("role '%s' is given to %d users", role.Name, users), nil)
+		}
+		if err := tx.Where("role = ?", role.Name).Delete(&models.RolePermission{}).Error; err != nil {
+			return exceptions.InternalError("failed to delete role permissions", err)
+		}
+		if err := tx.Delete(role).Error; err != nil {
+			return exceptions.InternalError("failed to delete role", err)
+		}
+		return nil
+	})
+}
+
+func (r *roleRepository) GetDB() *gorm.DB {
+	return r.db
+}
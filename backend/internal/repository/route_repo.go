@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type routeRepository struct {
+	db *gorm.DB
+}
+
+func NewRouteRepository(db *gorm.DB) RouteRepository {
+	return &routeRepository{db: db}
+}
+
+func (r routeRepository) withRelations() *gorm.DB {
+	return r.db.
+		Preload("DepartureAirport").
+		Preload("ArrivalAirport").
+		Preload("AllowedAircraftTypes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("type_code")
+		})
+}
+
+func (r routeRepository) GetAll() ([]*models.Route, error) {
+	routes := make([]*models.Route, 0)
+	result := r.withRelations().Order("routes.id").Find(&routes)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get all routes", result.Error)
+	}
+	return routes, nil
+}
+
+func (r routeRepository) GetByID(id uint) (*models.Route, error) {
+	var route models.Route
+	result := r.withRelations().First(&route, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("route", strconv.FormatUint(uint64(id), 10))
+		}
+		return nil, exceptions.InternalError("failed to get route by id", result.Error)
+	}
+	return &route, nil
+}
+
+// GetByAirportCodes returns the route from the departure airport to the arrival airport.
+func (r routeRepository) GetByAirportCodes(departureCode, arrivalCode string) (*models.Route, error) {
+	var route models.Route
+	result := r.withRelations().
+		Joins("JOIN airports departure ON departure.id = routes.departure_airport_id AND departure.deleted_at IS NULL").
+		Joins("JOIN airports arrival ON arrival.id = routes.arrival_airport_id AND arrival.deleted_at IS NULL").
+		Where("departure.airport_code = ? AND arrival.airport_code = ?", departureCode, arrivalCode).
+		First(&route)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("route", departureCode+"-"+arrivalCode)
+		}
+		return nil, exceptions.InternalError("failed to get route by airports", result.Error)
+	}
+	return &route, nil
+}
+
+func (r routeRepository) Create(route *models.Route) (*models.Route, error) {
+	result := r.db.Omit("DepartureAirport", "ArrivalAirport", "AllowedAircraftTypes.*").Create(route)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return nil, exceptions.NewAppError(exceptions.CONFLICT, "a route between these airports already exists", nil)
+		}
+		return nil, exceptions.InternalError("failed to create route", result.Error)
+	}
+	return route, nil
+}
+
+// Update saves the route and replaces its allowed aircraft types.
+func (r routeRepository) Update(route *models.Route) (*models.Route, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("DepartureAirport", "ArrivalAirport", "AllowedAircraftTypes").Save(route).Error; err != nil {
+			if isUniqueViolation(err) {
+				return exceptions.NewAppError(exceptions.CONFLICT, "a route between these airports already exists", nil)
+			}
+			return exceptions.InternalError("failed to update route", err)
+		}
+		if err := tx.Model(route).Association("AllowedAircraftTypes").Replace(route.AllowedAircraftTypes); err != nil {
+			return exceptions.InternalError("failed to update allowed aircraft types of route", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+func (r routeRepository) Delete(route *models.Route) error {
+	result := r.db.Delete(route)
+	if result.Error != nil {
+		return exceptions.InternalError("failed to delete route", result.Error)
+	}
+	return nil
+}
+
+func (r routeRepository) CountFlights(routeID uint) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.Flight{}).Where("route_id = ?", routeID).Count(&count)
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to count flights of route", result.Error)
+	}
+	return count, nil
+}
+
+func (r routeRepository) GetDB() *gorm.DB {
+	return r.db
+}
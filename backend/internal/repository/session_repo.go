@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+var errRefreshTokenUsed = errors.New("refresh token already used")
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create starts a session with its first refresh token.
+func (r *sessionRepository) Create(session *models.Session, refreshToken *models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User").Create(session).Error; err != nil {
+			return exceptions.InternalError("failed to create session", err)
+		}
+		refreshToken.SessionID = session.ID
+		if err := tx.Omit("Session").Create(refreshToken).Error; err != nil {
+			return exceptions.InternalError("failed to create refresh token", err)
+		}
+		return nil
+	})
+}
+
+// GetActive returns a session that has not been revoked, with its user.
+func (r *sessionRepository) GetActive(id uint) (*models.Session, error) {
+	var session models.Session
+	result := r.db.Joins("User").Where("sessions.revoked_at IS NULL").First(&session, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("session", strconv.FormatUint(uint64(id), 10))
+		}
+		return nil, exceptions.InternalError("failed to get session", result.Error)
+	}
+	return &session, nil
+}
+
+// GetRefreshToken returns a refresh token with its session and the session's user.
+func (r *sessionRepository) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	result := r.db.Preload("Session.User").Where("token_hash = ?", tokenHash).First(&refreshToken)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("refresh token", "token")
+		}
+		return nil, exceptions.InternalError("failed to get refresh token", result.Error)
+	}
+	return &refreshToken, nil
+}
+
+// Rotate marks a refresh token used and stores the one replacing it. It reports false, storing
+// nothing, when the token was already used, possibly by a concurrent request.
+func (r *sessionRepository) Rotate(used, next *models.RefreshToken) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", used.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return exceptions.InternalError("failed to use refresh token", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errRefreshTokenUsed
+		}
+		next.SessionID = used.SessionID
+		if err := tx.Omit("Session").Create(next).Error; err != nil {
+			return exceptions.InternalError("failed to create refresh token", err)
+		}
+		return nil
+	})
+	if errors.Is(err, errRefreshTokenUsed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *sessionRepository) Revoke(id uint) error {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return exceptions.InternalError("failed to revoke session", result.Error)
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes every open session of a user and returns how many there were.
+func (r *sessionRepository) RevokeAllForUser(userID uint) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to revoke sessions", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// RevokeOthersForUser revokes every open session of a user except one, e.g. the session that
+// changed the password, and returns how many it revoked.
+func (r *sessionRepository) RevokeOthersForUser(userID, keepSessionID uint) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to revoke sessions", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *sessionRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"gorm.io/gorm"
+)
+
+type ticketClassRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketClassRepository(db *gorm.DB) TicketClassRepository {
+	return &ticketClassRepository{db: db}
+}
+
+func (t ticketClassRepository) GetAll() ([]*models.TicketClass, error) {
+	ticketClasses := make([]*models.TicketClass, 0)
+	result := t.db.Order("display_order, ticket_class_name").Find(&ticketClasses)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get all ticket classes", result.Error)
+	}
+	return ticketClasses, nil
+}
+
+func (t ticketClassRepository) GetByID(id uint) (*models.TicketClass, error) {
+	var ticketClass models.TicketClass
+	result := t.db.First(&ticketClass, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("ticket class", strconv.FormatUint(uint64(id), 10))
+		}
+		return nil, exceptions.InternalError("failed to get ticket class by id", result.Error)
+	}
+	return &ticketClass, nil
+}
+
+func (t ticketClassRepository) GetByName(name string) (*models.TicketClass, error) {
+	var ticketClass models.TicketClass
+	result := t.db.Where("ticket_class_name = ?", name).First(&ticketClass)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("ticket class", name)
+		}
+		return nil, exceptions.InternalError("failed to get ticket class by name", result.Error)
+	}
+	return &ticketClass, nil
+}
+
+func (t ticketClassRepository) GetByNames(names []string) (map[string]*models.TicketClass, error) {
+	ticketClasses := make([]*models.TicketClass, 0, len(names))
+	result := t.db.Where("ticket_class_name IN ?", names).Find(&ticketClasses)
+
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get ticket classes by names", result.Error)
+	}
+
+	ticketClassMap := make(map[string]*models.TicketClass, len(ticketClasses))
+	for _, ticketClass := range ticketClasses {
+		ticketClassMap[ticketClass.TicketClassName] = ticketClass
+	}
+
+	return ticketClassMap, nil
+}
+
+// Create adds a ticket class unless the number of classes would exceed maxTicketClasses.
+func (t ticketClassRepository) Create(ticketClass *models.TicketClass, maxTicketClasses int) (*models.TicketClass, error) {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		// Concurrent creations must not push the count over the limit together
+		if err := tx.Exec("LOCK TABLE ticket_classes IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return exceptions.InternalError("failed to lock ticket classes", err)
+		}
+		var count int64
+		if err := tx.Model(&models.TicketClass{}).Count(&count).Error; err != nil {
+			return exceptions.InternalError("failed to count ticket classes", err)
+		}
+		if count >= int64(maxTicketClasses) {
+			return exceptions.NewAppError(exceptions.CONFLICT,
+				fmt.Sprintf("the number of ticket classes is limited to %d", maxTicketClasses), nil)
+		}
+		if err := tx.Omit("Seats").Create(ticketClass).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ticketClassNameTakenError(ticketClass.TicketClassName)
+			}
+			return exceptions.InternalError("failed to create ticket class", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ticketClass, nil
+}
+
+// Update saves the class together with the seat templates of the aircraft types that were
+// changed to follow a rename, so the templates never refer to a name that no longer exists.
+func (t ticketClassRepository) Update(ticketClass *models.TicketClass, templates []*models.AircraftType) (*models.TicketClass, error) {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Seats").Save(ticketClass).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ticketClassNameTakenError(ticketClass.TicketClassName)
+			}
+			return exceptions.InternalError("failed to update ticket class", err)
+		}
+		for _, aircraftType := range templates {
+			if err := tx.Model(aircraftType).Select("SeatLayout").Updates(aircraftType).Error; err != nil {
+				return exceptions.InternalError("failed to update aircraft type seat layout", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ticketClass, nil
+}
+
+func (t ticketClassRepository) Delete(ticketClass *models.TicketClass) error {
+	result := t.db.Delete(ticketClass)
+	if result.Error != nil {
+		return exceptions.InternalError("failed to delete ticket class", result.Error)
+	}
+	return nil
+}
+
+func ticketClassNameTakenError(name string) error {
+	return exceptions.NewAppError(exceptions.CONFLICT, fmt.Sprintf("ticket class '%s' already exists", name), nil)
+}
+
+func (t ticketClassRepository) Count() (int64, error) {
+	var count int64
+	if err := t.db.Model(&models.TicketClass{}).Count(&count).Error; err != nil {
+		return 0, exceptions.InternalError("failed to count ticket classes", err)
+	}
+	return count, nil
+}
+
+// CountSeats counts the seats of the class, blocked seats included.
+func (t ticketClassRepository) CountSeats(ticketClassID uint) (int64, error) {
+	var count int64
+	result := t.db.Model(&models.Seat{}).Where("ticket_class_id = ?", ticketClassID).Count(&count)
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to count seats by ticket class", result.Error)
+	}
+	return count, nil
+}
+
+func (t ticketClassRepository) GetDB() *gorm.DB {
+	return t.db
+}
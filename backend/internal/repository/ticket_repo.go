@@ -3,12 +3,18 @@ package repository
 import (
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// uniqueViolation is the Postgres error code raised when a unique index rejects a write.
+const uniqueViolation = "23505"
+
 type ticketRepository struct {
 	db *gorm.DB
 }
@@ -29,7 +35,10 @@ func (t *ticketRepository) GetByID(id uint) (*models.Ticket, error) {
 	var ticket models.Ticket
 	result := t.db.
 		Preload("Flight").
-		Preload("Seat").
+		Preload("Flight.DepartureAirport").
+		Preload("Flight.ArrivalAirport").
+		Preload("Flight.IntermediateStops.Airport").
+		Preload("Seat.TicketClass").
 		Where("id = ?", id).
 		First(&ticket)
 	if result.Error != nil {
@@ -44,6 +53,9 @@ func (t *ticketRepository) GetByID(id uint) (*models.Ticket, error) {
 func (t *ticketRepository) Create(ticket *models.Ticket) (*models.Ticket, error) {
 	result := t.db.Create(ticket)
 	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return nil, seatTakenError()
+		}
 		return nil, exceptions.InternalError("failed to create ticket", result.Error)
 	}
 	return ticket, nil
@@ -78,6 +90,9 @@ func (t *ticketRepository) GetActiveTicketsByFlightID(flightID uint) ([]*models.
 func (t *ticketRepository) UpdateTicketStatus(ticketID uint, status models.TicketStatus) error {
 	result := t.db.Model(&models.Ticket{}).Where("id = ?", ticketID).Update("ticket_status", status)
 	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return seatTakenError()
+		}
 		return exceptions.InternalError("failed to update ticket status", result.Error)
 	}
 	return nil
@@ -86,6 +101,9 @@ func (t *ticketRepository) UpdateTicketStatus(ticketID uint, status models.Ticke
 func (t *ticketRepository) Update(ticket *models.Ticket) (*models.Ticket, error) {
 	result := t.db.Save(ticket)
 	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return nil, seatTakenError()
+		}
 		return nil, exceptions.InternalError("failed to update ticket", result.Error)
 	}
 	return ticket, nil
@@ -114,6 +132,257 @@ func (t *ticketRepository) GetTicketsByFlightID(flightID uint) ([]*models.Ticket
 	return tickets, nil
 }
 
+func (t *ticketRepository) GetByPassengerID(passengerID uint) ([]*models.Ticket, error) {
+	var tickets []*models.Ticket
+	result := t.db.
+		Preload("Flight").
+		Preload("Flight.DepartureAirport").
+		Preload("Flight.ArrivalAirport").
+		Preload("Seat").
+		Joins("JOIN flights ON flights.id = tickets.flight_id").
+		Where("tickets.passenger_id = ?", passengerID).
+		Order("flights.departure_date_time DESC").
+		Find(&tickets)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get tickets by passenger ID", result.Error)
+	}
+	return tickets, nil
+}
+
+func (t *ticketRepository) GetByIDCardIndex(index string) ([]*models.Ticket, error) {
+	var tickets []*models.Ticket
+	result := t.db.
+		Preload("Flight").
+		Preload("Seat").
+		Where("id_card_index = ?", index).
+		Order("id").
+		Find(&tickets)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get tickets by ID card", result.Error)
+	}
+	return tickets, nil
+}
+
+// ReencryptPII rewrites, in batches of batchSize, every ticket holding a PII value that was
+// not encrypted with the key identified by activePrefix, and returns the number of tickets rewritten.
+func (t *ticketRepository) ReencryptPII(activePrefix string, batchSize int) (int, error) {
+	pattern := activePrefix + "%"
+	var lastID uint
+	rewritten := 0
+	for {
+		var tickets []*models.Ticket
+		result := t.db.
+			Where("id > ?", lastID).
+			Where("(id_card <> '' AND id_card NOT LIKE ?) OR (phone_number <> '' AND phone_number NOT LIKE ?) OR (email <> '' AND email NOT LIKE ?)",
+				pattern, pattern, pattern).
+			Order("id").
+			Limit(batchSize).
+			Find(&tickets)
+		if result.Error != nil {
+			return rewritten, exceptions.InternalError("failed to load tickets for re-encryption", result.Error)
+		}
+		if len(tickets) == 0 {
+			return rewritten, nil
+		}
+
+		err := t.db.Transaction(func(tx *gorm.DB) error {
+			for _, ticket := range tickets {
+				if err := tx.Model(ticket).Select("IDCard", "IDCardIndex", "PhoneNumber", "Email", "EmailIndex").Updates(ticket).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return rewritten, exceptions.InternalError("failed to re-encrypt tickets", err)
+		}
+		rewritten += len(tickets)
+		lastID = tickets[len(tickets)-1].ID
+	}
+}
+
+func (t *ticketRepository) GetBySubject(emailIndex, idCardIndex string) ([]*models.Ticket, error) {
+	var tickets []*models.Ticket
+	result := t.db.
+		Preload("Flight").
+		Preload("Flight.DepartureAirport").
+		Preload("Flight.ArrivalAirport").
+		Preload("Seat.TicketClass").
+		Scopes(matchSubject(emailIndex, idCardIndex)).
+		Order("id").
+		Find(&tickets)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get tickets by subject", result.Error)
+	}
+	return tickets, nil
+}
+
+func (t *ticketRepository) CountUnanonymizedBySubject(emailIndex, idCardIndex string) (int64, error) {
+	var count int64
+	result := t.db.Model(&models.Ticket{}).
+		Scopes(matchSubject(emailIndex, idCardIndex)).
+		Where("anonymized_at IS NULL").
+		Count(&count)
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to count tickets by subject", result.Error)
+	}
+	return count, nil
+}
+
+func (t *ticketRepository) AnonymizeBySubject(emailIndex, idCardIndex string, departedBefore time.Time) (int64, error) {
+	return t.anonymize(t.db.Scopes(matchSubject(emailIndex, idCardIndex)), departedBefore)
+}
+
+func (t *ticketRepository) AnonymizeDepartedBefore(departedBefore time.Time) (int64, error) {
+	return t.anonymize(t.db, departedBefore)
+}
+
+// anonymize erases the personal data of the tickets selected by scope whose flight departed
+// before the cutoff. Price, status and seat are kept so revenue reports are unaffected.
+func (t *ticketRepository) anonymize(scope *gorm.DB, departedBefore time.Time) (int64, error) {
+	departed := t.db.Model(&models.Flight{}).
+		Select("id").
+		Where("departure_date_time < ?", departedBefore)
+	result := scope.Model(&models.Ticket{}).
+		Where("anonymized_at IS NULL AND flight_id IN (?)", departed).
+		Updates(map[string]interface{}{
+			"full_name":     models.AnonymizedName,
+			"id_card":       "",
+			"id_card_index": "",
+			"phone_number":  "",
+			"email":         "",
+			"email_index":   "",
+			"passenger_id":  nil,
+			"anonymized_at": time.Now(),
+		})
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to anonymize tickets", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetActiveByFlightIDs returns the active tickets on each of the given flights, with only
+// their ID, flight and booking type loaded. Flights without active tickets are left out.
+func (t *ticketRepository) GetActiveByFlightIDs(flightIDs []uint) (map[uint][]*models.Ticket, error) {
+	tickets := make(map[uint][]*models.Ticket)
+	if len(flightIDs) == 0 {
+		return tickets, nil
+	}
+
+	var rows []*models.Ticket
+	result := t.db.
+		Select("id, flight_id, booking_type").
+		Where("flight_id IN ? AND ticket_status = ?", flightIDs, models.TicketStatusActive).
+		Order("id").
+		Find(&rows)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get active tickets by flight", result.Error)
+	}
+	for _, row := range rows {
+		tickets[row.FlightID] = append(tickets[row.FlightID], row)
+	}
+	return tickets, nil
+}
+
+// CountActiveByFlightIDs returns the number of active tickets on each of the given flights.
+// Flights without active tickets are left out.
+func (t *ticketRepository) CountActiveByFlightIDs(flightIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int)
+	if len(flightIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		FlightID uint
+		Count    int
+	}
+	result := t.db.Model(&models.Ticket{}).
+		Select("flight_id, COUNT(*) AS count").
+		Where("flight_id IN ? AND ticket_status = ?", flightIDs, models.TicketStatusActive).
+		Group("flight_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to count active tickets by flight", result.Error)
+	}
+	for _, row := range rows {
+		counts[row.FlightID] = row.Count
+	}
+	return counts, nil
+}
+
+func (t *ticketRepository) GetByBookingReference(reference string) ([]*models.Ticket, error) {
+	var tickets []*models.Ticket
+	result := t.db.
+		Preload("Flight").
+		Preload("Seat").
+		Where("booking_reference = ?", reference).
+		Order("id").
+		Find(&tickets)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get tickets by booking reference", result.Error)
+	}
+	return tickets, nil
+}
+
+// CreateWithSeats inserts the tickets of one booking while holding a lock on the flight row.
+// assign receives the transaction and the seats already held by active tickets on the flight,
+// and must set the seat and price of every ticket; what it writes with tx is committed or
+// rolled back with the tickets. Concurrent bookings of the same flight wait for the lock,
+// so they always see each other's seats.
+func (t *ticketRepository) CreateWithSeats(flightID uint, tickets []*models.Ticket, assign func(tx *gorm.DB, occupied map[uint]bool) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		var flight models.Flight
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&flight, flightID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exceptions.NotFoundError("flight", strconv.Itoa(int(flightID)))
+			}
+			return exceptions.InternalError("failed to lock flight", err)
+		}
+
+		var seatIDs []uint
+		if err := tx.Model(&models.Ticket{}).
+			Where("flight_id = ? AND ticket_status = ?", flightID, models.TicketStatusActive).
+			Pluck("seat_id", &seatIDs).Error; err != nil {
+			return exceptions.InternalError("failed to get booked seats", err)
+		}
+		occupied := make(map[uint]bool, len(seatIDs))
+		for _, id := range seatIDs {
+			occupied[id] = true
+		}
+
+		if err := assign(tx, occupied); err != nil {
+			return err
+		}
+		if err := tx.Create(tickets).Error; err != nil {
+			if isUniqueViolation(err) {
+				return seatTakenError()
+			}
+			return exceptions.InternalError("failed to create tickets", err)
+		}
+		return nil
+	})
+}
+
+// matchSubject selects tickets whose email or ID card matches the given blind indexes.
+// Empty indexes never match.
+func matchSubject(emailIndex, idCardIndex string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(email_index = ? AND email_index <> '') OR (id_card_index = ? AND id_card_index <> '')",
+			emailIndex, idCardIndex)
+	}
+}
+
+// isUniqueViolation reports whether err was raised by a unique index, such as the index
+// allowing one active ticket per seat and flight.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+func seatTakenError() error {
+	return exceptions.NewAppError(exceptions.CONFLICT, "seat is already booked for this flight", nil)
+}
+
 func NewTicketRepository(db *gorm.DB) TicketRepository {
 	return &ticketRepository{db: db}
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/models"
 	"gorm.io/gorm"
 )
@@ -13,8 +14,15 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
+// Create stores a new user. A username or email taken by a concurrent request is a conflict.
 func (r *userRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+	if err := r.db.Create(user).Error; err != nil {
+		if isUniqueViolation(err) {
+			return userExistsError()
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *userRepository) GetByUsername(username string) (*models.User, error) {
@@ -44,14 +52,88 @@ func (r *userRepository) GetByID(id uint) (*models.User, error) {
 	return &user, nil
 }
 
+// Search lists users whose username or email contains the search text, optionally only those
+// with the given role.
+func (r *userRepository) Search(search string, role models.Role) ([]*models.User, error) {
+	users := make([]*models.User, 0)
+	query := r.db.Order("username")
+	if search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("username ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if err := query.Find(&users).Error; err != nil {
+		return nil, exceptions.InternalError("failed to search users", err)
+	}
+	return users, nil
+}
+
+func (r *userRepository) CountEnabledByRole(role models.Role) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.User{}).Where("role = ? AND disabled_at IS NULL", role).Count(&count)
+	if result.Error != nil {
+		return 0, exceptions.InternalError("failed to count users by role", result.Error)
+	}
+	return count, nil
+}
+
 func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdateKeepingSuperAdmin saves a change to a user's role or status unless it leaves no
+// enabled super admin.
+func (r *userRepository) UpdateKeepingSuperAdmin(user *models.User) error {
+	return r.keepingSuperAdmin(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return exceptions.InternalError("failed to update user", err)
+		}
+		return nil
+	})
+}
+
 func (r *userRepository) Delete(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
 
+// DeleteKeepingSuperAdmin deletes a user unless no enabled super admin would remain.
+func (r *userRepository) DeleteKeepingSuperAdmin(user *models.User) error {
+	return r.keepingSuperAdmin(func(tx *gorm.DB) error {
+		if err := tx.Delete(user).Error; err != nil {
+			return exceptions.InternalError("failed to delete user", err)
+		}
+		return nil
+	})
+}
+
+// keepingSuperAdmin runs a change to the users and undoes it when no enabled super admin is
+// left afterwards.
+func (r *userRepository) keepingSuperAdmin(change func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Two super admins demoting each other at once must not both succeed
+		if err := tx.Exec("LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return exceptions.InternalError("failed to lock users", err)
+		}
+		if err := change(tx); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.User{}).Where("role = ? AND disabled_at IS NULL", models.RoleSuperAdmin).Count(&count).Error; err != nil {
+			return exceptions.InternalError("failed to count super admins", err)
+		}
+		if count == 0 {
+			return exceptions.NewAppError(exceptions.CONFLICT, "the last enabled super admin cannot be demoted, disabled or deleted", nil)
+		}
+		return nil
+	})
+}
+
+func userExistsError() error {
+	return exceptions.NewAppError(exceptions.CONFLICT, "User already exists", nil)
+}
+
 func (r *userRepository) GetDB() *gorm.DB {
 	return r.db
 }
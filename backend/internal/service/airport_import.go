@@ -0,0 +1,340 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/pkg/config"
+)
+
+// maxReportedImportErrors caps the invalid rows listed when an import is refused.
+const maxReportedImportErrors = 100
+
+// defaultImportedAirportTypes are the OurAirports types imported when none are requested.
+var defaultImportedAirportTypes = []string{"large_airport", "medium_airport"}
+
+// airportRow is a validated row of an OurAirports airports.csv file.
+type airportRow struct {
+	line        int
+	airportCode string
+	icaoCode    string
+	name        string
+	city        string
+	country     string // ISO 3166-1 alpha-2 code
+	latitude    float64
+	longitude   float64
+	elevationFt *int
+}
+
+// ImportAirports creates and updates airports from an OurAirports CSV file. Airports are
+// matched on their IATA code. The airports file only has ISO country codes, so new airports
+// take their country name from the countries file; updated airports keep theirs when it does
+// not name the country. The whole file is refused when a row is invalid, and nothing is saved
+// when the import would exceed the number of airports allowed by the parameters.
+func (a airportService) ImportAirports(request *dto.AirportImportRequest) (*dto.AirportImportResponse, error) {
+	directory := config.GetConfig().Airports.ImportDirectory
+	if directory == "" {
+		return nil, exceptions.BadRequestError("airport import directory is not configured", nil)
+	}
+	root, err := os.OpenRoot(directory)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("airport import directory %s does not exist", directory), err)
+		}
+		return nil, exceptions.InternalError("failed to open airport import directory", err)
+	}
+	defer root.Close()
+
+	countryNames := make(map[string]string)
+	if request.CountriesFile != "" {
+		if countryNames, err = readCountryNames(root, request.CountriesFile); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := openImportFile(root, request.File)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	types := request.Types
+	if len(types) == 0 {
+		types = defaultImportedAirportTypes
+	}
+	countries := make([]string, len(request.Countries))
+	for i, country := range request.Countries {
+		countries[i] = strings.ToUpper(country)
+	}
+	rows, skipped, rowErrors, err := readAirportRows(file, types, countries)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := a.airportRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	byAirportCode := make(map[string]*models.Airport, len(existing))
+	byIcaoCode := make(map[string]*models.Airport, len(existing))
+	for _, airport := range existing {
+		byAirportCode[airport.AirportCode] = airport
+		if airport.IcaoCode != nil {
+			byIcaoCode[*airport.IcaoCode] = airport
+		}
+	}
+
+	response := &dto.AirportImportResponse{Skipped: skipped, DryRun: request.DryRun}
+	airports := make([]*models.Airport, 0, len(rows))
+	for _, row := range rows {
+		if owner, ok := byIcaoCode[row.icaoCode]; ok && owner.AirportCode != row.airportCode {
+			rowErrors = append(rowErrors, dto.AirportImportError{
+				Line:    row.line,
+				Message: fmt.Sprintf("ICAO code %s is already used by airport %s", row.icaoCode, owner.AirportCode),
+			})
+			continue
+		}
+
+		countryName, named := countryNames[row.country]
+		airport, ok := byAirportCode[row.airportCode]
+		if ok {
+			response.Updated++
+		} else if named {
+			airport = &models.Airport{AirportCode: row.airportCode}
+			response.Created++
+		} else {
+			message := fmt.Sprintf("country %s is not named in the countries file", row.country)
+			if request.CountriesFile == "" {
+				message = fmt.Sprintf("a countries file is required to create airport %s", row.airportCode)
+			}
+			rowErrors = append(rowErrors, dto.AirportImportError{Line: row.line, Message: message})
+			continue
+		}
+		if row.icaoCode != "" {
+			icaoCode := row.icaoCode
+			airport.IcaoCode = &icaoCode
+		}
+		airport.AirportName = row.name
+		airport.CityName = row.city
+		airport.CountryCode = row.country
+		if named {
+			airport.CountryName = countryName
+		}
+		latitude, longitude := row.latitude, row.longitude
+		airport.Latitude = &latitude
+		airport.Longitude = &longitude
+		if row.elevationFt != nil {
+			airport.ElevationFt = row.elevationFt
+		}
+		airports = append(airports, airport)
+	}
+	if len(rowErrors) > 0 {
+		slices.SortFunc(rowErrors, func(a, b dto.AirportImportError) int { return a.Line - b.Line })
+		message := fmt.Sprintf("%d rows of %s are invalid, nothing was imported", len(rowErrors), request.File)
+		return nil, exceptions.NewAppError(exceptions.BadRequest, message, rowErrors[:min(len(rowErrors), maxReportedImportErrors)])
+	}
+
+	params, err := a.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+	if request.DryRun {
+		if len(existing)+response.Created > params.NumberOfAirports {
+			return nil, exceptions.NewAppError(exceptions.CONFLICT,
+				fmt.Sprintf("importing %d new airports would exceed the limit of %d airports", response.Created, params.NumberOfAirports), nil)
+		}
+		return response, nil
+	}
+	if err := a.airportRepo.Upsert(airports, params.NumberOfAirports); err != nil {
+		return nil, err
+	}
+	a.searchCache.invalidate()
+	return response, nil
+}
+
+func openImportFile(root *os.Root, name string) (*os.File, error) {
+	file, err := root.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, exceptions.NotFoundError("import file", name)
+		}
+		return nil, exceptions.BadRequestError(fmt.Sprintf("cannot open import file %s", name), err)
+	}
+	return file, nil
+}
+
+// readAirportRows reads the airports of the requested types and countries that have an IATA
+// code. Rows left out are counted as skipped; invalid rows are reported with their line.
+func readAirportRows(file io.Reader, types, countries []string) ([]airportRow, int, []dto.AirportImportError, error) {
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, nil, exceptions.BadRequestError("cannot read the CSV header", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"type", "name", "latitude_deg", "longitude_deg", "iso_country", "municipality", "iata_code"} {
+		if _, ok := columns[name]; !ok {
+			return nil, 0, nil, exceptions.BadRequestError(fmt.Sprintf("CSV column %s is missing", name), nil)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []airportRow
+	var rowErrors []dto.AirportImportError
+	skipped := 0
+	lineByAirportCode := make(map[string]int)
+	lineByIcaoCode := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, nil, exceptions.BadRequestError("cannot parse the CSV file", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		airportCode := strings.ToUpper(field(record, "iata_code"))
+		country := strings.ToUpper(field(record, "iso_country"))
+		if airportCode == "" || !slices.Contains(types, field(record, "type")) ||
+			(len(countries) > 0 && !slices.Contains(countries, country)) {
+			skipped++
+			continue
+		}
+
+		row := airportRow{
+			line:        line,
+			airportCode: airportCode,
+			icaoCode:    icaoCodeOf(field(record, "icao_code"), field(record, "gps_code"), field(record, "ident")),
+			name:        field(record, "name"),
+			city:        field(record, "municipality"),
+			country:     country,
+		}
+		message := validateAirportRow(&row, field(record, "latitude_deg"), field(record, "longitude_deg"), field(record, "elevation_ft"))
+		if message != "" {
+			rowErrors = append(rowErrors, dto.AirportImportError{Line: line, Message: message})
+			continue
+		}
+		if first, ok := lineByAirportCode[row.airportCode]; ok {
+			rowErrors = append(rowErrors, dto.AirportImportError{
+				Line:    line,
+				Message: fmt.Sprintf("IATA code %s already appears on line %d", row.airportCode, first),
+			})
+			continue
+		}
+		if first, ok := lineByIcaoCode[row.icaoCode]; ok && row.icaoCode != "" {
+			rowErrors = append(rowErrors, dto.AirportImportError{
+				Line:    line,
+				Message: fmt.Sprintf("ICAO code %s already appears on line %d", row.icaoCode, first),
+			})
+			continue
+		}
+		lineByAirportCode[row.airportCode] = line
+		if row.icaoCode != "" {
+			lineByIcaoCode[row.icaoCode] = line
+		}
+		rows = append(rows, row)
+	}
+	return rows, skipped, rowErrors, nil
+}
+
+// validateAirportRow parses the coordinates and the optional elevation into the row and
+// returns why the row is invalid, or an empty string.
+func validateAirportRow(row *airportRow, latitude, longitude, elevation string) string {
+	if !isLetterCode(row.airportCode, 3) {
+		return fmt.Sprintf("IATA code %q must be 3 letters", row.airportCode)
+	}
+	if row.name == "" {
+		return "name is required"
+	}
+	if row.city == "" {
+		return "municipality is required"
+	}
+	if len(row.country) != 2 {
+		return fmt.Sprintf("country code %q must be 2 letters", row.country)
+	}
+
+	var err error
+	if row.latitude, err = strconv.ParseFloat(latitude, 64); err != nil || row.latitude < -90 || row.latitude > 90 {
+		return fmt.Sprintf("latitude %q is not between -90 and 90", latitude)
+	}
+	if row.longitude, err = strconv.ParseFloat(longitude, 64); err != nil || row.longitude < -180 || row.longitude > 180 {
+		return fmt.Sprintf("longitude %q is not between -180 and 180", longitude)
+	}
+	if elevation != "" {
+		elevationFt, err := strconv.Atoi(elevation)
+		if err != nil {
+			return fmt.Sprintf("elevation %q is not a whole number of feet", elevation)
+		}
+		row.elevationFt = &elevationFt
+	}
+	return ""
+}
+
+// icaoCodeOf returns the first candidate that looks like an ICAO code. Older OurAirports
+// files have no icao_code column, and the ident of airports without one is a local code.
+func icaoCodeOf(candidates ...string) string {
+	for _, candidate := range candidates {
+		if candidate = strings.ToUpper(candidate); isLetterCode(candidate, 4) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func isLetterCode(code string, length int) bool {
+	if len(code) != length {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// readCountryNames maps ISO country codes to names using an OurAirports countries.csv file.
+func readCountryNames(root *os.Root, name string) (map[string]string, error) {
+	file, err := openImportFile(root, name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, exceptions.BadRequestError(fmt.Sprintf("cannot parse %s", name), err)
+	}
+	if len(records) == 0 {
+		return nil, exceptions.BadRequestError(fmt.Sprintf("%s is empty", name), nil)
+	}
+	codeColumn, nameColumn := slices.Index(records[0], "code"), slices.Index(records[0], "name")
+	if codeColumn < 0 || nameColumn < 0 {
+		return nil, exceptions.BadRequestError(fmt.Sprintf("%s needs code and name columns", name), nil)
+	}
+
+	countryNames := make(map[string]string, len(records)-1)
+	for _, record := range records[1:] {
+		countryNames[strings.ToUpper(record[codeColumn])] = strings.TrimSpace(record[nameColumn])
+	}
+	return countryNames, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/pkg/textfold"
+)
+
+const (
+	// defaultAirportSearchLimit is the number of airports returned when no limit is given.
+	defaultAirportSearchLimit = 10
+	// maxCachedAirportSearches bounds the number of search results kept in memory.
+	maxCachedAirportSearches = 1000
+)
+
+// airportMatch ranks how well an airport matches a search, best first.
+type airportMatch int
+
+const (
+	matchCodeExact   airportMatch = iota // The query is the IATA or ICAO code
+	matchCodePrefix                      // A code starts with the query
+	matchFieldPrefix                     // The name, city or country starts with the query
+	matchWordPrefix                      // Every query word starts a word of the airport
+	matchSubstring                       // The query appears inside the name, city or country
+	matchFuzzy                           // Every query word is a typo away from a word of the airport
+	noMatch
+)
+
+// searchableAirport is an airport with its codes and names folded for matching.
+type searchableAirport struct {
+	response *dto.AirportResponse
+	codes    []string
+	fields   []string // Name, city and country
+	compact  []string // Fields without spaces, so "danang" finds "Da Nang"
+	words    []string
+}
+
+// airportSearchCache keeps the folded airports and recent search results in memory. It is
+// emptied whenever an airport is created, changed, deleted or imported.
+type airportSearchCache struct {
+	mu         sync.RWMutex
+	generation int // Incremented on invalidation, so loads started before it are not kept
+	airports   []searchableAirport
+	results    map[string][]*dto.AirportResponse
+}
+
+func (c *airportSearchCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.airports = nil
+	c.results = nil
+}
+
+// SearchAirports returns the airports matching the query on code, name, city or country,
+// ignoring case and diacritics. Exact and prefix matches come first, then matches inside
+// words, then matches with a typo.
+func (a airportService) SearchAirports(query *dto.AirportSearchQuery) ([]*dto.AirportResponse, error) {
+	folded := textfold.Fold(query.Q)
+	if folded == "" {
+		return []*dto.AirportResponse{}, nil
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAirportSearchLimit
+	}
+	key := strconv.Itoa(limit) + ":" + folded
+
+	cache := a.searchCache
+	cache.mu.RLock()
+	cached, ok := cache.results[key]
+	airports, generation := cache.airports, cache.generation
+	cache.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	if airports == nil {
+		loaded, err := a.airportRepo.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		airports = make([]searchableAirport, len(loaded))
+		for i, airport := range loaded {
+			response := toAirportResponse(airport)
+			searchable := searchableAirport{
+				response: response,
+				codes:    []string{textfold.Fold(response.AirportCode)},
+				fields:   []string{textfold.Fold(response.AirportName), textfold.Fold(response.CityName), textfold.Fold(response.CountryName)},
+			}
+			if response.IcaoCode != "" {
+				searchable.codes = append(searchable.codes, textfold.Fold(response.IcaoCode))
+			}
+			for _, field := range searchable.fields {
+				searchable.compact = append(searchable.compact, strings.ReplaceAll(field, " ", ""))
+				searchable.words = append(searchable.words, strings.Fields(field)...)
+			}
+			airports[i] = searchable
+		}
+	}
+
+	type rankedAirport struct {
+		airport *dto.AirportResponse
+		match   airportMatch
+	}
+	var ranked []rankedAirport
+	for i := range airports {
+		if match := matchAirport(&airports[i], folded); match != noMatch {
+			ranked = append(ranked, rankedAirport{airport: airports[i].response, match: match})
+		}
+	}
+	slices.SortFunc(ranked, func(x, y rankedAirport) int {
+		return cmp.Or(cmp.Compare(x.match, y.match), strings.Compare(x.airport.AirportCode, y.airport.AirportCode))
+	})
+
+	results := make([]*dto.AirportResponse, 0, min(len(ranked), limit))
+	for _, r := range ranked[:min(len(ranked), limit)] {
+		results = append(results, r.airport)
+	}
+
+	cache.mu.Lock()
+	if cache.generation == generation {
+		cache.airports = airports
+		if cache.results == nil || len(cache.results) >= maxCachedAirportSearches {
+			cache.results = make(map[string][]*dto.AirportResponse)
+		}
+		cache.results[key] = results
+	}
+	cache.mu.Unlock()
+	return results, nil
+}
+
+// matchAirport tells how well the airport matches a folded query.
+func matchAirport(airport *searchableAirport, query string) airportMatch {
+	for _, code := range airport.codes {
+		if code == query {
+			return matchCodeExact
+		}
+	}
+	for _, code := range airport.codes {
+		if strings.HasPrefix(code, query) {
+			return matchCodePrefix
+		}
+	}
+	compactQuery := strings.ReplaceAll(query, " ", "")
+	for i, field := range airport.fields {
+		if strings.HasPrefix(field, query) || strings.HasPrefix(airport.compact[i], compactQuery) {
+			return matchFieldPrefix
+		}
+	}
+
+	tokens := strings.Fields(query)
+	if allTokens(tokens, airport.words, strings.HasPrefix) {
+		return matchWordPrefix
+	}
+	for _, field := range airport.fields {
+		if strings.Contains(field, query) {
+			return matchSubstring
+		}
+	}
+	if allTokens(tokens, airport.words, withinTypos) || allTokens([]string{compactQuery}, airport.compact, withinTypos) {
+		return matchFuzzy
+	}
+	return noMatch
+}
+
+// allTokens reports whether every query token matches at least one of the words.
+func allTokens(tokens, words []string, matches func(word, token string) bool) bool {
+	for _, token := range tokens {
+		if !slices.ContainsFunc(words, func(word string) bool { return matches(word, token) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// withinTypos reports whether the token is close enough to the word, or to the start of the
+// word while it is still being typed. Short tokens must match exactly.
+func withinTypos(word, token string) bool {
+	t := []rune(token)
+	allowed := 0
+	switch {
+	case len(t) >= 8:
+		allowed = 2
+	case len(t) >= 4:
+		allowed = 1
+	default:
+		return false
+	}
+	w := []rune(word)
+	return editDistance(w, t) <= allowed || (len(w) > len(t) && editDistance(w[:len(t)], t) <= allowed)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitution := previous[j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -1,19 +1,30 @@
 package service
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/internal/repository"
 )
 
 type airportService struct {
 	airportRepo repository.AirportRepository
+	paramRepo   repository.ParameterRepository
+	searchCache *airportSearchCache
 }
 
-func NewAirportService(airportRepo repository.AirportRepository) AirportService {
-	if airportRepo == nil {
+func NewAirportService(airportRepo repository.AirportRepository, paramRepo repository.ParameterRepository) AirportService {
+	if airportRepo == nil || paramRepo == nil {
 		panic("Missing required repositories for airport service")
 	}
-	return &airportService{airportRepo: airportRepo}
+	return &airportService{
+		airportRepo: airportRepo,
+		paramRepo:   paramRepo,
+		searchCache: &airportSearchCache{},
+	}
 }
 
 func (a airportService) GetAllAirports() ([]*dto.AirportResponse, error) {
@@ -24,12 +35,7 @@ func (a airportService) GetAllAirports() ([]*dto.AirportResponse, error) {
 
 	airportResponses := make([]*dto.AirportResponse, len(airports))
 	for i, airport := range airports {
-		airportResponses[i] = &dto.AirportResponse{
-			AirportCode: airport.AirportCode,
-			AirportName: airport.AirportName,
-			CityName:    airport.CityName,
-			CountryName: airport.CountryName,
-		}
+		airportResponses[i] = toAirportResponse(airport)
 	}
 	return airportResponses, nil
 }
@@ -39,14 +45,7 @@ func (a airportService) GetAirportByCode(code string) (*dto.AirportResponse, err
 	if err != nil {
 		return nil, err
 	}
-
-	airportResponse := &dto.AirportResponse{
-		AirportCode: airport.AirportCode,
-		AirportName: airport.AirportName,
-		CityName:    airport.CityName,
-		CountryName: airport.CountryName,
-	}
-	return airportResponse, nil
+	return toAirportResponse(airport), nil
 }
 
 func (a airportService) GetAirportsByCodes(codes []string) (map[string]*dto.AirportResponse, error) {
@@ -56,12 +55,119 @@ func (a airportService) GetAirportsByCodes(codes []string) (map[string]*dto.Airp
 	}
 	airportResponses := make(map[string]*dto.AirportResponse, len(airports))
 	for _, airport := range airports {
-		airportResponses[airport.AirportCode] = &dto.AirportResponse{
-			AirportCode: airport.AirportCode,
-			AirportName: airport.AirportName,
-			CityName:    airport.CityName,
-			CountryName: airport.CountryName,
-		}
+		airportResponses[airport.AirportCode] = toAirportResponse(airport)
 	}
 	return airportResponses, nil
 }
+
+// CreateAirport adds an airport, refusing once the number of airports allowed by the
+// parameters is reached.
+func (a airportService) CreateAirport(request *dto.AirportRequest) (*dto.AirportResponse, error) {
+	airport := &models.Airport{}
+	applyAirportRequest(airport, request)
+	if err := a.ensureCodesAvailable(airport); err != nil {
+		return nil, err
+	}
+
+	params, err := a.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+	if err := a.airportRepo.Upsert([]*models.Airport{airport}, params.NumberOfAirports); err != nil {
+		return nil, err
+	}
+	a.searchCache.invalidate()
+	return toAirportResponse(airport), nil
+}
+
+func (a airportService) UpdateAirport(code string, request *dto.AirportRequest) (*dto.AirportResponse, error) {
+	airport, err := a.airportRepo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	applyAirportRequest(airport, request)
+	if err := a.ensureCodesAvailable(airport); err != nil {
+		return nil, err
+	}
+	if _, err := a.airportRepo.Update(airport); err != nil {
+		return nil, err
+	}
+	a.searchCache.invalidate()
+	return toAirportResponse(airport), nil
+}
+
+// DeleteAirport removes an airport no flight or route uses.
+func (a airportService) DeleteAirport(code string) error {
+	airport, err := a.airportRepo.GetByCode(code)
+	if err != nil {
+		return err
+	}
+	inUse, err := a.airportRepo.IsInUse(airport.ID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("airport '%s' is used by flights or routes and cannot be deleted", airport.AirportCode), nil)
+	}
+	if err := a.airportRepo.Delete(airport); err != nil {
+		return err
+	}
+	a.searchCache.invalidate()
+	return nil
+}
+
+// ensureCodesAvailable checks that no other airport has the airport's IATA or ICAO code.
+func (a airportService) ensureCodesAvailable(airport *models.Airport) error {
+	existing, err := a.airportRepo.GetByCode(airport.AirportCode)
+	if err == nil && existing.ID != airport.ID {
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("airport code '%s' is already in use", airport.AirportCode), nil)
+	} else if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	if airport.IcaoCode == nil {
+		return nil
+	}
+	existing, err = a.airportRepo.GetByIcaoCode(*airport.IcaoCode)
+	if err == nil && existing.ID != airport.ID {
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("ICAO code '%s' is already used by airport '%s'", *airport.IcaoCode, existing.AirportCode), nil)
+	} else if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func applyAirportRequest(airport *models.Airport, request *dto.AirportRequest) {
+	airport.AirportCode = strings.ToUpper(strings.TrimSpace(request.AirportCode))
+	airport.IcaoCode = nil
+	if icaoCode := strings.ToUpper(strings.TrimSpace(request.IcaoCode)); icaoCode != "" {
+		airport.IcaoCode = &icaoCode
+	}
+	airport.AirportName = strings.TrimSpace(request.AirportName)
+	airport.CityName = strings.TrimSpace(request.CityName)
+	airport.CountryName = strings.TrimSpace(request.CountryName)
+	airport.CountryCode = strings.ToUpper(strings.TrimSpace(request.CountryCode))
+	airport.Latitude = request.Latitude
+	airport.Longitude = request.Longitude
+	airport.ElevationFt = request.ElevationFt
+}
+
+func toAirportResponse(airport *models.Airport) *dto.AirportResponse {
+	response := &dto.AirportResponse{
+		AirportCode: airport.AirportCode,
+		AirportName: airport.AirportName,
+		CityName:    airport.CityName,
+		CountryName: airport.CountryName,
+		CountryCode: airport.CountryCode,
+		Latitude:    airport.Latitude,
+		Longitude:   airport.Longitude,
+		ElevationFt: airport.ElevationFt,
+	}
+	if airport.IcaoCode != nil {
+		response.IcaoCode = *airport.IcaoCode
+	}
+	return response
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/repository"
+)
+
+const (
+	// auditActorSystem is the actor of events the application records on its own
+	auditActorSystem = "system"
+
+	defaultAuditLogLimit = 100
+)
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	if auditRepo == nil {
+		panic("Missing required repositories for audit service")
+	}
+	return &auditService{auditRepo: auditRepo}
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func (a auditService) SearchAuditLog(query *dto.AuditLogQuery) ([]*dto.AuditLogResponse, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultAuditLogLimit
+	}
+	entries, err := a.auditRepo.Search(query.Action, query.Subject, limit)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.AuditLogResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = toAuditLogResponse(entry)
+	}
+	return responses, nil
+}
+
+func toAuditLogResponse(entry *models.AuditLog) *dto.AuditLogResponse {
+	return &dto.AuditLogResponse{
+		ID:        entry.ID,
+		Action:    entry.Action,
+		Actor:     entry.Actor,
+		Subject:   entry.Subject,
+		IPAddress: entry.IPAddress,
+		Details:   entry.Details,
+		CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+	}
+}
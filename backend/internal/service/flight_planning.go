@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/pkg/geo"
+)
+
+const (
+	// flightOverheadMinutes covers taxi, climb and approach on every leg.
+	flightOverheadMinutes = 30
+	// durationRoundingMinutes is the step suggested durations are rounded up to.
+	durationRoundingMinutes = 5
+	// maxGroundSpeedKmh is the fastest an airliner covers ground, helped by a strong tailwind.
+	maxGroundSpeedKmh = 1200
+	// slowDurationFactor is how many times the expected time in the air a flight may take
+	// before its duration is flagged.
+	slowDurationFactor = 2
+)
+
+// routeAirports lists the airports of a flight in the order they are visited.
+func routeAirports(flight *models.Flight) []*models.Airport {
+	stops := slices.Clone(flight.IntermediateStops)
+	slices.SortFunc(stops, func(a, b models.IntermediateStop) int {
+		return a.StopOrder - b.StopOrder
+	})
+
+	airports := make([]*models.Airport, 0, len(stops)+2)
+	airports = append(airports, &flight.DepartureAirport)
+	for i := range stops {
+		airports = append(airports, &stops[i].Airport)
+	}
+	return append(airports, &flight.ArrivalAirport)
+}
+
+// legDistance returns the great-circle distance between two airports, and false when either
+// has no coordinates.
+func legDistance(from, to *models.Airport) (float64, bool) {
+	if from.Latitude == nil || from.Longitude == nil || to.Latitude == nil || to.Longitude == nil {
+		return 0, false
+	}
+	return geo.DistanceKm(*from.Latitude, *from.Longitude, *to.Latitude, *to.Longitude), true
+}
+
+// routeDistance returns the segments between consecutive airports of a route and their total
+// great-circle distance in whole kilometres. The total is 0 when an airport has no coordinates.
+func routeDistance(airports []*models.Airport) (float64, []dto.FlightSegmentDTO) {
+	segments := make([]dto.FlightSegmentDTO, 0, len(airports)-1)
+	total, complete := 0.0, true
+	for i := 1; i < len(airports); i++ {
+		segment := dto.FlightSegmentDTO{From: airports[i-1].AirportCode, To: airports[i].AirportCode}
+		if distance, ok := legDistance(airports[i-1], airports[i]); ok {
+			segment.DistanceKm = math.Round(distance)
+			total += distance
+		} else {
+			complete = false
+		}
+		segments = append(segments, segment)
+	}
+	if !complete {
+		return 0, segments
+	}
+	return math.Round(total), segments
+}
+
+// flightDistance returns the distance and segments of a flight loaded with its airports and
+// intermediate stops.
+func flightDistance(flight *models.Flight) (float64, []dto.FlightSegmentDTO) {
+	return routeDistance(routeAirports(flight))
+}
+
+// checkFlightDuration verifies that the time in the air left by a flight's duration once its
+// stops are taken out can cover the route. A duration no airliner could fly is refused; one
+// far longer than expected at the aircraft type's cruise speed is returned as a warning.
+// Routes with airports lacking coordinates are not checked.
+func checkFlightDuration(airports []*models.Airport, duration, stopMinutes int, aircraftType *models.AircraftType) (string, error) {
+	distance, segments := routeDistance(airports)
+	if distance == 0 {
+		return "", nil
+	}
+
+	airTime := duration - stopMinutes
+	fastest := int(math.Ceil(distance / maxGroundSpeedKmh * 60))
+	if airTime < fastest {
+		return "", exceptions.BadRequestError(fmt.Sprintf(
+			"flight duration leaves %d minutes in the air to fly %.0f km, at least %d minutes are needed",
+			airTime, distance, fastest), nil)
+	}
+
+	cruiseSpeedKmh := estimatedCruiseSpeedKmh
+	if aircraftType != nil && aircraftType.CruiseSpeedKmh > 0 {
+		cruiseSpeedKmh = aircraftType.CruiseSpeedKmh
+	}
+	expected := 0
+	for _, segment := range segments {
+		expected += legDuration(segment.DistanceKm, cruiseSpeedKmh)
+	}
+	if airTime > expected*slowDurationFactor {
+		return fmt.Sprintf("flight duration leaves %d minutes in the air to fly %.0f km, about %d minutes are expected",
+			airTime, distance, expected), nil
+	}
+	return "", nil
+}
+
+// rangeWarnings lists the legs of the route the aircraft type cannot fly without refuelling.
+// Legs between airports without coordinates are not checked.
+func rangeWarnings(aircraftType *models.AircraftType, airports []*models.Airport) []string {
+	if aircraftType == nil || aircraftType.RangeKm <= 0 {
+		return nil
+	}
+	var warnings []string
+	for i := 1; i < len(airports); i++ {
+		distance, ok := legDistance(airports[i-1], airports[i])
+		if ok && distance > float64(aircraftType.RangeKm) {
+			warnings = append(warnings, fmt.Sprintf("leg %s-%s is %.0f km, beyond the %d km range of the %s",
+				airports[i-1].AirportCode, airports[i].AirportCode, distance, aircraftType.RangeKm, aircraftType.TypeCode))
+		}
+	}
+	return warnings
+}
+
+// legDuration estimates the minutes needed to fly a leg at cruise speed, rounded up.
+func legDuration(distanceKm float64, cruiseSpeedKmh int) int {
+	minutes := distanceKm/float64(cruiseSpeedKmh)*60 + flightOverheadMinutes
+	return int(math.Ceil(minutes/durationRoundingMinutes)) * durationRoundingMinutes
+}
+
+// SuggestDuration estimates the flight duration of a route from the great-circle distance of
+// its legs and the cruise speed of the aircraft type.
+func (f flightService) SuggestDuration(query *dto.DurationSuggestionQuery) (*dto.DurationSuggestionResponse, error) {
+	var aircraftType *models.AircraftType
+	if query.PlaneCode != "" {
+		plane, err := f.planeRepo.GetByCode(query.PlaneCode)
+		if err != nil {
+			return nil, err
+		}
+		if plane.AircraftType == nil {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("plane '%s' has no aircraft type", plane.PlaneCode), nil)
+		}
+		aircraftType = plane.AircraftType
+	} else {
+		var err error
+		aircraftType, err = f.aircraftTypeRepo.GetByCode(strings.ToUpper(strings.TrimSpace(query.AircraftType)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	codes := []string{query.DepartureAirport}
+	for _, stop := range strings.Split(query.Stops, ",") {
+		if stop = strings.TrimSpace(stop); stop != "" {
+			codes = append(codes, stop)
+		}
+	}
+	codes = append(codes, query.ArrivalAirport)
+
+	airports := make([]*models.Airport, len(codes))
+	for i, code := range codes {
+		airport, err := f.airportRepo.GetByCode(code)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && airport.ID == airports[i-1].ID {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("airport %s is visited twice in a row", airport.AirportCode), nil)
+		}
+		airports[i] = airport
+	}
+
+	response := &dto.DurationSuggestionResponse{
+		CruiseSpeedKmh: aircraftType.CruiseSpeedKmh,
+		Legs:           make([]dto.RouteLegDetail, 0, len(airports)-1),
+		Warnings:       rangeWarnings(aircraftType, airports),
+	}
+	for i := 1; i < len(airports); i++ {
+		from, to := airports[i-1], airports[i]
+		distance, ok := legDistance(from, to)
+		if !ok {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("coordinates are missing for leg %s-%s", from.AirportCode, to.AirportCode), nil)
+		}
+		leg := dto.RouteLegDetail{
+			From:       from.AirportCode,
+			To:         to.AirportCode,
+			DistanceKm: math.Round(distance),
+			Duration:   legDuration(distance, aircraftType.CruiseSpeedKmh),
+		}
+		response.Legs = append(response.Legs, leg)
+		response.DistanceKm += leg.DistanceKm
+		response.SuggestedDuration += leg.Duration
+	}
+	return response, nil
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 	_ "time/tzdata"
@@ -17,11 +19,14 @@ import (
 )
 
 type flightService struct {
-	paramRepo   repository.ParameterRepository
-	flightRepo  repository.FlightRepository
-	airportRepo repository.AirportRepository
-	planeRepo   repository.PlaneRepository
-	ticketRepo  repository.TicketRepository
+	paramRepo        repository.ParameterRepository
+	flightRepo       repository.FlightRepository
+	airportRepo      repository.AirportRepository
+	planeRepo        repository.PlaneRepository
+	ticketRepo       repository.TicketRepository
+	maintenanceRepo  repository.MaintenanceRepository
+	aircraftTypeRepo repository.AircraftTypeRepository
+	routeRepo        repository.RouteRepository
 }
 
 type flightCodeGenerator struct {
@@ -60,17 +65,20 @@ func (g *flightCodeGenerator) Generate() (string, error) {
 	return flightCode, nil
 }
 
-func NewFlightService(flightRepo repository.FlightRepository, airportRepo repository.AirportRepository, planeRepo repository.PlaneRepository, paramRepo repository.ParameterRepository, ticketRepo repository.TicketRepository) FlightService {
+func NewFlightService(flightRepo repository.FlightRepository, airportRepo repository.AirportRepository, planeRepo repository.PlaneRepository, paramRepo repository.ParameterRepository, ticketRepo repository.TicketRepository, maintenanceRepo repository.MaintenanceRepository, aircraftTypeRepo repository.AircraftTypeRepository, routeRepo repository.RouteRepository) FlightService {
 
-	if flightRepo == nil || airportRepo == nil || planeRepo == nil || paramRepo == nil || ticketRepo == nil {
+	if flightRepo == nil || airportRepo == nil || planeRepo == nil || paramRepo == nil || ticketRepo == nil || maintenanceRepo == nil || aircraftTypeRepo == nil || routeRepo == nil {
 		panic("Missing required repositories for flight service")
 	}
 	return &flightService{
-		paramRepo:   paramRepo,
-		flightRepo:  flightRepo,
-		airportRepo: airportRepo,
-		planeRepo:   planeRepo,
-		ticketRepo:  ticketRepo,
+		paramRepo:        paramRepo,
+		flightRepo:       flightRepo,
+		airportRepo:      airportRepo,
+		planeRepo:        planeRepo,
+		ticketRepo:       ticketRepo,
+		maintenanceRepo:  maintenanceRepo,
+		aircraftTypeRepo: aircraftTypeRepo,
+		routeRepo:        routeRepo,
 	}
 }
 
@@ -90,7 +98,7 @@ func (f flightService) getTotalSeatsForPlane(planeID uint) (int64, error) {
 	var planeSeatCounts []PlaneSeatCount
 	result := f.planeRepo.GetDB().Model(&models.Seat{}).
 		Select("plane_id, COUNT(*) as total_seats").
-		Where("plane_id = ?", planeID).
+		Where("plane_id = ? AND is_blocked = ?", planeID, false).
 		Group("plane_id").
 		Find(&planeSeatCounts)
 	if result.Error != nil {
@@ -133,7 +141,7 @@ func (f flightService) getSeatCountsForFlights(flights []*models.Flight) (map[ui
 	var planeSeatCounts []PlaneSeatCount
 	result := f.planeRepo.GetDB().Model(&models.Seat{}).
 		Select("plane_id, COUNT(*) as total_seats").
-		Where("plane_id IN ?", planeIDs).
+		Where("plane_id IN ? AND is_blocked = ?", planeIDs, false).
 		Group("plane_id").
 		Find(&planeSeatCounts)
 	if result.Error != nil {
@@ -222,7 +230,11 @@ func (f flightService) GetAllFlights() ([]*dto.FlightResponse, error) {
 			EmptySeats:        int(emptySeats),
 			BookedSeats:       int(bookedSeats),
 			TotalSeats:        int(totalSeats),
+
+			LatestTicketPurchaseTime: flight.LatestTicketPurchaseTime,
+			TicketCancellationTime:   flight.TicketCancellationTime,
 		}
+		flightResponses[i].DistanceKm, flightResponses[i].Segments = flightDistance(flight)
 	}
 
 	return flightResponses, nil
@@ -264,7 +276,7 @@ func (f flightService) GetFlightByCode(flightCode string) (*dto.FlightResponseDe
 	result := f.planeRepo.GetDB().Model(&models.Seat{}).
 		Select("ticket_classes.ticket_class_name, COUNT(*) as total_seats").
 		Joins("JOIN ticket_classes ON ticket_classes.id = seats.ticket_class_id").
-		Where("seats.plane_id = ?", flight.PlaneID).
+		Where("seats.plane_id = ? AND seats.is_blocked = ?", flight.PlaneID, false).
 		Group("ticket_classes.ticket_class_name").
 		Find(&seatClassCounts)
 	if result.Error != nil {
@@ -287,48 +299,9 @@ func (f flightService) GetFlightByCode(flightCode string) (*dto.FlightResponseDe
 	}
 
 	// Get all seats for the plane with their booking status
-	var seats []models.Seat
-	result = f.planeRepo.GetDB().
-		Preload("TicketClass").
-		Where("plane_id = ?", flight.PlaneID).
-		Find(&seats)
-	if result.Error != nil {
-		return nil, exceptions.InternalError("failed to get seats", result.Error)
-	}
-
-	// Get all active tickets for this flight
-	var tickets []models.Ticket
-	result = f.ticketRepo.GetDB().
-		Where("flight_id = ? AND ticket_status = ?", flight.ID, models.TicketStatusActive).
-		Find(&tickets)
-	if result.Error != nil {
-		return nil, exceptions.InternalError("failed to get tickets", result.Error)
-	}
-
-	// Create a map of seat ID to ticket for quick lookup
-	ticketMap := make(map[uint]*models.Ticket)
-	for i := range tickets {
-		ticketMap[tickets[i].SeatID] = &tickets[i]
-	}
-
-	// Create detailed seat information
-	seatInfo := make([]dto.SeatInfo, len(seats))
-	for i, seat := range seats {
-		ticket := ticketMap[seat.ID]
-		price := flight.BasePrice * seat.TicketClass.PricePercentage
-
-		seatInfo[i] = dto.SeatInfo{
-			SeatNumber: seat.SeatNumber,
-			ClassName:  seat.TicketClass.TicketClassName,
-			IsBooked:   ticket != nil,
-			BookedBy: func() string {
-				if ticket != nil {
-					return ticket.FullName
-				}
-				return ""
-			}(),
-			Price: price,
-		}
+	seatInfo, err := f.getSeatInfo(flight)
+	if err != nil {
+		return nil, err
 	}
 
 	// Map intermediate stops
@@ -342,6 +315,8 @@ func (f flightService) GetFlightByCode(flightCode string) (*dto.FlightResponseDe
 		}
 	}
 
+	distanceKm, segments := flightDistance(flight)
+
 	// Create seat class information
 	seatClassInfo := make([]dto.SeatClassInfo, len(seatClassCounts))
 	for i, count := range seatClassCounts {
@@ -367,18 +342,267 @@ func (f flightService) GetFlightByCode(flightCode string) (*dto.FlightResponseDe
 		TotalSeats:        int(totalSeats),
 		SeatClassInfo:     seatClassInfo,
 		Seats:             seatInfo,
+		SeatMap:           buildSeatMap(flight.Plane.SeatLayout, seatInfo),
+		DistanceKm:        distanceKm,
+		Segments:          segments,
+
+		LatestTicketPurchaseTime: flight.LatestTicketPurchaseTime,
+		TicketCancellationTime:   flight.TicketCancellationTime,
 	}, nil
 }
 
-func (f flightService) Create(flightRequest *dto.FlightRequest) (*dto.FlightResponse, error) {
-	// 1. Get parameters for validation
+// GetEffectiveParameters resolves the parameters in force on a flight now, with the source of
+// each value.
+func (f flightService) GetEffectiveParameters(flightCode string) (*dto.EffectiveParametersResponse, error) {
+	flight, err := f.flightRepo.GetByCode(flightCode)
+	if err != nil {
+		return nil, err
+	}
 	params, err := f.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+
+	effective, sources := resolveParameters(params, flight.Route, flight)
+	response := &dto.EffectiveParametersResponse{
+		FlightCode: flight.FlightCode,
+		Version:    params.Version,
+		Parameters: make([]dto.EffectiveParameter, len(parameterFields)),
+	}
+	if flight.Route != nil {
+		response.Route = flight.DepartureAirport.AirportCode + "-" + flight.ArrivalAirport.AirportCode
+	}
+	for i, field := range parameterFields {
+		response.Parameters[i] = dto.EffectiveParameter{
+			Name:   field.name,
+			Value:  *field.value(effective),
+			Source: sources[field.name],
+		}
+	}
+	return response, nil
+}
+
+// GetFlightSeats lists the seats of a flight matching the filter, e.g. available window seats.
+func (f flightService) GetFlightSeats(flightCode string, filter *dto.SeatFilter) ([]dto.SeatInfo, error) {
+	flight, err := f.flightRepo.GetByCode(flightCode)
+	if err != nil {
+		return nil, err
+	}
+	seats, err := f.getSeatInfo(flight)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]dto.SeatInfo, 0, len(seats))
+	for _, seat := range seats {
+		if seatMatchesFilter(&seat, filter) {
+			matches = append(matches, seat)
+		}
+	}
+	return matches, nil
+}
+
+// getSeatInfo returns every seat of the flight's plane with its fare and booking status.
+func (f flightService) getSeatInfo(flight *models.Flight) ([]dto.SeatInfo, error) {
+	seats, err := f.planeRepo.GetSeatsByPlaneID(flight.PlaneID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all active tickets for this flight
+	var tickets []models.Ticket
+	result := f.ticketRepo.GetDB().
+		Where("flight_id = ? AND ticket_status = ?", flight.ID, models.TicketStatusActive).
+		Find(&tickets)
+	if result.Error != nil {
+		return nil, exceptions.InternalError("failed to get tickets", result.Error)
+	}
+
+	// Create a map of seat ID to ticket for quick lookup
+	ticketMap := make(map[uint]*models.Ticket)
+	for i := range tickets {
+		ticketMap[tickets[i].SeatID] = &tickets[i]
+	}
+
+	seatInfo := make([]dto.SeatInfo, len(seats))
+	for i, seat := range seats {
+		ticket := ticketMap[seat.ID]
+		price := flight.BasePrice*seat.TicketClass.PricePercentage + seat.Surcharge
+
+		seatInfo[i] = dto.SeatInfo{
+			SeatNumber: seat.SeatNumber,
+			ClassName:  seat.TicketClass.TicketClassName,
+			IsBooked:   ticket != nil,
+			BookedBy: func() string {
+				if ticket != nil {
+					return ticket.FullName
+				}
+				return ""
+			}(),
+			Price:          price,
+			SeatAttributes: toSeatAttributes(seat),
+		}
+	}
+	return seatInfo, nil
+}
+
+// buildSeatMap arranges the seats into the grid described by the plane's layout.
+func buildSeatMap(layout *models.SeatLayout, seats []dto.SeatInfo) *dto.SeatMap {
+	if layout == nil {
+		return nil
+	}
+
+	seatsByNumber := make(map[string]*dto.SeatInfo, len(seats))
+	for i := range seats {
+		seatsByNumber[seats[i].SeatNumber] = &seats[i]
+	}
+
+	seatMap := &dto.SeatMap{
+		Columns:     layout.Columns,
+		AislesAfter: layout.AislesAfter,
+		Cabins:      make([]dto.CabinSeatMap, len(layout.Cabins)),
+	}
+	for i, cabin := range layout.Cabins {
+		cabinMap := dto.CabinSeatMap{
+			ClassName: cabin.TicketClass,
+			FromRow:   cabin.FromRow,
+			ToRow:     cabin.ToRow,
+			Rows:      make([]dto.SeatMapRow, 0, cabin.ToRow-cabin.FromRow+1),
+		}
+		for row := cabin.FromRow; row <= cabin.ToRow; row++ {
+			seatRow := dto.SeatMapRow{
+				Row:       row,
+				IsExitRow: slices.Contains(layout.ExitRows, row),
+				Seats:     make([]*dto.SeatInfo, len(layout.Columns)),
+			}
+			for j, column := range layout.Columns {
+				seatRow.Seats[j] = seatsByNumber[fmt.Sprintf("%d%s", row, column)]
+			}
+			cabinMap.Rows = append(cabinMap.Rows, seatRow)
+		}
+		seatMap.Cabins[i] = cabinMap
+	}
+	return seatMap
+}
+
+func seatMatchesFilter(seat *dto.SeatInfo, filter *dto.SeatFilter) bool {
+	if filter.ClassName != "" && !strings.EqualFold(seat.ClassName, filter.ClassName) {
+		return false
+	}
+	if filter.Position != "" && seat.Position != filter.Position {
+		return false
+	}
+	if filter.ExitRow != nil && seat.IsExitRow != *filter.ExitRow {
+		return false
+	}
+	if filter.ExtraLegroom != nil && seat.ExtraLegroom != *filter.ExtraLegroom {
+		return false
+	}
+	if filter.Bassinet != nil && seat.HasBassinet != *filter.Bassinet {
+		return false
+	}
+	if filter.AvailableOnly && (seat.IsBooked || seat.IsBlocked) {
+		return false
+	}
+	if filter.MaxSurcharge != nil && seat.Surcharge > *filter.MaxSurcharge {
+		return false
+	}
+	return true
+}
+
+// checkMaintenanceWindows rejects a schedule that keeps the plane busy during one of its
+// maintenance windows.
+func (f flightService) checkMaintenanceWindows(plane *models.Plane, departure time.Time, duration int) error {
+	arrival := departure.Add(time.Duration(duration) * time.Minute)
+	windows, err := f.maintenanceRepo.GetOverlapping(plane.ID, departure, arrival)
+	if err != nil {
+		return err
+	}
+	if len(windows) == 0 {
+		return nil
+	}
+
+	loc, _ := time.LoadLocation(config.GetConfig().Database.Timezone)
+	window := windows[0]
+	return exceptions.NewAppError(exceptions.CONFLICT,
+		fmt.Sprintf("plane '%s' is in maintenance from %s to %s: %s", plane.PlaneCode,
+			window.StartTime.In(loc).Format(time.DateTime), window.EndTime.In(loc).Format(time.DateTime), window.Reason), nil)
+}
+
+// requestRoute returns the airports a requested flight visits, in order, and the minutes it
+// spends on the ground at its stops.
+func (f flightService) requestRoute(departure, arrival *models.Airport, stops []dto.IntermediateStopDTO) ([]*models.Airport, int, error) {
+	stops = slices.Clone(stops)
+	slices.SortStableFunc(stops, func(a, b dto.IntermediateStopDTO) int {
+		return a.StopOrder - b.StopOrder
+	})
+
+	airports := make([]*models.Airport, 0, len(stops)+2)
+	airports = append(airports, departure)
+	stopMinutes := 0
+	for _, stop := range stops {
+		airport, err := f.airportRepo.GetByCode(stop.StopAirport)
+		if err != nil {
+			return nil, 0, err
+		}
+		airports = append(airports, airport)
+		stopMinutes += stop.StopDuration
+	}
+	return append(airports, arrival), stopMinutes, nil
+}
+
+// checkRoute checks that the flight's duration is plausible for the distance flown and lists
+// the warnings to return with the flight.
+func (f flightService) checkRoute(plane *models.Plane, departure, arrival *models.Airport, flightRequest *dto.FlightRequest) ([]string, error) {
+	route, stopMinutes, err := f.requestRoute(departure, arrival, flightRequest.IntermediateStop)
+	if err != nil {
+		return nil, err
+	}
+	durationWarning, err := checkFlightDuration(route, flightRequest.Duration, stopMinutes, plane.AircraftType)
+	if err != nil {
+		return nil, err
+	}
+	warnings := rangeWarnings(plane.AircraftType, route)
+	if durationWarning != "" {
+		warnings = append(warnings, durationWarning)
+	}
+	return warnings, nil
+}
+
+// flightRoute finds the approved route a requested flight follows, fills in the duration and
+// base price the request leaves out, and returns the parameters in force on the route.
+func (f flightService) flightRoute(flightRequest *dto.FlightRequest, params *models.Parameter) (*models.Route, *models.Parameter, error) {
+	route, err := f.routeRepo.GetByAirportCodes(flightRequest.DepartureAirport, flightRequest.ArrivalAirport)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil, exceptions.BadRequestError(fmt.Sprintf("there is no route from %s to %s",
+				flightRequest.DepartureAirport, flightRequest.ArrivalAirport), nil)
+		}
+		return nil, nil, err
+	}
+	if flightRequest.Duration == 0 {
+		flightRequest.Duration = route.DefaultDuration
+	}
+	if flightRequest.BasePrice == 0 {
+		flightRequest.BasePrice = route.DefaultBasePrice
+	}
+	return route, routeParameters(params, route), nil
+}
+
+func (f flightService) Create(flightRequest *dto.FlightRequest) (*dto.FlightResponse, error) {
+	// 1. Parse the departure date time and get the parameters in force on it for validation
+	loc, _ := time.LoadLocation(config.GetConfig().Database.Timezone)
+	departureDateTime, err := time.ParseInLocation(time.DateTime, flightRequest.DepartureDateTime, loc)
+	if err != nil {
+		return nil, exceptions.BadRequestError("invalid departure date time format", err)
+	}
+	params, err := f.paramRepo.GetParamsAt(departureDateTime)
 	if err != nil {
 		var appErr *exceptions.AppError
 		if errors.As(err, &appErr) {
 			return nil, appErr
 		}
-		return nil, exceptions.InternalError("failed to get all params", err)
+		return nil, exceptions.InternalError("failed to get params", err)
 	}
 
 	// 2. Validate basic flight requirements
@@ -386,6 +610,12 @@ func (f flightService) Create(flightRequest *dto.FlightRequest) (*dto.FlightResp
 		return nil, exceptions.BadRequestError("departure and arrival airports cannot be the same", nil)
 	}
 
+	// The flight must follow an approved route, which may override the parameters
+	route, params, err := f.flightRoute(flightRequest, params)
+	if err != nil {
+		return nil, err
+	}
+
 	// 3. Validate flight duration
 	if flightRequest.Duration < params.MinFlightDuration {
 		return nil, exceptions.BadRequestError(fmt.Sprintf("flight duration must be at least %d minutes", params.MinFlightDuration), nil)
@@ -462,6 +692,12 @@ func (f flightService) Create(flightRequest *dto.FlightRequest) (*dto.FlightResp
 		}
 		return nil, exceptions.InternalError("failed to get plane by code", err)
 	}
+	if plane.RetiredAt != nil {
+		return nil, exceptions.BadRequestError(fmt.Sprintf("plane '%s' is retired", plane.PlaneCode), nil)
+	}
+	if err := checkAllowedAircraft(route, plane); err != nil {
+		return nil, err
+	}
 
 	// 7. Validate and get airports
 	departureAirport, err := f.airportRepo.GetByCode(flightRequest.DepartureAirport)
@@ -482,18 +718,22 @@ func (f flightService) Create(flightRequest *dto.FlightRequest) (*dto.FlightResp
 		return nil, exceptions.InternalError("failed to get arrival airport by code", err)
 	}
 
-	// 8. Parse and validate departure date time
-	loc, _ := time.LoadLocation(config.GetConfig().Database.Timezone)
-	departureDateTime, err := time.ParseInLocation(time.DateTime, flightRequest.DepartureDateTime, loc)
-	if err != nil {
-		return nil, exceptions.BadRequestError("invalid departure date time format", err)
-	}
-
-	// Validate departure is not in the past
+	// 8. Validate departure is not in the past
 	if departureDateTime.Before(time.Now()) {
 		return nil, exceptions.BadRequestError("departure date time cannot be in the past", nil)
 	}
 
+	// The plane must not be in maintenance while the flight is under way
+	if err := f.checkMaintenanceWindows(plane, departureDateTime, flightRequest.Duration); err != nil {
+		return nil, err
+	}
+
+	// The duration must be plausible for the distance flown
+	warnings, err := f.checkRoute(plane, departureAirport, arrivalAirport, flightRequest)
+	if err != nil {
+		return nil, err
+	}
+
 	// 9. Create the flight and intermediate stops in a transaction
 	var createdFlight *models.Flight
 	err = f.flightRepo.GetDB().Transaction(func(tx *gorm.DB) error {
@@ -501,11 +741,15 @@ func (f flightService) Create(flightRequest *dto.FlightRequest) (*dto.FlightResp
 		newFlight := &models.Flight{
 			FlightCode:         flightCode,
 			PlaneID:            plane.ID,
+			RouteID:            &route.ID,
 			DepartureAirportID: departureAirport.ID,
 			ArrivalAirportID:   arrivalAirport.ID,
 			DepartureDateTime:  departureDateTime,
 			FlightDuration:     flightRequest.Duration,
 			BasePrice:          flightRequest.BasePrice,
+
+			LatestTicketPurchaseTime: flightRequest.LatestTicketPurchaseTime,
+			TicketCancellationTime:   flightRequest.TicketCancellationTime,
 		}
 
 		if err := tx.Create(newFlight).Error; err != nil {
@@ -566,6 +810,8 @@ func (f flightService) Create(flightRequest *dto.FlightRequest) (*dto.FlightResp
 	}
 	emptySeats := totalSeats - bookedSeats
 
+	distanceKm, segments := flightDistance(createdFlight)
+
 	// Map intermediate stops
 	intermediateStopDTOs := make([]dto.IntermediateStopDTO, len(createdFlight.IntermediateStops))
 	for i, stop := range createdFlight.IntermediateStops {
@@ -589,6 +835,12 @@ func (f flightService) Create(flightRequest *dto.FlightRequest) (*dto.FlightResp
 		EmptySeats:        int(emptySeats),
 		BookedSeats:       int(bookedSeats),
 		TotalSeats:        int(totalSeats),
+		DistanceKm:        distanceKm,
+		Segments:          segments,
+		Warnings:          warnings,
+
+		LatestTicketPurchaseTime: createdFlight.LatestTicketPurchaseTime,
+		TicketCancellationTime:   createdFlight.TicketCancellationTime,
 	}, nil
 }
 
@@ -603,14 +855,19 @@ func (f flightService) Update(flightCode string, flightRequest *dto.FlightReques
 		return nil, exceptions.InternalError("failed to get flight by code", err)
 	}
 
-	// Get parameters for validation
-	params, err := f.paramRepo.GetAllParams()
+	// Parse the departure date time and get the parameters in force on it for validation
+	loc, _ := time.LoadLocation(config.GetConfig().Database.Timezone)
+	departureDateTime, err := time.ParseInLocation(time.DateTime, flightRequest.DepartureDateTime, loc)
+	if err != nil {
+		return nil, exceptions.BadRequestError("invalid departure date time format", err)
+	}
+	params, err := f.paramRepo.GetParamsAt(departureDateTime)
 	if err != nil {
 		var appErr *exceptions.AppError
 		if errors.As(err, &appErr) {
 			return nil, appErr
 		}
-		return nil, exceptions.InternalError("failed to get all params", err)
+		return nil, exceptions.InternalError("failed to get params", err)
 	}
 
 	// Validate basic flight requirements
@@ -618,6 +875,12 @@ func (f flightService) Update(flightCode string, flightRequest *dto.FlightReques
 		return nil, exceptions.BadRequestError("departure and arrival airports cannot be the same", nil)
 	}
 
+	// The flight must follow an approved route, which may override the parameters
+	route, params, err := f.flightRoute(flightRequest, params)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate flight duration
 	if flightRequest.Duration < params.MinFlightDuration {
 		return nil, exceptions.BadRequestError(fmt.Sprintf("flight duration must be at least %d minutes", params.MinFlightDuration), nil)
@@ -667,6 +930,12 @@ func (f flightService) Update(flightCode string, flightRequest *dto.FlightReques
 		}
 		return nil, exceptions.InternalError("failed to get plane by code", err)
 	}
+	if plane.RetiredAt != nil {
+		return nil, exceptions.BadRequestError(fmt.Sprintf("plane '%s' is retired", plane.PlaneCode), nil)
+	}
+	if err := checkAllowedAircraft(route, plane); err != nil {
+		return nil, err
+	}
 
 	// Validate and get airports
 	departureAirport, err := f.airportRepo.GetByCode(flightRequest.DepartureAirport)
@@ -687,20 +956,28 @@ func (f flightService) Update(flightCode string, flightRequest *dto.FlightReques
 		return nil, exceptions.InternalError("failed to get arrival airport by code", err)
 	}
 
-	// Parse and validate departure date time
-	loc, _ := time.LoadLocation(config.GetConfig().Database.Timezone)
-	departureDateTime, err := time.ParseInLocation(time.DateTime, flightRequest.DepartureDateTime, loc)
+	// The plane must not be in maintenance while the flight is under way
+	if err := f.checkMaintenanceWindows(plane, departureDateTime, flightRequest.Duration); err != nil {
+		return nil, err
+	}
+
+	// The duration must be plausible for the distance flown
+	warnings, err := f.checkRoute(plane, departureAirport, arrivalAirport, flightRequest)
 	if err != nil {
-		return nil, exceptions.BadRequestError("invalid departure date time format", err)
+		return nil, err
 	}
 
 	// Update flight fields
 	existingFlight.PlaneID = plane.ID
+	existingFlight.RouteID = &route.ID
+	existingFlight.Route = route
 	existingFlight.DepartureAirportID = departureAirport.ID
 	existingFlight.ArrivalAirportID = arrivalAirport.ID
 	existingFlight.DepartureDateTime = departureDateTime
 	existingFlight.FlightDuration = flightRequest.Duration
 	existingFlight.BasePrice = flightRequest.BasePrice
+	existingFlight.LatestTicketPurchaseTime = flightRequest.LatestTicketPurchaseTime
+	existingFlight.TicketCancellationTime = flightRequest.TicketCancellationTime
 
 	// Update the flight
 	updatedFlight, err := f.flightRepo.Update(existingFlight)
@@ -764,6 +1041,8 @@ func (f flightService) Update(flightCode string, flightRequest *dto.FlightReques
 	}
 	emptySeats := totalSeats - bookedSeats
 
+	distanceKm, segments := flightDistance(updatedFlight)
+
 	// Map intermediate stops
 	intermediateStopDTOs := make([]dto.IntermediateStopDTO, len(updatedFlight.IntermediateStops))
 	for i, stop := range updatedFlight.IntermediateStops {
@@ -787,6 +1066,12 @@ func (f flightService) Update(flightCode string, flightRequest *dto.FlightReques
 		EmptySeats:        int(emptySeats),
 		BookedSeats:       int(bookedSeats),
 		TotalSeats:        int(totalSeats),
+		DistanceKm:        distanceKm,
+		Segments:          segments,
+		Warnings:          warnings,
+
+		LatestTicketPurchaseTime: updatedFlight.LatestTicketPurchaseTime,
+		TicketCancellationTime:   updatedFlight.TicketCancellationTime,
 	}, nil
 }
 
@@ -880,6 +1165,7 @@ func (f flightService) GetAllFlightsInList() ([]*dto.FlightListResponse, error)
 			HasStops:          len(flight.IntermediateStops) > 0,
 			StopCount:         len(flight.IntermediateStops),
 		}
+		flightResponses[i].DistanceKm, _ = flightDistance(flight)
 	}
 
 	return flightResponses, nil
@@ -907,6 +1193,9 @@ func (f flightService) GetMonthlyRevenueReport(year int, month int) (*dto.Monthl
 		Flights: make([]dto.FlightRevenueReport, 0),
 	}
 
+	// Revenue per seat-kilometer only covers flights whose distance is known
+	var seatKmRevenue float64
+
 	// Calculate revenue for each flight
 	for _, flight := range flights {
 		// Get all tickets for this flight
@@ -943,11 +1232,21 @@ func (f flightService) GetMonthlyRevenueReport(year int, month int) (*dto.Monthl
 			Revenue:    actualRevenue,
 			Ratio:      ratio * 100,
 		}
+		flightReport.DistanceKm, _ = flightDistance(flight)
+		flightReport.SeatKm = flightReport.DistanceKm * float64(totalSeats)
+		if flightReport.SeatKm > 0 {
+			flightReport.RevenuePerSeatKm = actualRevenue / flightReport.SeatKm
+			seatKmRevenue += actualRevenue
+		}
 		report.Flights = append(report.Flights, flightReport)
 
 		// Update totals
 		report.TotalRevenue += actualRevenue
 		report.TotalTickets += len(tickets)
+		report.TotalSeatKm += flightReport.SeatKm
+	}
+	if report.TotalSeatKm > 0 {
+		report.RevenuePerSeatKm = seatKmRevenue / report.TotalSeatKm
 	}
 
 	// Calculate average ratio
@@ -969,6 +1268,9 @@ func (f flightService) GetYearlyRevenueReport(year int) (*dto.YearlyRevenueRepor
 		Months: make([]dto.MonthlyRevenueSummary, 0, 12),
 	}
 
+	// Revenue per seat-kilometer only covers flights whose distance is known
+	var seatKmRevenue float64
+
 	// Process each month
 	for month := 1; month <= 12; month++ {
 		// Get start and end dates for the month
@@ -993,7 +1295,7 @@ func (f flightService) GetYearlyRevenueReport(year int) (*dto.YearlyRevenueRepor
 		}
 
 		// Calculate revenue and ratio for each flight
-		var totalRatio float64
+		var totalRatio, monthSeatKmRevenue float64
 		for _, flight := range flights {
 			// Get all tickets for this flight
 			tickets, err := f.ticketRepo.GetTicketsByFlightID(flight.ID)
@@ -1026,6 +1328,13 @@ func (f flightService) GetYearlyRevenueReport(year int) (*dto.YearlyRevenueRepor
 			// Update month summary
 			monthSummary.Revenue += actualRevenue
 			totalRatio += ratio
+			if distanceKm, _ := flightDistance(flight); distanceKm > 0 {
+				monthSummary.SeatKm += distanceKm * float64(totalSeats)
+				monthSeatKmRevenue += actualRevenue
+			}
+		}
+		if monthSummary.SeatKm > 0 {
+			monthSummary.RevenuePerSeatKm = monthSeatKmRevenue / monthSummary.SeatKm
 		}
 
 		// Calculate average ratio for the month
@@ -1039,6 +1348,11 @@ func (f flightService) GetYearlyRevenueReport(year int) (*dto.YearlyRevenueRepor
 		// Update yearly totals
 		report.TotalRevenue += monthSummary.Revenue
 		report.TotalFlights += monthSummary.FlightCount
+		report.TotalSeatKm += monthSummary.SeatKm
+		seatKmRevenue += monthSeatKmRevenue
+	}
+	if report.TotalSeatKm > 0 {
+		report.RevenuePerSeatKm = seatKmRevenue / report.TotalSeatKm
 	}
 
 	// Calculate yearly average ratio
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/aprilboiz/flight-management/internal/dto"
 	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/pkg/auth"
 )
 
 type FlightService interface {
@@ -10,26 +11,65 @@ type FlightService interface {
 	GetAllFlights() ([]*dto.FlightResponse, error)
 	GetAllFlightsInList() ([]*dto.FlightListResponse, error)
 	GetFlightByCode(flightCode string) (*dto.FlightResponseDetailed, error)
+	GetFlightSeats(flightCode string, filter *dto.SeatFilter) ([]dto.SeatInfo, error)
+	GetEffectiveParameters(flightCode string) (*dto.EffectiveParametersResponse, error)
 	Update(code string, flight *dto.FlightRequest) (*dto.FlightResponse, error)
 	Delete(code string) error
 	GetMonthlyRevenueReport(year int, month int) (*dto.MonthlyRevenueReport, error)
 	GetYearlyRevenueReport(year int) (*dto.YearlyRevenueReport, error)
+	SuggestDuration(query *dto.DurationSuggestionQuery) (*dto.DurationSuggestionResponse, error)
 }
 
 type AirportService interface {
 	GetAllAirports() ([]*dto.AirportResponse, error)
 	GetAirportByCode(code string) (*dto.AirportResponse, error)
 	GetAirportsByCodes(codes []string) (map[string]*dto.AirportResponse, error)
+	SearchAirports(query *dto.AirportSearchQuery) ([]*dto.AirportResponse, error)
+	CreateAirport(request *dto.AirportRequest) (*dto.AirportResponse, error)
+	UpdateAirport(code string, request *dto.AirportRequest) (*dto.AirportResponse, error)
+	DeleteAirport(code string) error
+	ImportAirports(request *dto.AirportImportRequest) (*dto.AirportImportResponse, error)
 }
 
 type PlaneService interface {
 	GetAllPlanes() ([]*dto.PlaneResponse, error)
 	GetPlaneByCode(code string) (*dto.PlaneResponseDetails, error)
+	CreatePlane(request *dto.PlaneRequest) (*dto.PlaneResponseDetails, error)
+	UpdatePlane(code string, request *dto.PlaneRequest) (*dto.PlaneResponseDetails, error)
+	BuildSeatMap(code string, layout *dto.SeatLayoutDTO) (*dto.PlaneResponseDetails, error)
+	RetirePlane(code string) (*dto.PlaneResponseDetails, error)
+	GetAllAircraftTypes() ([]*dto.AircraftTypeResponse, error)
+	GetAircraftType(code string) (*dto.AircraftTypeResponse, error)
+	CreateAircraftType(request *dto.AircraftTypeRequest) (*dto.AircraftTypeResponse, error)
+	UpdateAircraftType(code string, request *dto.AircraftTypeRequest) (*dto.AircraftTypeResponse, error)
+	DeleteAircraftType(code string) error
+}
+
+type TicketClassService interface {
+	GetAllTicketClasses() ([]*dto.TicketClassResponse, error)
+	GetTicketClass(id uint) (*dto.TicketClassResponse, error)
+	CreateTicketClass(request *dto.TicketClassRequest) (*dto.TicketClassResponse, error)
+	UpdateTicketClass(id uint, request *dto.TicketClassRequest) (*dto.TicketClassResponse, error)
+	DeleteTicketClass(id uint) error
+}
+
+type RouteService interface {
+	GetAllRoutes() ([]*dto.RouteResponse, error)
+	GetRoute(id uint) (*dto.RouteResponse, error)
+	CreateRoute(request *dto.RouteRequest) (*dto.RouteResponse, error)
+	UpdateRoute(id uint, request *dto.RouteRequest) (*dto.RouteResponse, error)
+	DeleteRoute(id uint) error
+	GetNetwork(query *dto.RouteNetworkQuery) (*dto.RouteNetworkResponse, error)
 }
 
 type ParameterService interface {
-	GetAllParams() (*models.Parameter, error)
-	UpdateParams(params *models.Parameter) (*models.Parameter, error)
+	GetAllParams() (*dto.ParameterVersionResponse, error)
+	GetVersions() ([]*dto.ParameterVersionResponse, error)
+	GetVersion(version int) (*dto.ParameterVersionResponse, error)
+	UpdateParams(request *dto.ParameterRequest, author string, force bool) (*dto.ParameterVersionResponse, error)
+	AnalyzeParams(request *dto.ParameterRequest) (*dto.ParameterImpactResponse, error)
+	RollbackParams(version int, author string, force bool) (*dto.ParameterVersionResponse, error)
+	CancelScheduledParams(version int) error
 }
 
 type FlightCodeGenerator interface {
@@ -38,8 +78,12 @@ type FlightCodeGenerator interface {
 
 type TicketService interface {
 	Create(ticket *dto.TicketRequest) (*dto.TicketResponse, error)
+	CreateGroup(group *dto.GroupTicketRequest) (*dto.GroupTicketResponse, error)
 	GetAllTickets() ([]*dto.TicketResponse, error)
 	GetTicketByID(id uint) (*dto.TicketResponse, error)
+	GetTicketsByIDCard(idCard string) ([]*dto.TicketResponse, error)
+	GetTicketsByBookingReference(reference string) ([]*dto.TicketResponse, error)
+	RotatePIIEncryption() (*dto.PIIRotationResponse, error)
 	UpdateTicketStatus(ticketId uint, newStatus models.TicketStatus) (*dto.TicketResponse, error)
 	DeleteTicket(id uint) error
 	CancelPlaceOrders(flightCode string) error
@@ -47,7 +91,77 @@ type TicketService interface {
 	GetBookingTypes() []models.BookingType
 }
 
+type PassengerService interface {
+	Create(passenger *dto.PassengerRequest) (*dto.PassengerResponse, error)
+	Update(id uint, passenger *dto.PassengerRequest) (*dto.PassengerResponse, error)
+	AddDocument(id uint, document *dto.TravelDocumentDTO) (*dto.PassengerResponse, error)
+	GetByID(id uint) (*dto.PassengerResponse, error)
+	Search(query string) ([]*dto.PassengerResponse, error)
+	GetBookingHistory(id uint) (*dto.PassengerHistoryResponse, error)
+}
+
+type LoyaltyService interface {
+	Enroll(passengerID uint) (*dto.LoyaltyAccountResponse, error)
+	GetAccount(memberNumber string) (*dto.LoyaltyAccountResponse, error)
+	GetAccountByPassengerID(passengerID uint) (*dto.LoyaltyAccountResponse, error)
+	GetTransactions(memberNumber string) ([]*dto.LoyaltyTransactionResponse, error)
+	QuoteRedemption(passengerID uint, points int, fare float64) (float64, error)
+	RedeemForTicket(passengerID uint, ticketID uint, points int) error
+	AccrueForTicket(ticket *models.Ticket) error
+	ReverseForTicket(ticket *models.Ticket, reverseEarned bool) error
+	RefreshTiers() (int, error)
+}
+
+type MaintenanceService interface {
+	GetWindows(planeCode string) ([]*dto.MaintenanceWindowResponse, error)
+	CreateWindow(planeCode string, request *dto.MaintenanceWindowRequest, createdBy string) (*dto.MaintenanceWindowResponse, error)
+	DeleteWindow(planeCode string, id uint) error
+	GetAffectedFlights(planeCode string, id uint) ([]dto.AffectedFlightResponse, error)
+	GetFleetAvailability(query *dto.FleetAvailabilityQuery) (*dto.FleetAvailabilityResponse, error)
+}
+
+type PrivacyService interface {
+	ExportPassengerData(email, idCard string) (*dto.PassengerDataExport, error)
+	RequestErasure(request *dto.ErasureRequestRequest, requestedBy string) (*dto.ErasureRequestResponse, error)
+	GetErasureRequests() ([]*dto.ErasureRequestResponse, error)
+	ApplyRetentionPolicy() (int64, int64, error)
+}
+
+type RoleService interface {
+	GetAllPermissions() []models.Permission
+	GetAllRoles() ([]*dto.RoleResponse, error)
+	GetRole(name models.Role) (*dto.RoleResponse, error)
+	CreateRole(request *dto.CreateRoleRequest, callerRole models.Role) (*dto.RoleResponse, error)
+	UpdateRole(name models.Role, request *dto.RoleRequest, callerRole models.Role) (*dto.RoleResponse, error)
+	DeleteRole(name models.Role) error
+	RolePermissions(role models.Role) ([]models.Permission, error)
+}
+
+type AuditService interface {
+	SearchAuditLog(query *dto.AuditLogQuery) ([]*dto.AuditLogResponse, error)
+}
+
 type UserService interface {
 	Register(req dto.RegisterRequest) (*dto.AuthResponse, error)
-	Login(req dto.LoginRequest) (*dto.AuthResponse, error)
+	Login(req dto.LoginRequest, ipAddress string) (*dto.AuthResponse, error)
+	Refresh(req dto.RefreshRequest) (*dto.AuthResponse, error)
+	Logout(sessionID uint) error
+	LogoutAll(userID uint) error
+	ChangePassword(userID, sessionID uint, req *dto.ChangePasswordRequest, ipAddress string) error
+	RequestPasswordReset(req *dto.PasswordResetRequest)
+	ResetPassword(req *dto.PasswordResetConfirmRequest, ipAddress string) error
+	ValidateAccessToken(claims *auth.Claims) error
+	ListUsers(query *dto.UserSearchQuery) ([]*dto.UserResponse, error)
+	GetUser(id uint) (*dto.UserResponse, error)
+	GetMe(id uint) (*dto.MeResponse, error)
+	CreateUser(req *dto.CreateUserRequest, callerRole models.Role) (*dto.UserResponse, error)
+	ChangeRole(id uint, role, callerRole models.Role) (*dto.UserResponse, error)
+	DisableUser(id uint, callerRole models.Role) (*dto.UserResponse, error)
+	EnableUser(id uint, callerRole models.Role) (*dto.UserResponse, error)
+	UnlockUser(id uint, callerRole models.Role, actor, ipAddress string) (*dto.UserResponse, error)
+	DeleteUser(id uint, callerRole models.Role) error
+	BootstrapSuperAdmin() error
+	CreateInvitation(req *dto.InvitationRequest, issuer string, issuerRole models.Role) (*dto.InvitationResponse, error)
+	ListInvitations() ([]*dto.InvitationResponse, error)
+	RevokeInvitation(id uint) error
 }
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/pkg/config"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrTooManyAttempts = errors.New("too many failed login attempts")
+
+// LoginThrottledError refuses a login attempt until RetryAfter has passed. It is the same for
+// usernames that exist and usernames that do not.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrTooManyAttempts, e.RetryAfter)
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// dummyPasswordHash is compared against when the username does not exist, so a failed login
+// takes as long whether or not it does.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("no such user"), bcrypt.DefaultCost)
+
+func usernameThrottleKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+func ipThrottleKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
+
+// checkLoginThrottle refuses an attempt while the username or the IP address is locked out, or
+// while the username has to wait after its last failure.
+func (s *userService) checkLoginThrottle(userKey, ipKey string) error {
+	throttles, err := s.loginThrottleRepo.GetByKeys(userKey, ipKey)
+	if err != nil {
+		return err
+	}
+	cfg := config.GetConfig().Security.Login
+	now := time.Now()
+	var wait time.Duration
+	for _, throttle := range throttles {
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			wait = max(wait, throttle.LockedUntil.Sub(now))
+		}
+		if throttle.Key == userKey && throttle.Failures > 0 {
+			next := throttle.LastFailureAt.Add(loginBackoff(cfg, throttle.Failures))
+			if next.After(now) {
+				wait = max(wait, next.Sub(now))
+			}
+		}
+	}
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait.Round(time.Second) + time.Second}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt against the username and the IP address, locking
+// out either one that reaches its threshold.
+func (s *userService) recordLoginFailure(userKey, ipKey, username, ipAddress string) error {
+	cfg := config.GetConfig().Security.Login
+	now := time.Now()
+	windowStart := now.Add(-time.Duration(cfg.FailureWindowMinutes) * time.Minute)
+
+	for _, target := range []struct {
+		key, subject string
+		maxFailures  int
+	}{
+		{userKey, username, cfg.MaxFailures},
+		{ipKey, ipAddress, cfg.MaxFailuresPerIP},
+	} {
+		throttle, err := s.loginThrottleRepo.RecordFailure(target.key, now, windowStart)
+		if err != nil {
+			return err
+		}
+		if target.maxFailures <= 0 || throttle.Failures < target.maxFailures {
+			continue
+		}
+		until := now.Add(time.Duration(cfg.LockoutMinutes) * time.Minute)
+		if err := s.loginThrottleRepo.Lock(target.key, until); err != nil {
+			return err
+		}
+		zap.L().Warn("Login locked out", zap.String("key", target.key), zap.Time("until", until))
+		if err := s.auditRepo.Create(&models.AuditLog{
+			Action:    models.AuditLoginLockout,
+			Actor:     auditActorSystem,
+			Subject:   target.key,
+			IPAddress: ipAddress,
+			Details: fmt.Sprintf("%d failed logins, locked until %s",
+				throttle.Failures, until.Format(time.RFC3339)),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loginBackoff is the wait after the given number of consecutive failures, doubling from the
+// base delay up to the maximum. Without a maximum above the base delay, the wait does not grow.
+func loginBackoff(cfg config.LoginConfig, failures int) time.Duration {
+	if cfg.BaseDelaySeconds <= 0 || failures <= 0 {
+		return 0
+	}
+	delay := time.Duration(cfg.BaseDelaySeconds) * time.Second
+	limit := max(time.Duration(cfg.MaxDelaySeconds)*time.Second, delay)
+	for i := 1; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aprilboiz/flight-management/pkg/config"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	capped := config.LoginConfig{BaseDelaySeconds: 1, MaxDelaySeconds: 30}
+
+	tests := []struct {
+		name     string
+		cfg      config.LoginConfig
+		failures int
+		want     time.Duration
+	}{
+		{"no failures", capped, 0, 0},
+		{"negative failures", capped, -1, 0},
+		{"first failure", capped, 1, time.Second},
+		{"doubles", capped, 2, 2 * time.Second},
+		{"doubles again", capped, 5, 16 * time.Second},
+		{"reaches the cap", capped, 6, 30 * time.Second},
+		{"stays at the cap", capped, 7, 30 * time.Second},
+		{"many failures do not overflow", capped, 1000, 30 * time.Second},
+		{"cap equal to a doubling", config.LoginConfig{BaseDelaySeconds: 2, MaxDelaySeconds: 8}, 3, 8 * time.Second},
+		{"cap below the base delay", config.LoginConfig{BaseDelaySeconds: 5, MaxDelaySeconds: 2}, 4, 5 * time.Second},
+		{"no cap", config.LoginConfig{BaseDelaySeconds: 3}, 10, 3 * time.Second},
+		{"no base delay", config.LoginConfig{MaxDelaySeconds: 30}, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loginBackoff(tt.cfg, tt.failures); got != tt.want {
+				t.Errorf("loginBackoff(%+v, %d) = %v, want %v", tt.cfg, tt.failures, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/repository"
+	"github.com/aprilboiz/flight-management/pkg/config"
+)
+
+// estimatedCruiseSpeedKmh is assumed for planes of unknown type. It also converts a flight
+// duration into an approximate distance flown when an airport has no coordinates.
+const estimatedCruiseSpeedKmh = 800
+
+// loyaltyTierOrder lists the tiers from lowest to highest qualification.
+var loyaltyTierOrder = []models.LoyaltyTier{
+	models.LoyaltyTierBasic,
+	models.LoyaltyTierSilver,
+	models.LoyaltyTierGold,
+	models.LoyaltyTierPlatinum,
+}
+
+type loyaltyService struct {
+	loyaltyRepo   repository.LoyaltyRepository
+	passengerRepo repository.PassengerRepository
+}
+
+func NewLoyaltyService(loyaltyRepo repository.LoyaltyRepository, passengerRepo repository.PassengerRepository) LoyaltyService {
+	if loyaltyRepo == nil || passengerRepo == nil {
+		panic("Missing required repositories for loyalty service")
+	}
+	return &loyaltyService{
+		loyaltyRepo:   loyaltyRepo,
+		passengerRepo: passengerRepo,
+	}
+}
+
+func (l loyaltyService) Enroll(passengerID uint) (*dto.LoyaltyAccountResponse, error) {
+	passenger, err := l.passengerRepo.GetByID(passengerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, err := l.loyaltyRepo.GetAccountByPassengerID(passenger.ID); err == nil {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("passenger is already enrolled as member %s", existing.MemberNumber), nil)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	memberNumber, err := generateMemberNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := l.loyaltyRepo.CreateAccount(&models.LoyaltyAccount{
+		PassengerID:  passenger.ID,
+		MemberNumber: memberNumber,
+		Tier:         models.LoyaltyTierBasic,
+	})
+	if err != nil {
+		return nil, err
+	}
+	account.Passenger = *passenger
+
+	return l.toAccountResponse(account)
+}
+
+func (l loyaltyService) GetAccount(memberNumber string) (*dto.LoyaltyAccountResponse, error) {
+	account, err := l.loyaltyRepo.GetAccountByMemberNumber(memberNumber)
+	if err != nil {
+		return nil, err
+	}
+	return l.toAccountResponse(account)
+}
+
+func (l loyaltyService) GetAccountByPassengerID(passengerID uint) (*dto.LoyaltyAccountResponse, error) {
+	account, err := l.loyaltyRepo.GetAccountByPassengerID(passengerID)
+	if err != nil {
+		return nil, err
+	}
+	return l.toAccountResponse(account)
+}
+
+func (l loyaltyService) GetTransactions(memberNumber string) ([]*dto.LoyaltyTransactionResponse, error) {
+	account, err := l.loyaltyRepo.GetAccountByMemberNumber(memberNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := l.loyaltyRepo.GetTransactions(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.LoyaltyTransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		responses[i] = &dto.LoyaltyTransactionResponse{
+			ID:           transaction.ID,
+			TicketID:     transaction.TicketID,
+			Type:         transaction.Type,
+			Points:       transaction.Points,
+			BalanceAfter: transaction.BalanceAfter,
+			Description:  transaction.Description,
+			CreatedAt:    transaction.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return responses, nil
+}
+
+func (l loyaltyService) QuoteRedemption(passengerID uint, points int, fare float64) (float64, error) {
+	if points <= 0 {
+		return 0, nil
+	}
+
+	account, err := l.loyaltyRepo.GetAccountByPassengerID(passengerID)
+	if err != nil {
+		if isNotFound(err) {
+			return 0, exceptions.BadRequestError("passenger is not a loyalty member and cannot redeem points", nil)
+		}
+		return 0, err
+	}
+
+	balance, err := l.loyaltyRepo.GetBalance(account.ID)
+	if err != nil {
+		return 0, err
+	}
+	if points > balance {
+		return 0, exceptions.BadRequestError(fmt.Sprintf("insufficient loyalty points: %d available", balance), nil)
+	}
+
+	cfg := config.GetConfig().Loyalty
+	discount := float64(points) * cfg.PointValue
+	if maxDiscount := fare * cfg.MaxRedemptionRatio; discount > maxDiscount {
+		return 0, exceptions.BadRequestError(
+			fmt.Sprintf("points can cover at most %.0f%% of the fare (%d points)", cfg.MaxRedemptionRatio*100, int(maxDiscount/cfg.PointValue)), nil)
+	}
+	return discount, nil
+}
+
+func (l loyaltyService) RedeemForTicket(passengerID uint, ticketID uint, points int) error {
+	account, err := l.loyaltyRepo.GetAccountByPassengerID(passengerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.loyaltyRepo.AppendTransaction(&models.LoyaltyTransaction{
+		AccountID:   account.ID,
+		TicketID:    &ticketID,
+		Type:        models.LoyaltyTransactionRedeem,
+		Points:      -points,
+		Description: fmt.Sprintf("Redeemed against ticket %d", ticketID),
+	}, qualifyingSince(), tierForPoints)
+	return err
+}
+
+func (l loyaltyService) AccrueForTicket(ticket *models.Ticket) error {
+	account, err := l.accountForTicket(ticket)
+	if err != nil || account == nil {
+		return err
+	}
+
+	// Accrual is idempotent: a ticket earns once unless the earning was reversed
+	ticketPoints, err := l.loyaltyRepo.GetTicketPoints(ticket.ID)
+	if err != nil {
+		return err
+	}
+	if ticketPoints[models.LoyaltyTransactionEarn]+ticketPoints[models.LoyaltyTransactionEarnReversal] > 0 {
+		return nil
+	}
+
+	points := calculateEarnedPoints(ticket)
+	if points <= 0 {
+		return nil
+	}
+
+	_, err = l.loyaltyRepo.AppendTransaction(&models.LoyaltyTransaction{
+		AccountID:   account.ID,
+		TicketID:    &ticket.ID,
+		Type:        models.LoyaltyTransactionEarn,
+		Points:      points,
+		Description: fmt.Sprintf("Flight %s, seat %s", ticket.Flight.FlightCode, ticket.Seat.SeatNumber),
+	}, qualifyingSince(), tierForPoints)
+	return err
+}
+
+func (l loyaltyService) ReverseForTicket(ticket *models.Ticket, reverseEarned bool) error {
+	account, err := l.accountForTicket(ticket)
+	if err != nil || account == nil {
+		return err
+	}
+
+	ticketPoints, err := l.loyaltyRepo.GetTicketPoints(ticket.ID)
+	if err != nil {
+		return err
+	}
+
+	// Give back points that were spent on the fare and not yet returned
+	if redeemed := -(ticketPoints[models.LoyaltyTransactionRedeem] + ticketPoints[models.LoyaltyTransactionRedeemReversal]); redeemed > 0 {
+		if _, err := l.loyaltyRepo.AppendTransaction(&models.LoyaltyTransaction{
+			AccountID:   account.ID,
+			TicketID:    &ticket.ID,
+			Type:        models.LoyaltyTransactionRedeemReversal,
+			Points:      redeemed,
+			Description: fmt.Sprintf("Redeemed points returned, ticket %d %s", ticket.ID, ticket.TicketStatus),
+		}, qualifyingSince(), tierForPoints); err != nil {
+			return err
+		}
+	}
+
+	if !reverseEarned {
+		return nil
+	}
+
+	// Take back points earned on the ticket. The balance may not cover them if the member
+	// has already spent them, in which case only the available balance is reversed.
+	earned := ticketPoints[models.LoyaltyTransactionEarn] + ticketPoints[models.LoyaltyTransactionEarnReversal]
+	if earned > 0 {
+		balance, err := l.loyaltyRepo.GetBalance(account.ID)
+		if err != nil {
+			return err
+		}
+		reversal := min(earned, balance)
+		if reversal > 0 {
+			if _, err := l.loyaltyRepo.AppendTransaction(&models.LoyaltyTransaction{
+				AccountID:   account.ID,
+				TicketID:    &ticket.ID,
+				Type:        models.LoyaltyTransactionEarnReversal,
+				Points:      -reversal,
+				Description: fmt.Sprintf("Earned points reversed, ticket %d refunded", ticket.ID),
+			}, qualifyingSince(), tierForPoints); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RefreshTiers lowers the tier of members whose points earned over the last 12 months no
+// longer qualify for it. Tiers are otherwise updated as points are posted.
+func (l loyaltyService) RefreshTiers() (int, error) {
+	return l.loyaltyRepo.RefreshTiers(qualifyingSince(), tierForPoints)
+}
+
+// accountForTicket returns the loyalty account of the ticket's passenger, or nil when the
+// passenger is not a member.
+func (l loyaltyService) accountForTicket(ticket *models.Ticket) (*models.LoyaltyAccount, error) {
+	if ticket.PassengerID == nil {
+		return nil, nil
+	}
+	account, err := l.loyaltyRepo.GetAccountByPassengerID(*ticket.PassengerID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+// qualifyingSince starts the rolling 12 months over which earned points count towards tiers.
+func qualifyingSince() time.Time {
+	return time.Now().AddDate(-1, 0, 0)
+}
+
+func (l loyaltyService) toAccountResponse(account *models.LoyaltyAccount) (*dto.LoyaltyAccountResponse, error) {
+	balance, err := l.loyaltyRepo.GetBalance(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	qualifying, err := l.loyaltyRepo.GetQualifyingPoints(account.ID, qualifyingSince())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.LoyaltyAccountResponse{
+		MemberNumber:     account.MemberNumber,
+		PassengerID:      account.PassengerID,
+		PassengerName:    account.Passenger.FullName,
+		Tier:             account.Tier,
+		Balance:          balance,
+		QualifyingPoints: qualifying,
+		RedemptionValue:  float64(balance) * config.GetConfig().Loyalty.PointValue,
+		MemberSince:      account.CreatedAt.Format(time.DateOnly),
+	}
+	if next, threshold, ok := nextTier(account.Tier); ok {
+		response.NextTier = next
+		response.PointsToNextTier = max(threshold-qualifying, 0)
+	}
+	return response, nil
+}
+
+// calculateEarnedPoints awards points for the fare paid and the distance flown, scaled by
+// the multiplier of the seat's ticket class.
+func calculateEarnedPoints(ticket *models.Ticket) int {
+	cfg := config.GetConfig().Loyalty
+
+	classMultiplier, ok := cfg.ClassMultipliers[ticket.Seat.TicketClass.TicketClassName]
+	if !ok {
+		classMultiplier = 1
+	}
+	distanceKm, _ := flightDistance(&ticket.Flight)
+	if distanceKm == 0 {
+		distanceKm = float64(ticket.Flight.FlightDuration) / 60 * estimatedCruiseSpeedKmh
+	}
+
+	points := (ticket.Price*cfg.PointsPerUnitSpent + distanceKm*cfg.PointsPerKilometer) * classMultiplier
+	return int(math.Round(points))
+}
+
+func tierForPoints(points int) models.LoyaltyTier {
+	thresholds := config.GetConfig().Loyalty.TierThresholds
+	tier := models.LoyaltyTierBasic
+	for _, candidate := range loyaltyTierOrder[1:] {
+		threshold, ok := thresholds[string(candidate)]
+		if ok && points >= threshold {
+			tier = candidate
+		}
+	}
+	return tier
+}
+
+func nextTier(current models.LoyaltyTier) (models.LoyaltyTier, int, bool) {
+	thresholds := config.GetConfig().Loyalty.TierThresholds
+	passed := false
+	for _, candidate := range loyaltyTierOrder {
+		if passed {
+			if threshold, ok := thresholds[string(candidate)]; ok {
+				return candidate, threshold, true
+			}
+		}
+		if candidate == current {
+			passed = true
+		}
+	}
+	return "", 0, false
+}
+
+func generateMemberNumber() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000_000))
+	if err != nil {
+		return "", exceptions.InternalError("failed to generate member number", err)
+	}
+	return fmt.Sprintf("%s%09d", config.GetConfig().Loyalty.MemberNumberPrefix, n.Int64()), nil
+}
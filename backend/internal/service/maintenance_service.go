@@ -0,0 +1,291 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/repository"
+	"github.com/aprilboiz/flight-management/pkg/config"
+)
+
+const (
+	// defaultAvailabilityDays is the length of the fleet calendar when no end date is given.
+	defaultAvailabilityDays = 30
+	// maxAvailabilityDays caps the length of the fleet calendar.
+	maxAvailabilityDays = 92
+)
+
+func NewMaintenanceService(maintenanceRepo repository.MaintenanceRepository, planeRepo repository.PlaneRepository, flightRepo repository.FlightRepository, ticketRepo repository.TicketRepository) MaintenanceService {
+	if maintenanceRepo == nil || planeRepo == nil || flightRepo == nil || ticketRepo == nil {
+		panic("Missing required repositories for maintenance service")
+	}
+	return &maintenanceService{
+		maintenanceRepo: maintenanceRepo,
+		planeRepo:       planeRepo,
+		flightRepo:      flightRepo,
+		ticketRepo:      ticketRepo,
+	}
+}
+
+type maintenanceService struct {
+	maintenanceRepo repository.MaintenanceRepository
+	planeRepo       repository.PlaneRepository
+	flightRepo      repository.FlightRepository
+	ticketRepo      repository.TicketRepository
+}
+
+func (m maintenanceService) GetWindows(planeCode string) ([]*dto.MaintenanceWindowResponse, error) {
+	plane, err := m.planeRepo.GetByCode(planeCode)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := m.maintenanceRepo.GetByPlaneID(plane.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.MaintenanceWindowResponse, len(windows))
+	for i, window := range windows {
+		responses[i] = toMaintenanceWindowResponse(window, plane.PlaneCode)
+	}
+	return responses, nil
+}
+
+// CreateWindow takes the plane out of service for the window. Flights already scheduled
+// inside the window are not changed; they are returned so they can be moved.
+func (m maintenanceService) CreateWindow(planeCode string, request *dto.MaintenanceWindowRequest, createdBy string) (*dto.MaintenanceWindowResponse, error) {
+	plane, err := m.planeRepo.GetByCode(planeCode)
+	if err != nil {
+		return nil, err
+	}
+	if plane.RetiredAt != nil {
+		return nil, exceptions.BadRequestError(fmt.Sprintf("plane '%s' is retired", plane.PlaneCode), nil)
+	}
+
+	loc, _ := time.LoadLocation(config.GetConfig().Database.Timezone)
+	startTime, err := time.ParseInLocation(time.DateTime, request.StartTime, loc)
+	if err != nil {
+		return nil, exceptions.BadRequestError("invalid start time format", err)
+	}
+	endTime, err := time.ParseInLocation(time.DateTime, request.EndTime, loc)
+	if err != nil {
+		return nil, exceptions.BadRequestError("invalid end time format", err)
+	}
+	if !endTime.After(startTime) {
+		return nil, exceptions.BadRequestError("end time must be after start time", nil)
+	}
+	if !endTime.After(time.Now()) {
+		return nil, exceptions.BadRequestError("maintenance window cannot end in the past", nil)
+	}
+
+	overlapping, err := m.maintenanceRepo.GetOverlapping(plane.ID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	if len(overlapping) > 0 {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("plane '%s' already has a maintenance window from %s to %s", plane.PlaneCode,
+				overlapping[0].StartTime.In(loc).Format(time.DateTime), overlapping[0].EndTime.In(loc).Format(time.DateTime)), nil)
+	}
+
+	window, err := m.maintenanceRepo.Create(&models.MaintenanceWindow{
+		PlaneID:   plane.ID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Reason:    request.Reason,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toMaintenanceWindowResponse(window, plane.PlaneCode)
+	response.AffectedFlights, err = m.affectedFlights(window)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (m maintenanceService) DeleteWindow(planeCode string, id uint) error {
+	window, err := m.getPlaneWindow(planeCode, id)
+	if err != nil {
+		return err
+	}
+	return m.maintenanceRepo.Delete(window.ID)
+}
+
+func (m maintenanceService) GetAffectedFlights(planeCode string, id uint) ([]dto.AffectedFlightResponse, error) {
+	window, err := m.getPlaneWindow(planeCode, id)
+	if err != nil {
+		return nil, err
+	}
+	return m.affectedFlights(window)
+}
+
+// GetFleetAvailability returns a day-by-day calendar of every plane's flights and maintenance.
+func (m maintenanceService) GetFleetAvailability(query *dto.FleetAvailabilityQuery) (*dto.FleetAvailabilityResponse, error) {
+	loc, _ := time.LoadLocation(config.GetConfig().Database.Timezone)
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if query.From != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, query.From, loc)
+		if err != nil {
+			return nil, exceptions.BadRequestError("invalid from date, expected YYYY-MM-DD", err)
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, defaultAvailabilityDays-1)
+	if query.To != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, query.To, loc)
+		if err != nil {
+			return nil, exceptions.BadRequestError("invalid to date, expected YYYY-MM-DD", err)
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		return nil, exceptions.BadRequestError("to date must not be before from date", nil)
+	}
+	days := 0
+	for day := from; !day.After(to) && days <= maxAvailabilityDays; day = day.AddDate(0, 0, 1) {
+		days++
+	}
+	if days > maxAvailabilityDays {
+		return nil, exceptions.BadRequestError(fmt.Sprintf("the calendar can span at most %d days", maxAvailabilityDays), nil)
+	}
+	end := to.AddDate(0, 0, 1)
+
+	planes, err := m.planeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	planeIDs := make([]uint, len(planes))
+	for i, plane := range planes {
+		planeIDs[i] = plane.ID
+	}
+	flights, err := m.flightRepo.GetByPlanesInRange(planeIDs, from, end)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := m.maintenanceRepo.GetInRange(from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	flightsByPlane := make(map[uint][]*models.Flight)
+	for _, flight := range flights {
+		flightsByPlane[flight.PlaneID] = append(flightsByPlane[flight.PlaneID], flight)
+	}
+	windowsByPlane := make(map[uint][]*models.MaintenanceWindow)
+	for _, window := range windows {
+		windowsByPlane[window.PlaneID] = append(windowsByPlane[window.PlaneID], window)
+	}
+
+	response := &dto.FleetAvailabilityResponse{
+		From:   from.Format(time.DateOnly),
+		To:     to.Format(time.DateOnly),
+		Planes: make([]dto.PlaneAvailability, len(planes)),
+	}
+	for i, plane := range planes {
+		availability := dto.PlaneAvailability{
+			PlaneCode: plane.PlaneCode,
+			PlaneName: plane.PlaneName,
+			Days:      make([]dto.PlaneDayAvailability, days),
+		}
+		for d := range days {
+			dayStart := from.AddDate(0, 0, d)
+			dayEnd := dayStart.AddDate(0, 0, 1)
+			day := dto.PlaneDayAvailability{
+				Date:   dayStart.Format(time.DateOnly),
+				Status: dto.PlaneDayAvailable,
+			}
+			for _, flight := range flightsByPlane[plane.ID] {
+				if overlaps(flight.DepartureDateTime, flightArrival(flight), dayStart, dayEnd) {
+					day.Flights = append(day.Flights, flight.FlightCode)
+				}
+			}
+			for _, window := range windowsByPlane[plane.ID] {
+				if overlaps(window.StartTime, window.EndTime, dayStart, dayEnd) {
+					day.Maintenance = append(day.Maintenance, window.Reason)
+				}
+			}
+
+			switch {
+			case plane.RetiredAt != nil && plane.RetiredAt.Before(dayEnd):
+				day.Status = dto.PlaneDayRetired
+			case len(day.Maintenance) > 0:
+				day.Status = dto.PlaneDayMaintenance
+			case len(day.Flights) > 0:
+				day.Status = dto.PlaneDayScheduled
+			}
+			availability.Days[d] = day
+		}
+		response.Planes[i] = availability
+	}
+	return response, nil
+}
+
+func (m maintenanceService) getPlaneWindow(planeCode string, id uint) (*models.MaintenanceWindow, error) {
+	window, err := m.maintenanceRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if window.Plane.PlaneCode != planeCode {
+		return nil, exceptions.NotFoundError("maintenance window", strconv.Itoa(int(id)))
+	}
+	return window, nil
+}
+
+// affectedFlights lists the plane's flights that are in the air during the window.
+func (m maintenanceService) affectedFlights(window *models.MaintenanceWindow) ([]dto.AffectedFlightResponse, error) {
+	flights, err := m.flightRepo.GetByPlanesInRange([]uint{window.PlaneID}, window.StartTime, window.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	flightIDs := make([]uint, len(flights))
+	for i, flight := range flights {
+		flightIDs[i] = flight.ID
+	}
+	activeTickets, err := m.ticketRepo.CountActiveByFlightIDs(flightIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	affected := make([]dto.AffectedFlightResponse, len(flights))
+	for i, flight := range flights {
+		affected[i] = dto.AffectedFlightResponse{
+			FlightCode:        flight.FlightCode,
+			DepartureAirport:  flight.DepartureAirport.AirportCode,
+			ArrivalAirport:    flight.ArrivalAirport.AirportCode,
+			DepartureDateTime: flight.DepartureDateTime.Format(time.RFC3339),
+			ArrivalDateTime:   flightArrival(flight).Format(time.RFC3339),
+			ActiveTickets:     activeTickets[flight.ID],
+		}
+	}
+	return affected, nil
+}
+
+func toMaintenanceWindowResponse(window *models.MaintenanceWindow, planeCode string) *dto.MaintenanceWindowResponse {
+	return &dto.MaintenanceWindowResponse{
+		ID:        window.ID,
+		PlaneCode: planeCode,
+		StartTime: window.StartTime.Format(time.RFC3339),
+		EndTime:   window.EndTime.Format(time.RFC3339),
+		Reason:    window.Reason,
+		CreatedBy: window.CreatedBy,
+	}
+}
+
+// flightArrival returns when the flight reaches its final destination.
+func flightArrival(flight *models.Flight) time.Time {
+	return flight.DepartureDateTime.Add(time.Duration(flight.FlightDuration) * time.Minute)
+}
+
+// overlaps reports whether the intervals [start, end) and [from, to) share any time.
+func overlaps(start, end, from, to time.Time) bool {
+	return start.Before(to) && end.After(from)
+}
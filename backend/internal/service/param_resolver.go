@@ -0,0 +1,47 @@
+package service
+
+import "github.com/aprilboiz/flight-management/internal/models"
+
+// Parameter sources, from the most general to the most specific
+const (
+	parameterSourceGlobal = "GLOBAL" // The parameter version in force
+	parameterSourceRoute  = "ROUTE"  // An override on the flight's route
+	parameterSourceFlight = "FLIGHT" // An override on the flight itself
+)
+
+// resolveParameters applies the overrides of a route and then of a flight to the global
+// parameters, the more specific override winning. Either may be nil. Along with the effective
+// parameters it returns where each value came from, by JSON name.
+func resolveParameters(params *models.Parameter, route *models.Route, flight *models.Flight) (*models.Parameter, map[string]string) {
+	effective := *params
+	sources := make(map[string]string, len(parameterFields))
+	for _, field := range parameterFields {
+		sources[field.name] = parameterSourceGlobal
+		if route != nil && field.route != nil {
+			if override := field.route(route); override != nil {
+				*field.value(&effective) = *override
+				sources[field.name] = parameterSourceRoute
+			}
+		}
+		if flight != nil && field.flight != nil {
+			if override := field.flight(flight); override != nil {
+				*field.value(&effective) = *override
+				sources[field.name] = parameterSourceFlight
+			}
+		}
+	}
+	return &effective, sources
+}
+
+// routeParameters returns the parameters in force on a route.
+func routeParameters(params *models.Parameter, route *models.Route) *models.Parameter {
+	effective, _ := resolveParameters(params, route, nil)
+	return effective
+}
+
+// flightParameters returns the parameters a flight is held to. Flights scheduled before routes
+// existed only have their own overrides.
+func flightParameters(params *models.Parameter, flight *models.Flight) *models.Parameter {
+	effective, _ := resolveParameters(params, flight.Route, flight)
+	return effective
+}
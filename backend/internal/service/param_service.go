@@ -1,24 +1,316 @@
 package service
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/internal/repository"
+	"github.com/aprilboiz/flight-management/pkg/config"
+)
+
+// Parameter version statuses
+const (
+	parameterVersionScheduled  = "SCHEDULED"  // Takes effect in the future
+	parameterVersionInForce    = "IN_FORCE"   // The version services use now
+	parameterVersionSuperseded = "SUPERSEDED" // Replaced by a later version
 )
 
-func NewParamService(paramRepo repository.ParameterRepository) ParameterService {
+// parameterField is a business rule held by every parameter version. Rules a route or a flight
+// can override have accessors for the override, nil otherwise.
+type parameterField struct {
+	name      string // JSON name
+	min       int    // Smallest value allowed
+	value     func(params *models.Parameter) *int
+	requested func(request *dto.ParameterRequest) *int
+	route     func(route *models.Route) *int
+	flight    func(flight *models.Flight) *int
+}
+
+var parameterFields = []parameterField{
+	{name: "number_of_airports", min: 2,
+		value:     func(p *models.Parameter) *int { return &p.NumberOfAirports },
+		requested: func(r *dto.ParameterRequest) *int { return r.NumberOfAirports }},
+	{name: "min_flight_duration", min: 1,
+		value:     func(p *models.Parameter) *int { return &p.MinFlightDuration },
+		requested: func(r *dto.ParameterRequest) *int { return r.MinFlightDuration },
+		route:     func(r *models.Route) *int { return r.MinFlightDuration }},
+	{name: "max_intermediate_stops", min: 0,
+		value:     func(p *models.Parameter) *int { return &p.MaxIntermediateStops },
+		requested: func(r *dto.ParameterRequest) *int { return r.MaxIntermediateStops },
+		route:     func(r *models.Route) *int { return r.MaxIntermediateStops }},
+	{name: "min_intermediate_stop_duration", min: 0,
+		value:     func(p *models.Parameter) *int { return &p.MinIntermediateStopDuration },
+		requested: func(r *dto.ParameterRequest) *int { return r.MinIntermediateStopDuration },
+		route:     func(r *models.Route) *int { return r.MinIntermediateStopDuration }},
+	{name: "max_intermediate_stop_duration", min: 0,
+		value:     func(p *models.Parameter) *int { return &p.MaxIntermediateStopDuration },
+		requested: func(r *dto.ParameterRequest) *int { return r.MaxIntermediateStopDuration },
+		route:     func(r *models.Route) *int { return r.MaxIntermediateStopDuration }},
+	{name: "max_ticket_classes", min: 1,
+		value:     func(p *models.Parameter) *int { return &p.MaxTicketClasses },
+		requested: func(r *dto.ParameterRequest) *int { return r.MaxTicketClasses }},
+	{name: "latest_ticket_purchase_time", min: 0,
+		value:     func(p *models.Parameter) *int { return &p.LatestTicketPurchaseTime },
+		requested: func(r *dto.ParameterRequest) *int { return r.LatestTicketPurchaseTime },
+		route:     func(r *models.Route) *int { return r.LatestTicketPurchaseTime },
+		flight:    func(f *models.Flight) *int { return f.LatestTicketPurchaseTime }},
+	{name: "ticket_cancellation_time", min: 0,
+		value:     func(p *models.Parameter) *int { return &p.TicketCancellationTime },
+		requested: func(r *dto.ParameterRequest) *int { return r.TicketCancellationTime },
+		route:     func(r *models.Route) *int { return r.TicketCancellationTime },
+		flight:    func(f *models.Flight) *int { return f.TicketCancellationTime }},
+}
+
+func NewParamService(paramRepo repository.ParameterRepository, flightRepo repository.FlightRepository, ticketRepo repository.TicketRepository, routeRepo repository.RouteRepository, airportRepo repository.AirportRepository, ticketClassRepo repository.TicketClassRepository) ParameterService {
+	if paramRepo == nil || flightRepo == nil || ticketRepo == nil || routeRepo == nil || airportRepo == nil || ticketClassRepo == nil {
+		panic("Missing required repositories for parameter service")
+	}
 	return &paramService{
-		paramRepo: paramRepo,
+		paramRepo:       paramRepo,
+		flightRepo:      flightRepo,
+		ticketRepo:      ticketRepo,
+		routeRepo:       routeRepo,
+		airportRepo:     airportRepo,
+		ticketClassRepo: ticketClassRepo,
 	}
 }
 
 type paramService struct {
-	paramRepo repository.ParameterRepository
+	paramRepo       repository.ParameterRepository
+	flightRepo      repository.FlightRepository
+	ticketRepo      repository.TicketRepository
+	routeRepo       repository.RouteRepository
+	airportRepo     repository.AirportRepository
+	ticketClassRepo repository.TicketClassRepository
+}
+
+// GetAllParams returns the parameter version in force now.
+func (p paramService) GetAllParams() (*dto.ParameterVersionResponse, error) {
+	params, err := p.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+	return toParameterVersionResponse(params, params.Version), nil
+}
+
+// GetVersions returns the history of the parameters, newest version first, including the
+// versions scheduled to take effect later.
+func (p paramService) GetVersions() ([]*dto.ParameterVersionResponse, error) {
+	inForce, err := p.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+	versions, err := p.paramRepo.GetVersions()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.ParameterVersionResponse, len(versions))
+	for i, version := range versions {
+		responses[i] = toParameterVersionResponse(version, inForce.Version)
+	}
+	return responses, nil
 }
 
-func (p paramService) GetAllParams() (*models.Parameter, error) {
-	return p.paramRepo.GetAllParams()
+func (p paramService) GetVersion(version int) (*dto.ParameterVersionResponse, error) {
+	inForce, err := p.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+	params, err := p.paramRepo.GetVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return toParameterVersionResponse(params, inForce.Version), nil
+}
+
+// UpdateParams adds a parameter version taking effect now or at the requested time. Values
+// left out of the request are taken from the version in force at that time. Unless forced, the
+// change is refused when existing flights or routes break its rules.
+func (p paramService) UpdateParams(request *dto.ParameterRequest, author string, force bool) (*dto.ParameterVersionResponse, error) {
+	params, replaced, err := p.newVersion(request, author)
+	if err != nil {
+		return nil, err
+	}
+	return p.saveVersion(params, replaced, force)
+}
+
+// AnalyzeParams lists what the requested change would break without saving it.
+func (p paramService) AnalyzeParams(request *dto.ParameterRequest) (*dto.ParameterImpactResponse, error) {
+	params, replaced, err := p.newVersion(request, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := validateParameters(params); err != nil {
+		return nil, err
+	}
+	return p.analyzeImpact(params, replaced)
 }
 
-func (p paramService) UpdateParams(params *models.Parameter) (*models.Parameter, error) {
-	return p.paramRepo.UpdateParams(params)
+// newVersion builds the parameter version a request asks for, along with the version it
+// replaces.
+func (p paramService) newVersion(request *dto.ParameterRequest, author string) (*models.Parameter, *models.Parameter, error) {
+	now := time.Now()
+	effectiveFrom := now
+	if request.EffectiveFrom != "" {
+		loc, _ := time.LoadLocation(config.GetConfig().Database.Timezone)
+		var err error
+		effectiveFrom, err = time.ParseInLocation(time.DateTime, request.EffectiveFrom, loc)
+		if err != nil {
+			return nil, nil, exceptions.BadRequestError("invalid effective date time format", err)
+		}
+		if effectiveFrom.Before(now) {
+			return nil, nil, exceptions.BadRequestError("effective date time cannot be in the past", nil)
+		}
+	}
+
+	replaced, err := p.paramRepo.GetParamsAt(effectiveFrom)
+	if err != nil {
+		return nil, nil, err
+	}
+	params := &models.Parameter{
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     author,
+		Comment:       request.Comment,
+	}
+	for _, field := range parameterFields {
+		*field.value(params) = *field.value(replaced)
+		if requested := field.requested(request); requested != nil {
+			*field.value(params) = *requested
+		}
+	}
+	params.Changes = diffParameters(replaced, params)
+	if len(params.Changes) == 0 {
+		return nil, nil, exceptions.BadRequestError(
+			fmt.Sprintf("the parameters are the same as in version %d", replaced.Version), nil)
+	}
+	return params, replaced, nil
+}
+
+// RollbackParams restores the values of an earlier version by adding a version that takes
+// effect now. Unless forced, the rollback is refused when existing flights or routes break
+// the restored rules.
+func (p paramService) RollbackParams(version int, author string, force bool) (*dto.ParameterVersionResponse, error) {
+	restored, err := p.paramRepo.GetVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	inForce, err := p.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+
+	params := &models.Parameter{
+		EffectiveFrom:   time.Now(),
+		CreatedBy:       author,
+		Comment:         fmt.Sprintf("Rollback to version %d", restored.Version),
+		RestoredVersion: &restored.Version,
+	}
+	for _, field := range parameterFields {
+		*field.value(params) = *field.value(restored)
+	}
+	params.Changes = diffParameters(inForce, params)
+	if len(params.Changes) == 0 {
+		return nil, exceptions.BadRequestError(
+			fmt.Sprintf("version %d has the same values as the version in force", restored.Version), nil)
+	}
+	return p.saveVersion(params, inForce, force)
+}
+
+// saveVersion checks a new version against the invariants and the existing data, then saves
+// it. A version that breaks existing flights or routes is only saved when forced, and the
+// response then lists what it broke.
+func (p paramService) saveVersion(params, replaced *models.Parameter, force bool) (*dto.ParameterVersionResponse, error) {
+	if err := validateParameters(params); err != nil {
+		return nil, err
+	}
+	impact, err := p.analyzeImpact(params, replaced)
+	if err != nil {
+		return nil, err
+	}
+	broken := len(impact.Violations) > 0 || len(impact.Routes) > 0 || len(impact.Flights) > 0 || len(impact.Tickets) > 0
+	if broken && !force {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT, fmt.Sprintf(
+			"the new parameters break %d limits, %d routes, %d flights and the deadlines of %d tickets, affecting %d active tickets, force the change to apply it anyway",
+			len(impact.Violations), len(impact.Routes), impact.AffectedFlights, len(impact.Tickets), impact.AffectedTickets), impact)
+	}
+
+	if _, err := p.paramRepo.CreateVersion(params); err != nil {
+		return nil, err
+	}
+	response, err := p.versionResponse(params)
+	if err != nil {
+		return nil, err
+	}
+	if broken {
+		response.Impact = impact
+	}
+	return response, nil
+}
+
+// CancelScheduledParams removes a version that has not taken effect yet. Versions already in
+// force stay in the history and are undone with a rollback instead.
+func (p paramService) CancelScheduledParams(version int) error {
+	params, err := p.paramRepo.GetVersion(version)
+	if err != nil {
+		return err
+	}
+	if !params.EffectiveFrom.After(time.Now()) {
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("version %d has already taken effect, roll back to an earlier version instead", version), nil)
+	}
+	return p.paramRepo.DeleteVersion(params)
+}
+
+func (p paramService) versionResponse(params *models.Parameter) (*dto.ParameterVersionResponse, error) {
+	inForce, err := p.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+	return toParameterVersionResponse(params, inForce.Version), nil
+}
+
+// diffParameters lists the business rules that differ between two parameter versions.
+func diffParameters(from, to *models.Parameter) []models.ParameterChange {
+	var changes []models.ParameterChange
+	for _, field := range parameterFields {
+		if oldValue, newValue := *field.value(from), *field.value(to); oldValue != newValue {
+			changes = append(changes, models.ParameterChange{Field: field.name, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return changes
+}
+
+func toParameterVersionResponse(params *models.Parameter, inForceVersion int) *dto.ParameterVersionResponse {
+	status := parameterVersionSuperseded
+	switch {
+	case params.Version == inForceVersion:
+		status = parameterVersionInForce
+	case params.EffectiveFrom.After(time.Now()):
+		status = parameterVersionScheduled
+	}
+	changes := params.Changes
+	if changes == nil {
+		changes = []models.ParameterChange{}
+	}
+	return &dto.ParameterVersionResponse{
+		Version:                     params.Version,
+		Status:                      status,
+		EffectiveFrom:               params.EffectiveFrom.Format(time.RFC3339),
+		CreatedBy:                   params.CreatedBy,
+		CreatedAt:                   params.CreatedAt.Format(time.RFC3339),
+		Comment:                     params.Comment,
+		RestoredVersion:             params.RestoredVersion,
+		Changes:                     changes,
+		NumberOfAirports:            params.NumberOfAirports,
+		MinFlightDuration:           params.MinFlightDuration,
+		MaxIntermediateStops:        params.MaxIntermediateStops,
+		MinIntermediateStopDuration: params.MinIntermediateStopDuration,
+		MaxIntermediateStopDuration: params.MaxIntermediateStopDuration,
+		MaxTicketClasses:            params.MaxTicketClasses,
+		LatestTicketPurchaseTime:    params.LatestTicketPurchaseTime,
+		TicketCancellationTime:      params.TicketCancellationTime,
+	}
 }
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+)
+
+// ruleViolation is a business rule broken by existing data, keyed by the rule so the same
+// breach under two parameter versions can be recognised.
+type ruleViolation struct {
+	rule    string
+	message string
+}
+
+// validateParameters checks the invariants every parameter version must hold on its own,
+// whatever the existing data.
+func validateParameters(params *models.Parameter) error {
+	var violations []string
+	for _, field := range parameterFields {
+		if value := *field.value(params); value < field.min {
+			violations = append(violations, fmt.Sprintf("%s must be at least %d, got %d", field.name, field.min, value))
+		}
+	}
+	if params.MinIntermediateStopDuration > params.MaxIntermediateStopDuration {
+		violations = append(violations, fmt.Sprintf(
+			"min_intermediate_stop_duration (%d) cannot exceed max_intermediate_stop_duration (%d)",
+			params.MinIntermediateStopDuration, params.MaxIntermediateStopDuration))
+	}
+	if len(violations) > 0 {
+		return exceptions.NewAppError(exceptions.BadRequest, "the parameters are inconsistent", violations)
+	}
+	return nil
+}
+
+// analyzeImpact lists the existing data that breaks the rules of params but not those of the
+// version it replaces. Flights departing before params take effect keep the old rules and are
+// not checked. Tickets are checked for purchase and cancellation deadlines that are still open
+// under the old rules when params take effect, but already past under params.
+func (p paramService) analyzeImpact(params, replaced *models.Parameter) (*dto.ParameterImpactResponse, error) {
+	impact := &dto.ParameterImpactResponse{
+		Violations: []string{},
+		Routes:     []dto.ParameterRouteImpact{},
+		Flights:    []dto.ParameterFlightImpact{},
+		Tickets:    []dto.ParameterTicketImpact{},
+	}
+
+	if params.NumberOfAirports < replaced.NumberOfAirports {
+		airports, err := p.airportRepo.Count()
+		if err != nil {
+			return nil, err
+		}
+		if airports > int64(params.NumberOfAirports) {
+			impact.Violations = append(impact.Violations, fmt.Sprintf(
+				"there are %d airports, more than the limit of %d", airports, params.NumberOfAirports))
+		}
+	}
+	if params.MaxTicketClasses < replaced.MaxTicketClasses {
+		ticketClasses, err := p.ticketClassRepo.Count()
+		if err != nil {
+			return nil, err
+		}
+		if ticketClasses > int64(params.MaxTicketClasses) {
+			impact.Violations = append(impact.Violations, fmt.Sprintf(
+				"there are %d ticket classes, more than the limit of %d", ticketClasses, params.MaxTicketClasses))
+		}
+	}
+
+	routes, err := p.routeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, route := range routes {
+		violations := newViolations(
+			routeViolations(route, routeParameters(replaced, route)),
+			routeViolations(route, routeParameters(params, route)))
+		if len(violations) > 0 {
+			impact.Routes = append(impact.Routes, dto.ParameterRouteImpact{Route: routeCode(route), Violations: violations})
+		}
+	}
+
+	flights, err := p.flightRepo.GetDepartingAfter(params.EffectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	var affected, tightened []*models.Flight
+	var flightIDs []uint
+	for _, flight := range flights {
+		before, after := flightParameters(replaced, flight), flightParameters(params, flight)
+		violations := newViolations(flightViolations(flight, before), flightViolations(flight, after))
+		if len(violations) > 0 {
+			affected = append(affected, flight)
+			impact.Flights = append(impact.Flights, dto.ParameterFlightImpact{
+				FlightCode:        flight.FlightCode,
+				DepartureDateTime: flight.DepartureDateTime.Format(time.RFC3339),
+				Violations:        violations,
+				TicketIDs:         []uint{},
+			})
+		}
+		deadlinesEarlier := after.LatestTicketPurchaseTime > before.LatestTicketPurchaseTime ||
+			after.TicketCancellationTime > before.TicketCancellationTime
+		if deadlinesEarlier {
+			tightened = append(tightened, flight)
+		}
+		if len(violations) > 0 || deadlinesEarlier {
+			flightIDs = append(flightIDs, flight.ID)
+		}
+	}
+	if len(flightIDs) == 0 {
+		return impact, nil
+	}
+
+	tickets, err := p.ticketRepo.GetActiveByFlightIDs(flightIDs)
+	if err != nil {
+		return nil, err
+	}
+	affectedTickets := make(map[uint]bool)
+	for i, flight := range affected {
+		for _, ticket := range tickets[flight.ID] {
+			impact.Flights[i].TicketIDs = append(impact.Flights[i].TicketIDs, ticket.ID)
+			affectedTickets[ticket.ID] = true
+		}
+	}
+	for _, flight := range tightened {
+		before, after := flightParameters(replaced, flight), flightParameters(params, flight)
+		for _, ticket := range tickets[flight.ID] {
+			violations := ticketViolations(ticket, flight, before, after, params.EffectiveFrom)
+			if len(violations) > 0 {
+				impact.Tickets = append(impact.Tickets, dto.ParameterTicketImpact{
+					TicketID:   ticket.ID,
+					FlightCode: flight.FlightCode,
+					Violations: violations,
+				})
+				affectedTickets[ticket.ID] = true
+			}
+		}
+	}
+	impact.AffectedFlights = len(affected)
+	impact.AffectedTickets = len(affectedTickets)
+	return impact, nil
+}
+
+func routeViolations(route *models.Route, params *models.Parameter) []ruleViolation {
+	var violations []ruleViolation
+	if route.DefaultDuration < params.MinFlightDuration {
+		violations = append(violations, ruleViolation{"min_flight_duration", fmt.Sprintf(
+			"default duration of %d minutes is below the minimum of %d", route.DefaultDuration, params.MinFlightDuration)})
+	}
+	if params.MinIntermediateStopDuration > params.MaxIntermediateStopDuration {
+		violations = append(violations, ruleViolation{"intermediate_stop_duration", fmt.Sprintf(
+			"minimum stop duration of %d minutes exceeds the maximum of %d",
+			params.MinIntermediateStopDuration, params.MaxIntermediateStopDuration)})
+	}
+	return violations
+}
+
+func flightViolations(flight *models.Flight, params *models.Parameter) []ruleViolation {
+	var violations []ruleViolation
+	if flight.FlightDuration < params.MinFlightDuration {
+		violations = append(violations, ruleViolation{"min_flight_duration", fmt.Sprintf(
+			"duration of %d minutes is below the minimum of %d", flight.FlightDuration, params.MinFlightDuration)})
+	}
+	if len(flight.IntermediateStops) > params.MaxIntermediateStops {
+		violations = append(violations, ruleViolation{"max_intermediate_stops", fmt.Sprintf(
+			"%d intermediate stops, more than the maximum of %d", len(flight.IntermediateStops), params.MaxIntermediateStops)})
+	}
+	for _, stop := range flight.IntermediateStops {
+		if stop.StopDuration < params.MinIntermediateStopDuration {
+			violations = append(violations, ruleViolation{"min_intermediate_stop_duration:" + stop.Airport.AirportCode, fmt.Sprintf(
+				"stop at %s lasts %d minutes, less than the minimum of %d",
+				stop.Airport.AirportCode, stop.StopDuration, params.MinIntermediateStopDuration)})
+		}
+		if stop.StopDuration > params.MaxIntermediateStopDuration {
+			violations = append(violations, ruleViolation{"max_intermediate_stop_duration:" + stop.Airport.AirportCode, fmt.Sprintf(
+				"stop at %s lasts %d minutes, more than the maximum of %d",
+				stop.Airport.AirportCode, stop.StopDuration, params.MaxIntermediateStopDuration)})
+		}
+	}
+	return violations
+}
+
+// ticketViolations lists the deadlines of a ticket on the flight that are still open under
+// before when the change takes effect, but already past under after.
+func ticketViolations(ticket *models.Ticket, flight *models.Flight, before, after *models.Parameter, effectiveFrom time.Time) []string {
+	deadline := func(days int) time.Time {
+		return flight.DepartureDateTime.Add(-time.Duration(days) * 24 * time.Hour)
+	}
+	closes := func(beforeDays, afterDays int) bool {
+		return deadline(beforeDays).After(effectiveFrom) && !deadline(afterDays).After(effectiveFrom)
+	}
+
+	var violations []string
+	if ticket.BookingType == models.BookingTypePlaceOrder &&
+		closes(before.LatestTicketPurchaseTime, after.LatestTicketPurchaseTime) {
+		violations = append(violations, fmt.Sprintf(
+			"unpaid place order can no longer be paid, its purchase deadline moves to %s",
+			deadline(after.LatestTicketPurchaseTime).Format(time.RFC3339)))
+	}
+	if closes(before.TicketCancellationTime, after.TicketCancellationTime) {
+		violations = append(violations, fmt.Sprintf(
+			"ticket can no longer be cancelled, its cancellation deadline moves to %s",
+			deadline(after.TicketCancellationTime).Format(time.RFC3339)))
+	}
+	return violations
+}
+
+// newViolations returns the messages of the violations in after that were not already in
+// before.
+func newViolations(before, after []ruleViolation) []string {
+	existing := make(map[string]bool, len(before))
+	for _, violation := range before {
+		existing[violation.rule] = true
+	}
+	var messages []string
+	for _, violation := range after {
+		if !existing[violation.rule] {
+			messages = append(messages, violation.message)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/repository"
+	"github.com/aprilboiz/flight-management/pkg/encryption"
+	"github.com/aprilboiz/flight-management/pkg/validator"
+	"gorm.io/gorm"
+)
+
+const maxPassengerSearchResults = 50
+
+type passengerService struct {
+	passengerRepo repository.PassengerRepository
+	ticketRepo    repository.TicketRepository
+}
+
+func NewPassengerService(passengerRepo repository.PassengerRepository, ticketRepo repository.TicketRepository) PassengerService {
+	if passengerRepo == nil || ticketRepo == nil {
+		panic("Missing required repositories for passenger service")
+	}
+	return &passengerService{
+		passengerRepo: passengerRepo,
+		ticketRepo:    ticketRepo,
+	}
+}
+
+func (p passengerService) Create(request *dto.PassengerRequest) (*dto.PassengerResponse, error) {
+	passenger := &models.Passenger{}
+	if err := applyPassengerRequest(passenger, request); err != nil {
+		return nil, err
+	}
+
+	documents, err := parseTravelDocuments(request.Documents)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse to create a duplicate profile for a document that is already known
+	for _, document := range documents {
+		existing, err := p.passengerRepo.GetByDocument(document.DocumentType, encryption.GetKeyring().BlindIndex(document.DocumentNumber))
+		if err == nil {
+			return nil, exceptions.NewAppError(exceptions.CONFLICT,
+				fmt.Sprintf("%s '%s' already belongs to passenger %d", document.DocumentType, document.DocumentNumber, existing.ID), nil)
+		}
+		if !isNotFound(err) {
+			return nil, err
+		}
+	}
+
+	err = p.passengerRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(passenger).Error; err != nil {
+			return exceptions.InternalError("failed to create passenger", err)
+		}
+		for _, document := range documents {
+			document.PassengerID = passenger.ID
+			if err := tx.Create(document).Error; err != nil {
+				return exceptions.InternalError("failed to create travel document", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetByID(passenger.ID)
+}
+
+func (p passengerService) Update(id uint, request *dto.PassengerRequest) (*dto.PassengerResponse, error) {
+	passenger, err := p.passengerRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPassengerRequest(passenger, request); err != nil {
+		return nil, err
+	}
+
+	documents, err := parseTravelDocuments(request.Documents)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.passengerRepo.Update(passenger); err != nil {
+		return nil, err
+	}
+	for _, document := range documents {
+		if _, err := p.saveDocument(passenger, document); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.GetByID(passenger.ID)
+}
+
+func (p passengerService) AddDocument(id uint, request *dto.TravelDocumentDTO) (*dto.PassengerResponse, error) {
+	passenger, err := p.passengerRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := parseTravelDocuments([]dto.TravelDocumentDTO{*request})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.saveDocument(passenger, documents[0]); err != nil {
+		return nil, err
+	}
+
+	return p.GetByID(passenger.ID)
+}
+
+func (p passengerService) GetByID(id uint) (*dto.PassengerResponse, error) {
+	passenger, err := p.passengerRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toPassengerResponse(passenger), nil
+}
+
+// Search finds passengers by their exact full name, email, phone number or travel document
+// number, ignoring case. These are stored encrypted, so partial values do not match.
+func (p passengerService) Search(query string) ([]*dto.PassengerResponse, error) {
+	query = strings.TrimSpace(query)
+	if len(query) < 2 {
+		return nil, exceptions.BadRequestError("search query must contain at least 2 characters", nil)
+	}
+
+	passengers, err := p.passengerRepo.Search(query, encryption.GetKeyring().BlindIndex(query), maxPassengerSearchResults)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.PassengerResponse, len(passengers))
+	for i, passenger := range passengers {
+		responses[i] = toPassengerResponse(passenger)
+	}
+	return responses, nil
+}
+
+func (p passengerService) GetBookingHistory(id uint) (*dto.PassengerHistoryResponse, error) {
+	passenger, err := p.passengerRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := p.ticketRepo.GetByPassengerID(passenger.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &dto.PassengerHistoryResponse{
+		Passenger: *toPassengerResponse(passenger),
+		Tickets:   make([]dto.TicketResponse, len(tickets)),
+	}
+	for i, ticket := range tickets {
+		history.Tickets[i] = dto.TicketResponse{
+			ID:           ticket.ID,
+			FlightCode:   ticket.Flight.FlightCode,
+			SeatNumber:   ticket.Seat.SeatNumber,
+			Price:        ticket.Price,
+			FullName:     ticket.FullName,
+			IDCard:       ticket.IDCard,
+			PhoneNumber:  ticket.PhoneNumber,
+			Email:        ticket.Email,
+			TicketStatus: ticket.TicketStatus,
+			BookingType:  ticket.BookingType,
+			PassengerID:  ticket.PassengerID,
+		}
+		if ticket.TicketStatus == models.TicketStatusActive || ticket.TicketStatus == models.TicketStatusUsed {
+			history.TotalSpent += ticket.Price
+		}
+	}
+	history.TotalTickets = len(tickets)
+
+	return history, nil
+}
+
+// saveDocument attaches a document to the passenger, updating it in place when the
+// passenger already holds a document with the same type and number.
+func (p passengerService) saveDocument(passenger *models.Passenger, document *models.TravelDocument) (*models.TravelDocument, error) {
+	owner, err := p.passengerRepo.GetByDocument(document.DocumentType, encryption.GetKeyring().BlindIndex(document.DocumentNumber))
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	if owner != nil && owner.ID != passenger.ID {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("%s '%s' already belongs to passenger %d", document.DocumentType, document.DocumentNumber, owner.ID), nil)
+	}
+
+	for _, existing := range passenger.Documents {
+		if existing.DocumentType == document.DocumentType && existing.DocumentNumber == document.DocumentNumber {
+			existing.IssuingCountry = document.IssuingCountry
+			existing.ExpiryDate = document.ExpiryDate
+			return p.passengerRepo.SaveDocument(&existing)
+		}
+	}
+
+	document.PassengerID = passenger.ID
+	return p.passengerRepo.SaveDocument(document)
+}
+
+func applyPassengerRequest(passenger *models.Passenger, request *dto.PassengerRequest) error {
+	passenger.FullName = request.FullName
+	passenger.Nationality = request.Nationality
+	passenger.PhoneNumber = request.PhoneNumber
+	passenger.Email = request.Email
+	passenger.DateOfBirth = nil
+
+	if request.DateOfBirth != "" {
+		dateOfBirth, err := time.Parse(time.DateOnly, request.DateOfBirth)
+		if err != nil {
+			return exceptions.BadRequestError("invalid date of birth format, expected YYYY-MM-DD", err)
+		}
+		if dateOfBirth.After(time.Now()) {
+			return exceptions.BadRequestError("date of birth cannot be in the future", nil)
+		}
+		passenger.DateOfBirth = &dateOfBirth
+	}
+	return nil
+}
+
+func parseTravelDocuments(requests []dto.TravelDocumentDTO) ([]*models.TravelDocument, error) {
+	documents := make([]*models.TravelDocument, 0, len(requests))
+	fieldErrors := validator.FieldErrors{}
+	seen := make(map[string]bool)
+	for i, request := range requests {
+		number := strings.ToUpper(strings.TrimSpace(request.DocumentNumber))
+		key := string(request.DocumentType) + ":" + number
+		if seen[key] {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("duplicate travel document: %s", number), nil)
+		}
+		seen[key] = true
+
+		document := &models.TravelDocument{
+			DocumentType:   request.DocumentType,
+			DocumentNumber: number,
+			IssuingCountry: request.IssuingCountry,
+		}
+		if document.DocumentType == models.DocumentTypeIDCard && document.IssuingCountry != "" {
+			if err := validator.ValidateNationalID(document.IssuingCountry, number); err != nil {
+				fieldErrors[fmt.Sprintf("documents[%d].document_number", i)] = err.Error()
+			}
+		}
+		if request.ExpiryDate != "" {
+			expiryDate, err := time.Parse(time.DateOnly, request.ExpiryDate)
+			if err != nil {
+				return nil, exceptions.BadRequestError("invalid document expiry date format, expected YYYY-MM-DD", err)
+			}
+			document.ExpiryDate = &expiryDate
+		}
+		documents = append(documents, document)
+	}
+	if len(fieldErrors) > 0 {
+		return nil, exceptions.NewAppError(exceptions.BadRequest, "Travel document validation failed", validator.ValidationErrors(fieldErrors))
+	}
+	return documents, nil
+}
+
+func toPassengerResponse(passenger *models.Passenger) *dto.PassengerResponse {
+	response := &dto.PassengerResponse{
+		ID:          passenger.ID,
+		FullName:    passenger.FullName,
+		Nationality: passenger.Nationality,
+		PhoneNumber: passenger.PhoneNumber,
+		Email:       passenger.Email,
+		Documents:   make([]dto.TravelDocumentDTO, len(passenger.Documents)),
+	}
+	if passenger.DateOfBirth != nil {
+		response.DateOfBirth = passenger.DateOfBirth.Format(time.DateOnly)
+	}
+	for i, document := range passenger.Documents {
+		response.Documents[i] = dto.TravelDocumentDTO{
+			DocumentType:   document.DocumentType,
+			DocumentNumber: document.DocumentNumber,
+			IssuingCountry: document.IssuingCountry,
+		}
+		if document.ExpiryDate != nil {
+			response.Documents[i].ExpiryDate = document.ExpiryDate.Format(time.DateOnly)
+		}
+	}
+	return response
+}
+
+func isNotFound(err error) bool {
+	var appErr *exceptions.AppError
+	return errors.As(err, &appErr) && appErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/pkg/auth"
+	"github.com/aprilboiz/flight-management/pkg/config"
+	"github.com/aprilboiz/flight-management/pkg/mail"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	ErrInvalidResetToken = errors.New("password reset token is invalid, expired or already used")
+)
+
+const (
+	defaultResetTokenTTL = 30 * time.Minute
+	// resetMailInterval is the least time between two reset mails to one user
+	resetMailInterval = time.Minute
+)
+
+// checkPasswordPolicy refuses a new password that does not meet the password policy, listing
+// what it lacks.
+func checkPasswordPolicy(password, username, email string) error {
+	localPart, _, _ := strings.Cut(email, "@")
+	violations := auth.GetPasswordPolicy().Check(password, username, localPart)
+	if len(violations) > 0 {
+		return exceptions.NewAppError(exceptions.BadRequest, "Password does not meet the password policy", violations)
+	}
+	return nil
+}
+
+// ChangePassword sets a new password for a user who knows their current one. Wrong current
+// passwords count as failed logins. The user's other sessions are revoked; the session that
+// changed the password stays open.
+func (s *userService) ChangePassword(userID, sessionID uint, req *dto.ChangePasswordRequest, ipAddress string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	userKey, ipKey := usernameThrottleKey(user.Username), ipThrottleKey(ipAddress)
+	if err := s.checkLoginThrottle(userKey, ipKey); err != nil {
+		return err
+	}
+	if err := user.CheckPassword(req.CurrentPassword); err != nil {
+		if err := s.recordLoginFailure(userKey, ipKey, user.Username, ipAddress); err != nil {
+			return err
+		}
+		return ErrIncorrectPassword
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return exceptions.BadRequestError("New password must differ from the current password", nil)
+	}
+	if err := checkPasswordPolicy(req.NewPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	user.Password = req.NewPassword
+	if err := user.HashPassword(); err != nil {
+		return exceptions.InternalError("failed to hash password", err)
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return exceptions.InternalError("failed to update password", err)
+	}
+	revoked, err := s.sessionRepo.RevokeOthersForUser(user.ID, sessionID)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(&models.AuditLog{
+		Action:    models.AuditPasswordChange,
+		Actor:     user.Username,
+		Subject:   user.Username,
+		IPAddress: ipAddress,
+		Details:   fmt.Sprintf("%d other sessions revoked", revoked),
+	})
+}
+
+// RequestPasswordReset mails a reset link if an enabled user has the email. It returns before
+// anything is looked up, so neither the response nor its timing tells whether the email is
+// registered.
+func (s *userService) RequestPasswordReset(req *dto.PasswordResetRequest) {
+	go func() {
+		if err := s.sendPasswordReset(req.Email); err != nil {
+			zap.L().Error("Failed to send password reset mail", zap.Error(err))
+		}
+	}()
+}
+
+// sendPasswordReset issues a reset token, replacing earlier ones, and mails it. At most one mail
+// goes to a user per resetMailInterval.
+func (s *userService) sendPasswordReset(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.DisabledAt != nil {
+		return nil
+	}
+	latest, err := s.passwordResetRepo.GetLatestForUser(user.ID)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < resetMailInterval {
+		return nil
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return exceptions.InternalError("failed to generate password reset token", err)
+	}
+	cfg := config.GetConfig().Security.Password
+	ttl := defaultResetTokenTTL
+	if cfg.ResetTokenMinutes > 0 {
+		ttl = time.Duration(cfg.ResetTokenMinutes) * time.Minute
+	}
+	if err := s.passwordResetRepo.Create(&models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return err
+	}
+
+	link := token
+	if cfg.ResetURL != "" {
+		separator := "?"
+		if strings.Contains(cfg.ResetURL, "?") {
+			separator = "&"
+		}
+		link = cfg.ResetURL + separator + "token=" + token
+	}
+	return s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Someone asked to reset the password of your account. To choose a new password, use\n\n"+
+			"%s\n\n"+
+			"within %d minutes. It works once. If you did not ask for this, ignore this message;\n"+
+			"your password has not changed.\n",
+			user.Username, link, int(ttl.Minutes())),
+	})
+}
+
+// ResetPassword sets a new password with the token of a reset link. Every session of the user
+// is revoked, and a lockout after failed logins is lifted.
+func (s *userService) ResetPassword(req *dto.PasswordResetConfirmRequest, ipAddress string) error {
+	token, err := s.passwordResetRepo.GetByTokenHash(hashToken(req.Token))
+	if err != nil {
+		if isNotFound(err) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) || token.User == nil {
+		return ErrInvalidResetToken
+	}
+	user := token.User
+	if user.DisabledAt != nil {
+		return ErrUserDisabled
+	}
+	if err := checkPasswordPolicy(req.NewPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	user.Password = req.NewPassword
+	if err := user.HashPassword(); err != nil {
+		return exceptions.InternalError("failed to hash password", err)
+	}
+	redeemed, err := s.passwordResetRepo.Redeem(token, user.Password)
+	if err != nil {
+		return err
+	}
+	if !redeemed {
+		return ErrInvalidResetToken
+	}
+	if _, err := s.loginThrottleRepo.Reset(usernameThrottleKey(user.Username)); err != nil {
+		return err
+	}
+	return s.auditRepo.Create(&models.AuditLog{
+		Action:    models.AuditPasswordReset,
+		Actor:     user.Username,
+		Subject:   user.Username,
+		IPAddress: ipAddress,
+	})
+}
@@ -1,19 +1,36 @@
 package service
 
 import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/internal/repository"
 )
 
-func NewPlaneService(planeRepo repository.PlaneRepository) PlaneService {
-	if planeRepo == nil {
+// maxSeatsPerPlane guards against layouts with runaway row ranges.
+const maxSeatsPerPlane = 1000
+
+func NewPlaneService(planeRepo repository.PlaneRepository, aircraftTypeRepo repository.AircraftTypeRepository, ticketClassRepo repository.TicketClassRepository) PlaneService {
+	if planeRepo == nil || aircraftTypeRepo == nil || ticketClassRepo == nil {
 		panic("Missing required repositories for plane service")
 	}
-	return &planeService{planeRepo: planeRepo}
+	return &planeService{
+		planeRepo:        planeRepo,
+		aircraftTypeRepo: aircraftTypeRepo,
+		ticketClassRepo:  ticketClassRepo,
+	}
 }
 
 type planeService struct {
-	planeRepo repository.PlaneRepository
+	planeRepo        repository.PlaneRepository
+	aircraftTypeRepo repository.AircraftTypeRepository
+	ticketClassRepo  repository.TicketClassRepository
 }
 
 func (p planeService) GetAllPlanes() ([]*dto.PlaneResponse, error) {
@@ -27,6 +44,12 @@ func (p planeService) GetAllPlanes() ([]*dto.PlaneResponse, error) {
 			PlaneCode: plane.PlaneCode,
 			PlaneName: plane.PlaneName,
 		}
+		if plane.AircraftType != nil {
+			planeResponses[i].AircraftType = plane.AircraftType.TypeCode
+		}
+		if plane.RetiredAt != nil {
+			planeResponses[i].RetiredAt = plane.RetiredAt.Format(time.RFC3339)
+		}
 	}
 	return planeResponses, nil
 }
@@ -38,15 +61,562 @@ func (p planeService) GetPlaneByCode(code string) (*dto.PlaneResponseDetails, er
 	}
 
 	planeResponse := &dto.PlaneResponseDetails{
-		PlaneCode: plane.PlaneCode,
-		PlaneName: plane.PlaneName,
-		Seats:     make([]dto.SeatResponse, 0),
+		PlaneCode:  plane.PlaneCode,
+		PlaneName:  plane.PlaneName,
+		SeatLayout: toSeatLayoutDTO(plane.SeatLayout),
+		Seats:      make([]dto.SeatResponse, 0),
+	}
+	if plane.AircraftType != nil {
+		planeResponse.AircraftType = toAircraftTypeResponse(plane.AircraftType)
+	}
+	if plane.RetiredAt != nil {
+		planeResponse.RetiredAt = plane.RetiredAt.Format(time.RFC3339)
 	}
 	for _, seat := range plane.Seats {
 		planeResponse.Seats = append(planeResponse.Seats, dto.SeatResponse{
-			SeatNumber:  seat.SeatNumber,
-			TicketClass: seat.TicketClass.TicketClassName,
+			SeatNumber:     seat.SeatNumber,
+			TicketClass:    seat.TicketClass.TicketClassName,
+			SeatAttributes: toSeatAttributes(&seat),
 		})
 	}
 	return planeResponse, nil
 }
+
+func (p planeService) CreatePlane(request *dto.PlaneRequest) (*dto.PlaneResponseDetails, error) {
+	planeCode := strings.ToUpper(strings.TrimSpace(request.PlaneCode))
+	if err := p.ensureCodeAvailable(planeCode, 0); err != nil {
+		return nil, err
+	}
+
+	plane := &models.Plane{
+		PlaneCode: planeCode,
+		PlaneName: strings.TrimSpace(request.PlaneName),
+	}
+	seatLayout := request.SeatLayout
+	if request.AircraftType != "" {
+		aircraftType, err := p.getAircraftType(request.AircraftType)
+		if err != nil {
+			return nil, err
+		}
+		plane.AircraftTypeID = &aircraftType.ID
+		plane.AircraftType = aircraftType
+
+		// Without a layout of its own, the plane takes the seats of its type's template
+		if seatLayout == nil {
+			seatLayout = toSeatLayoutDTO(aircraftType.SeatLayout)
+		}
+	}
+
+	// The layout is checked before anything is stored; the plane is then saved with its seats
+	if seatLayout != nil {
+		if err := p.applySeatLayout(plane, seatLayout); err != nil {
+			return nil, err
+		}
+	} else if _, err := p.planeRepo.Create(plane); err != nil {
+		return nil, err
+	}
+	return p.GetPlaneByCode(plane.PlaneCode)
+}
+
+func (p planeService) UpdatePlane(code string, request *dto.PlaneRequest) (*dto.PlaneResponseDetails, error) {
+	plane, err := p.planeRepo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	planeCode := strings.ToUpper(strings.TrimSpace(request.PlaneCode))
+	if err := p.ensureCodeAvailable(planeCode, plane.ID); err != nil {
+		return nil, err
+	}
+	plane.PlaneCode = planeCode
+	plane.PlaneName = strings.TrimSpace(request.PlaneName)
+	if request.AircraftType != "" {
+		aircraftType, err := p.getAircraftType(request.AircraftType)
+		if err != nil {
+			return nil, err
+		}
+		plane.AircraftTypeID = &aircraftType.ID
+		plane.AircraftType = aircraftType
+	}
+
+	if request.SeatLayout != nil {
+		if err := p.applySeatLayout(plane, request.SeatLayout); err != nil {
+			return nil, err
+		}
+	} else if _, err := p.planeRepo.Update(plane); err != nil {
+		return nil, err
+	}
+	return p.GetPlaneByCode(plane.PlaneCode)
+}
+
+func (p planeService) BuildSeatMap(code string, layout *dto.SeatLayoutDTO) (*dto.PlaneResponseDetails, error) {
+	plane, err := p.planeRepo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.applySeatLayout(plane, layout); err != nil {
+		return nil, err
+	}
+	return p.GetPlaneByCode(plane.PlaneCode)
+}
+
+// RetirePlane takes a plane out of service. Planes still scheduled on upcoming flights cannot
+// be retired; the conflict lists those flights so they can be moved to another plane first.
+func (p planeService) RetirePlane(code string) (*dto.PlaneResponseDetails, error) {
+	plane, err := p.planeRepo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if plane.RetiredAt != nil {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT, fmt.Sprintf("plane '%s' is already retired", plane.PlaneCode), nil)
+	}
+
+	now := time.Now()
+	flightCodes, err := p.planeRepo.GetUpcomingFlightCodes(plane.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(flightCodes) > 0 {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("plane '%s' is scheduled on upcoming flights %s", plane.PlaneCode, strings.Join(flightCodes, ", ")), nil)
+	}
+
+	plane.RetiredAt = &now
+	if _, err := p.planeRepo.Update(plane); err != nil {
+		return nil, err
+	}
+	return p.GetPlaneByCode(plane.PlaneCode)
+}
+
+func (p planeService) GetAllAircraftTypes() ([]*dto.AircraftTypeResponse, error) {
+	aircraftTypes, err := p.aircraftTypeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.AircraftTypeResponse, len(aircraftTypes))
+	for i, aircraftType := range aircraftTypes {
+		responses[i] = toAircraftTypeResponse(aircraftType)
+	}
+	return responses, nil
+}
+
+func (p planeService) GetAircraftType(code string) (*dto.AircraftTypeResponse, error) {
+	aircraftType, err := p.getAircraftType(code)
+	if err != nil {
+		return nil, err
+	}
+	return toAircraftTypeResponse(aircraftType), nil
+}
+
+func (p planeService) CreateAircraftType(request *dto.AircraftTypeRequest) (*dto.AircraftTypeResponse, error) {
+	typeCode := strings.ToUpper(strings.TrimSpace(request.TypeCode))
+	if _, err := p.aircraftTypeRepo.GetByCode(typeCode); err == nil {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT, fmt.Sprintf("aircraft type '%s' already exists", typeCode), nil)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	aircraftType := &models.AircraftType{TypeCode: typeCode}
+	if err := p.applyAircraftTypeRequest(aircraftType, request); err != nil {
+		return nil, err
+	}
+	if _, err := p.aircraftTypeRepo.Create(aircraftType); err != nil {
+		return nil, err
+	}
+	return toAircraftTypeResponse(aircraftType), nil
+}
+
+// UpdateAircraftType changes the type's data. Planes already built keep their seats when
+// the seat template changes.
+func (p planeService) UpdateAircraftType(code string, request *dto.AircraftTypeRequest) (*dto.AircraftTypeResponse, error) {
+	aircraftType, err := p.getAircraftType(code)
+	if err != nil {
+		return nil, err
+	}
+
+	typeCode := strings.ToUpper(strings.TrimSpace(request.TypeCode))
+	if typeCode != aircraftType.TypeCode {
+		if _, err := p.aircraftTypeRepo.GetByCode(typeCode); err == nil {
+			return nil, exceptions.NewAppError(exceptions.CONFLICT, fmt.Sprintf("aircraft type '%s' already exists", typeCode), nil)
+		} else if !isNotFound(err) {
+			return nil, err
+		}
+	}
+	aircraftType.TypeCode = typeCode
+	if err := p.applyAircraftTypeRequest(aircraftType, request); err != nil {
+		return nil, err
+	}
+	if _, err := p.aircraftTypeRepo.Update(aircraftType); err != nil {
+		return nil, err
+	}
+	return toAircraftTypeResponse(aircraftType), nil
+}
+
+func (p planeService) DeleteAircraftType(code string) error {
+	aircraftType, err := p.getAircraftType(code)
+	if err != nil {
+		return err
+	}
+	planes, err := p.aircraftTypeRepo.CountPlanes(aircraftType.ID)
+	if err != nil {
+		return err
+	}
+	if planes > 0 {
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("aircraft type '%s' is used by %d planes", aircraftType.TypeCode, planes), nil)
+	}
+	routes, err := p.aircraftTypeRepo.CountRoutes(aircraftType.ID)
+	if err != nil {
+		return err
+	}
+	if routes > 0 {
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("aircraft type '%s' is allowed on %d routes", aircraftType.TypeCode, routes), nil)
+	}
+	return p.aircraftTypeRepo.Delete(aircraftType)
+}
+
+func (p planeService) getAircraftType(code string) (*models.AircraftType, error) {
+	return p.aircraftTypeRepo.GetByCode(strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// applyAircraftTypeRequest copies the request onto the type, validating its seat template.
+func (p planeService) applyAircraftTypeRequest(aircraftType *models.AircraftType, request *dto.AircraftTypeRequest) error {
+	aircraftType.Manufacturer = strings.TrimSpace(request.Manufacturer)
+	aircraftType.ModelName = strings.TrimSpace(request.Model)
+	aircraftType.RangeKm = request.RangeKm
+	aircraftType.CruiseSpeedKmh = request.CruiseSpeedKmh
+	aircraftType.SeatLayout = nil
+	if request.SeatLayout != nil {
+		layout, err := p.parseSeatLayout(request.SeatLayout)
+		if err != nil {
+			return err
+		}
+		// Generating the seats once catches templates that could never be applied
+		if _, err := p.generateSeats(layout); err != nil {
+			return err
+		}
+		aircraftType.SeatLayout = layout
+	}
+	return nil
+}
+
+func (p planeService) ensureCodeAvailable(planeCode string, planeID uint) error {
+	existing, err := p.planeRepo.GetByCode(planeCode)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != planeID {
+		return exceptions.NewAppError(exceptions.CONFLICT, fmt.Sprintf("plane code '%s' is already in use", planeCode), nil)
+	}
+	return nil
+}
+
+// applySeatLayout regenerates the plane's seats from the layout. Seats keeping their number
+// are updated in place so existing tickets stay attached; the change is refused when a seat
+// holding an active ticket on an upcoming flight would be removed, blocked or moved to
+// another class.
+func (p planeService) applySeatLayout(plane *models.Plane, request *dto.SeatLayoutDTO) error {
+	layout, err := p.parseSeatLayout(request)
+	if err != nil {
+		return err
+	}
+	generated, err := p.generateSeats(layout)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]*models.Seat, len(plane.Seats))
+	for i := range plane.Seats {
+		existing[plane.Seats[i].SeatNumber] = &plane.Seats[i]
+	}
+
+	seats := make([]*models.Seat, 0, len(generated))
+	affected := make(map[uint]string)
+	for _, seat := range generated {
+		current, ok := existing[seat.SeatNumber]
+		if !ok {
+			seats = append(seats, seat)
+			continue
+		}
+		delete(existing, seat.SeatNumber)
+		if current.TicketClassID != seat.TicketClassID || (seat.IsBlocked && !current.IsBlocked) {
+			affected[current.ID] = current.SeatNumber
+		}
+		current.TicketClassID = seat.TicketClassID
+		current.RowNumber = seat.RowNumber
+		current.ColumnLetter = seat.ColumnLetter
+		current.IsBlocked = seat.IsBlocked
+		current.Position = seat.Position
+		current.IsExitRow = seat.IsExitRow
+		current.ExtraLegroom = seat.ExtraLegroom
+		current.HasBassinet = seat.HasBassinet
+		current.Surcharge = seat.Surcharge
+		seats = append(seats, current)
+	}
+
+	removedSeatIDs := make([]uint, 0, len(existing))
+	for _, seat := range existing {
+		removedSeatIDs = append(removedSeatIDs, seat.ID)
+		affected[seat.ID] = seat.SeatNumber
+	}
+
+	affectedIDs := make([]uint, 0, len(affected))
+	for id := range affected {
+		affectedIDs = append(affectedIDs, id)
+	}
+	plane.SeatLayout = layout
+	return p.planeRepo.SaveSeatMap(plane, seats, removedSeatIDs, affectedIDs, func(activeSeats map[uint]bool) error {
+		if len(activeSeats) == 0 {
+			return nil
+		}
+		seatNumbers := make([]string, 0, len(activeSeats))
+		for id := range activeSeats {
+			seatNumbers = append(seatNumbers, affected[id])
+		}
+		sort.Strings(seatNumbers)
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("seat map change would orphan active tickets on seats %s", strings.Join(seatNumbers, ", ")), nil)
+	})
+}
+
+// parseSeatLayout normalizes the requested layout and checks it for consistency.
+func (p planeService) parseSeatLayout(request *dto.SeatLayoutDTO) (*models.SeatLayout, error) {
+	layout := &models.SeatLayout{
+		Columns:          normalizeLetters(request.Columns),
+		AislesAfter:      normalizeLetters(request.AislesAfter),
+		Cabins:           make([]models.CabinLayout, len(request.Cabins)),
+		BlockedSeats:     normalizeSeatNumbers(request.BlockedSeats),
+		ExitRows:         request.ExitRows,
+		ExtraLegroomRows: request.ExtraLegroomRows,
+		BassinetSeats:    normalizeSeatNumbers(request.BassinetSeats),
+		Surcharges:       request.Surcharges,
+	}
+
+	if hasDuplicates(layout.Columns) {
+		return nil, exceptions.BadRequestError("seat layout columns must be unique", nil)
+	}
+	for _, aisle := range layout.AislesAfter {
+		position := slices.Index(layout.Columns, aisle)
+		if position < 0 {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("aisle column '%s' is not part of the layout", aisle), nil)
+		}
+		if position == len(layout.Columns)-1 {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("aisle cannot follow the last column '%s'", aisle), nil)
+		}
+	}
+
+	for i, cabin := range request.Cabins {
+		layout.Cabins[i] = models.CabinLayout{
+			TicketClass: strings.TrimSpace(cabin.TicketClass),
+			FromRow:     cabin.FromRow,
+			ToRow:       cabin.ToRow,
+			Columns:     normalizeLetters(cabin.Columns),
+		}
+		if hasDuplicates(layout.Cabins[i].Columns) {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("cabin rows %d-%d repeat a column", cabin.FromRow, cabin.ToRow), nil)
+		}
+		for _, column := range layout.Cabins[i].Columns {
+			if !slices.Contains(layout.Columns, column) {
+				return nil, exceptions.BadRequestError(fmt.Sprintf("cabin column '%s' is not part of the layout", column), nil)
+			}
+		}
+	}
+	sort.Slice(layout.Cabins, func(i, j int) bool {
+		return layout.Cabins[i].FromRow < layout.Cabins[j].FromRow
+	})
+	for i := 1; i < len(layout.Cabins); i++ {
+		if layout.Cabins[i].FromRow <= layout.Cabins[i-1].ToRow {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("cabin rows %d-%d overlap rows %d-%d",
+				layout.Cabins[i].FromRow, layout.Cabins[i].ToRow, layout.Cabins[i-1].FromRow, layout.Cabins[i-1].ToRow), nil)
+		}
+	}
+	return layout, nil
+}
+
+// generateSeats lays out one seat per row and column of every cabin, in row order and
+// from left to right within a row.
+func (p planeService) generateSeats(layout *models.SeatLayout) ([]*models.Seat, error) {
+	classNames := make([]string, 0, len(layout.Cabins))
+	for _, cabin := range layout.Cabins {
+		classNames = append(classNames, cabin.TicketClass)
+	}
+	ticketClasses, err := p.ticketClassRepo.GetByNames(classNames)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make([]*models.Seat, 0)
+	seatsByNumber := make(map[string]*models.Seat)
+	for _, cabin := range layout.Cabins {
+		ticketClass, ok := ticketClasses[cabin.TicketClass]
+		if !ok {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("unknown ticket class '%s'", cabin.TicketClass), nil)
+		}
+		columns := cabin.Columns
+		if len(columns) == 0 {
+			columns = layout.Columns
+		}
+		if len(seats)+(cabin.ToRow-cabin.FromRow+1)*len(columns) > maxSeatsPerPlane {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("seat layout cannot exceed %d seats", maxSeatsPerPlane), nil)
+		}
+
+		for row := cabin.FromRow; row <= cabin.ToRow; row++ {
+			for _, column := range layout.Columns {
+				if !slices.Contains(columns, column) {
+					continue
+				}
+				seat := &models.Seat{
+					SeatNumber:    fmt.Sprintf("%d%s", row, column),
+					TicketClassID: ticketClass.ID,
+					RowNumber:     row,
+					ColumnLetter:  column,
+					Position:      seatPosition(layout, columns, column),
+					IsExitRow:     slices.Contains(layout.ExitRows, row),
+					ExtraLegroom:  slices.Contains(layout.ExtraLegroomRows, row),
+				}
+				seats = append(seats, seat)
+				seatsByNumber[seat.SeatNumber] = seat
+			}
+		}
+	}
+
+	for _, seatNumber := range layout.BlockedSeats {
+		seat, ok := seatsByNumber[seatNumber]
+		if !ok {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("blocked seat '%s' is not part of the layout", seatNumber), nil)
+		}
+		seat.IsBlocked = true
+	}
+	for _, seatNumber := range layout.BassinetSeats {
+		seat, ok := seatsByNumber[seatNumber]
+		if !ok {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("bassinet seat '%s' is not part of the layout", seatNumber), nil)
+		}
+		seat.HasBassinet = true
+	}
+	for _, seat := range seats {
+		seat.Surcharge = seatSurcharge(layout.Surcharges, seat)
+	}
+	return seats, nil
+}
+
+// seatPosition places a column within its cabin: the outermost columns are windows and
+// columns next to an aisle are aisle seats.
+func seatPosition(layout *models.SeatLayout, cabinColumns []string, column string) models.SeatPosition {
+	index := slices.Index(cabinColumns, column)
+	if index == 0 || index == len(cabinColumns)-1 {
+		return models.SeatPositionWindow
+	}
+
+	// An aisle between this column and its neighbour in the cabin, in either direction
+	position := slices.Index(layout.Columns, column)
+	previous := slices.Index(layout.Columns, cabinColumns[index-1])
+	next := slices.Index(layout.Columns, cabinColumns[index+1])
+	for _, aisle := range layout.AislesAfter {
+		aislePosition := slices.Index(layout.Columns, aisle)
+		if (aislePosition >= previous && aislePosition < position) || (aislePosition >= position && aislePosition < next) {
+			return models.SeatPositionAisle
+		}
+	}
+	return models.SeatPositionMiddle
+}
+
+func seatSurcharge(surcharges map[models.SeatAttribute]float64, seat *models.Seat) float64 {
+	surcharge := 0.0
+	switch seat.Position {
+	case models.SeatPositionWindow:
+		surcharge += surcharges[models.SeatAttributeWindow]
+	case models.SeatPositionAisle:
+		surcharge += surcharges[models.SeatAttributeAisle]
+	}
+	if seat.IsExitRow {
+		surcharge += surcharges[models.SeatAttributeExitRow]
+	}
+	if seat.ExtraLegroom {
+		surcharge += surcharges[models.SeatAttributeExtraLegroom]
+	}
+	if seat.HasBassinet {
+		surcharge += surcharges[models.SeatAttributeBassinet]
+	}
+	return surcharge
+}
+
+func toAircraftTypeResponse(aircraftType *models.AircraftType) *dto.AircraftTypeResponse {
+	return &dto.AircraftTypeResponse{
+		TypeCode:       aircraftType.TypeCode,
+		Manufacturer:   aircraftType.Manufacturer,
+		Model:          aircraftType.ModelName,
+		RangeKm:        aircraftType.RangeKm,
+		CruiseSpeedKmh: aircraftType.CruiseSpeedKmh,
+		SeatLayout:     toSeatLayoutDTO(aircraftType.SeatLayout),
+	}
+}
+
+func toSeatAttributes(seat *models.Seat) dto.SeatAttributes {
+	return dto.SeatAttributes{
+		Row:          seat.RowNumber,
+		Column:       seat.ColumnLetter,
+		Position:     seat.Position,
+		IsExitRow:    seat.IsExitRow,
+		ExtraLegroom: seat.ExtraLegroom,
+		HasBassinet:  seat.HasBassinet,
+		IsBlocked:    seat.IsBlocked,
+		Surcharge:    seat.Surcharge,
+	}
+}
+
+func toSeatLayoutDTO(layout *models.SeatLayout) *dto.SeatLayoutDTO {
+	if layout == nil {
+		return nil
+	}
+	response := &dto.SeatLayoutDTO{
+		Columns:          layout.Columns,
+		AislesAfter:      layout.AislesAfter,
+		Cabins:           make([]dto.CabinLayoutDTO, len(layout.Cabins)),
+		BlockedSeats:     layout.BlockedSeats,
+		ExitRows:         layout.ExitRows,
+		ExtraLegroomRows: layout.ExtraLegroomRows,
+		BassinetSeats:    layout.BassinetSeats,
+		Surcharges:       layout.Surcharges,
+	}
+	for i, cabin := range layout.Cabins {
+		response.Cabins[i] = dto.CabinLayoutDTO{
+			TicketClass: cabin.TicketClass,
+			FromRow:     cabin.FromRow,
+			ToRow:       cabin.ToRow,
+			Columns:     cabin.Columns,
+		}
+	}
+	return response
+}
+
+func normalizeLetters(letters []string) []string {
+	normalized := make([]string, len(letters))
+	for i, letter := range letters {
+		normalized[i] = strings.ToUpper(strings.TrimSpace(letter))
+	}
+	return normalized
+}
+
+func normalizeSeatNumbers(seatNumbers []string) []string {
+	normalized := make([]string, len(seatNumbers))
+	for i, seatNumber := range seatNumbers {
+		normalized[i] = strings.ToUpper(strings.TrimSpace(seatNumber))
+	}
+	return normalized
+}
+
+func hasDuplicates(values []string) bool {
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if seen[value] {
+			return true
+		}
+		seen[value] = true
+	}
+	return false
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/repository"
+	"github.com/aprilboiz/flight-management/pkg/config"
+	"github.com/aprilboiz/flight-management/pkg/encryption"
+)
+
+type privacyService struct {
+	erasureRepo   repository.ErasureRequestRepository
+	ticketRepo    repository.TicketRepository
+	passengerRepo repository.PassengerRepository
+
+	loyaltyService LoyaltyService
+}
+
+func NewPrivacyService(erasureRepo repository.ErasureRequestRepository, ticketRepo repository.TicketRepository,
+	passengerRepo repository.PassengerRepository, loyaltyService LoyaltyService) PrivacyService {
+	if erasureRepo == nil || ticketRepo == nil || passengerRepo == nil || loyaltyService == nil {
+		panic("Missing required repositories for privacy service")
+	}
+	return &privacyService{
+		erasureRepo:    erasureRepo,
+		ticketRepo:     ticketRepo,
+		passengerRepo:  passengerRepo,
+		loyaltyService: loyaltyService,
+	}
+}
+
+func (p privacyService) ExportPassengerData(email, idCard string) (*dto.PassengerDataExport, error) {
+	email, idCard = strings.TrimSpace(email), strings.TrimSpace(idCard)
+	if email == "" && idCard == "" {
+		return nil, exceptions.BadRequestError("an email or ID card number is required", nil)
+	}
+	keyring := encryption.GetKeyring()
+
+	tickets, err := p.ticketRepo.GetBySubject(keyring.BlindIndex(email), keyring.BlindIndex(idCard))
+	if err != nil {
+		return nil, err
+	}
+	passengers, err := p.findPassengers(email, idCard, tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &dto.PassengerDataExport{
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Tickets:     make([]dto.TicketExport, len(tickets)),
+		Passengers:  make([]dto.PassengerExport, 0, len(passengers)),
+	}
+	for i, ticket := range tickets {
+		export.Tickets[i] = dto.TicketExport{
+			TicketResponse: dto.TicketResponse{
+				ID:           ticket.ID,
+				FlightCode:   ticket.Flight.FlightCode,
+				SeatNumber:   ticket.Seat.SeatNumber,
+				Price:        ticket.Price,
+				FullName:     ticket.FullName,
+				IDCard:       ticket.IDCard,
+				PhoneNumber:  ticket.PhoneNumber,
+				Email:        ticket.Email,
+				TicketStatus: ticket.TicketStatus,
+				BookingType:  ticket.BookingType,
+				PassengerID:  ticket.PassengerID,
+			},
+			TicketClass:       ticket.Seat.TicketClass.TicketClassName,
+			DepartureAirport:  ticket.Flight.DepartureAirport.AirportCode,
+			ArrivalAirport:    ticket.Flight.ArrivalAirport.AirportCode,
+			DepartureDateTime: ticket.Flight.DepartureDateTime.Format(time.RFC3339),
+		}
+		if ticket.AnonymizedAt != nil {
+			export.Tickets[i].AnonymizedAt = ticket.AnonymizedAt.Format(time.RFC3339)
+		}
+	}
+
+	for _, passenger := range passengers {
+		passengerExport := dto.PassengerExport{PassengerResponse: *toPassengerResponse(passenger)}
+		account, err := p.loyaltyService.GetAccountByPassengerID(passenger.ID)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		if account != nil {
+			transactions, err := p.loyaltyService.GetTransactions(account.MemberNumber)
+			if err != nil {
+				return nil, err
+			}
+			passengerExport.LoyaltyAccount = account
+			passengerExport.LoyaltyTransactions = transactions
+		}
+		export.Passengers = append(export.Passengers, passengerExport)
+	}
+
+	return export, nil
+}
+
+func (p privacyService) RequestErasure(request *dto.ErasureRequestRequest, requestedBy string) (*dto.ErasureRequestResponse, error) {
+	email, idCard := strings.TrimSpace(request.Email), strings.TrimSpace(request.IDCard)
+	keyring := encryption.GetKeyring()
+	erasure := &models.ErasureRequest{
+		EmailIndex:  keyring.BlindIndex(email),
+		IDCardIndex: keyring.BlindIndex(idCard),
+		Status:      models.ErasureRequestPending,
+		RequestedBy: requestedBy,
+	}
+
+	// Profiles are not needed for bookkeeping, so they are anonymized straight away.
+	// Matching is done before the tickets lose their passenger link.
+	tickets, err := p.ticketRepo.GetBySubject(erasure.EmailIndex, erasure.IDCardIndex)
+	if err != nil {
+		return nil, err
+	}
+	passengers, err := p.findPassengers(email, idCard, tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.erasureRepo.Create(erasure); err != nil {
+		return nil, err
+	}
+	for _, passenger := range passengers {
+		if err := p.passengerRepo.Anonymize(passenger.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	pending, err := p.processErasureRequest(erasure)
+	if err != nil {
+		return nil, err
+	}
+	response := toErasureRequestResponse(erasure, pending)
+	response.PassengersAnonymized = len(passengers)
+	return response, nil
+}
+
+func (p privacyService) GetErasureRequests() ([]*dto.ErasureRequestResponse, error) {
+	requests, err := p.erasureRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ErasureRequestResponse, len(requests))
+	for i, request := range requests {
+		var pending int64
+		if request.Status == models.ErasureRequestPending {
+			pending, err = p.ticketRepo.CountUnanonymizedBySubject(request.EmailIndex, request.IDCardIndex)
+			if err != nil {
+				return nil, err
+			}
+		}
+		responses[i] = toErasureRequestResponse(request, pending)
+	}
+	return responses, nil
+}
+
+// ApplyRetentionPolicy anonymizes tickets and passenger profiles that outlived the retention
+// period and advances pending erasure requests whose tickets have left the hold period. It
+// returns the number of tickets and of passenger profiles anonymized.
+func (p privacyService) ApplyRetentionPolicy() (int64, int64, error) {
+	var anonymized int64
+
+	pending, err := p.erasureRepo.GetPending()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, request := range pending {
+		before := request.TicketsAnonymized
+		if _, err := p.processErasureRequest(request); err != nil {
+			return anonymized, 0, err
+		}
+		anonymized += int64(request.TicketsAnonymized - before)
+	}
+
+	retentionDays := config.GetConfig().Security.PII.RetentionDays
+	if retentionDays <= 0 {
+		return anonymized, 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	count, err := p.ticketRepo.AnonymizeDepartedBefore(cutoff)
+	if err != nil {
+		return anonymized, 0, err
+	}
+	anonymized += count
+
+	// A profile goes once its last ticket has left the retention period
+	passengerIDs, err := p.passengerRepo.GetInactiveIDs(cutoff)
+	if err != nil {
+		return anonymized, 0, err
+	}
+	var passengers int64
+	for _, id := range passengerIDs {
+		if err := p.passengerRepo.Anonymize(id); err != nil {
+			return anonymized, passengers, err
+		}
+		passengers++
+	}
+	return anonymized, passengers, nil
+}
+
+// processErasureRequest anonymizes the subject's tickets that are past the hold period and
+// completes the request once none are left. It returns the number of tickets still pending.
+func (p privacyService) processErasureRequest(request *models.ErasureRequest) (int64, error) {
+	holdDays := config.GetConfig().Security.PII.ErasureHoldDays
+	count, err := p.ticketRepo.AnonymizeBySubject(request.EmailIndex, request.IDCardIndex, time.Now().AddDate(0, 0, -holdDays))
+	if err != nil {
+		return 0, err
+	}
+	request.TicketsAnonymized += int(count)
+
+	pending, err := p.ticketRepo.CountUnanonymizedBySubject(request.EmailIndex, request.IDCardIndex)
+	if err != nil {
+		return 0, err
+	}
+	if pending == 0 {
+		now := time.Now()
+		request.Status = models.ErasureRequestCompleted
+		request.CompletedAt = &now
+	}
+
+	if _, err := p.erasureRepo.Update(request); err != nil {
+		return 0, err
+	}
+	return pending, nil
+}
+
+// findPassengers returns the profiles matching the subject directly or through their tickets.
+func (p privacyService) findPassengers(email, idCard string, tickets []*models.Ticket) ([]*models.Passenger, error) {
+	keyring := encryption.GetKeyring()
+	passengers, err := p.passengerRepo.FindBySubject(keyring.BlindIndex(email), keyring.BlindIndex(idCard))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(passengers))
+	for _, passenger := range passengers {
+		seen[passenger.ID] = true
+	}
+	for _, ticket := range tickets {
+		if ticket.PassengerID == nil || seen[*ticket.PassengerID] {
+			continue
+		}
+		passenger, err := p.passengerRepo.GetByID(*ticket.PassengerID)
+		if err != nil {
+			return nil, err
+		}
+		seen[passenger.ID] = true
+		passengers = append(passengers, passenger)
+	}
+	return passengers, nil
+}
+
+func toErasureRequestResponse(request *models.ErasureRequest, pending int64) *dto.ErasureRequestResponse {
+	response := &dto.ErasureRequestResponse{
+		ID:                request.ID,
+		Status:            request.Status,
+		RequestedBy:       request.RequestedBy,
+		TicketsAnonymized: request.TicketsAnonymized,
+		PendingTickets:    pending,
+		CreatedAt:         request.CreatedAt.Format(time.RFC3339),
+	}
+	if request.CompletedAt != nil {
+		response.CompletedAt = request.CompletedAt.Format(time.RFC3339)
+	}
+	return response
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/repository"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+func NewRoleService(roleRepo repository.RoleRepository) RoleService {
+	if roleRepo == nil {
+		panic("Missing required repositories for role service")
+	}
+	return &roleService{roleRepo: roleRepo}
+}
+
+type roleService struct {
+	roleRepo repository.RoleRepository
+}
+
+func (r roleService) GetAllPermissions() []models.Permission {
+	return slices.Clone(models.AllPermissions)
+}
+
+func (r roleService) GetAllRoles() ([]*dto.RoleResponse, error) {
+	roles, err := r.roleRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = toRoleResponse(role)
+	}
+	return responses, nil
+}
+
+func (r roleService) GetRole(name models.Role) (*dto.RoleResponse, error) {
+	role, err := r.roleRepo.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return toRoleResponse(role), nil
+}
+
+// CreateRole adds a role. Only permissions the caller holds can be granted, unless the caller
+// is a super admin.
+func (r roleService) CreateRole(request *dto.CreateRoleRequest, callerRole models.Role) (*dto.RoleResponse, error) {
+	name := models.Role(strings.ToUpper(strings.TrimSpace(string(request.Name))))
+	if !roleNamePattern.MatchString(string(name)) {
+		return nil, exceptions.BadRequestError(
+			"role names may only contain upper case letters, digits and underscores, starting with a letter", nil)
+	}
+	permissions, err := rolePermissionRows(name, request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireHeldPermissions(r.roleRepo, callerRole, request.Permissions); err != nil {
+		return nil, err
+	}
+	role := &models.RoleDefinition{
+		Name:        name,
+		Description: strings.TrimSpace(request.Description),
+		Permissions: permissions,
+	}
+	if _, err := r.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+	return toRoleResponse(role), nil
+}
+
+// UpdateRole replaces the permissions of a role. Users of the role are held to the new
+// permissions on their next request. The super admin role always has every permission. Only
+// permissions the caller holds can be added, unless the caller is a super admin.
+func (r roleService) UpdateRole(name models.Role, request *dto.RoleRequest, callerRole models.Role) (*dto.RoleResponse, error) {
+	role, err := r.roleRepo.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == models.RoleSuperAdmin {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT, "the permissions of the super admin role cannot be changed", nil)
+	}
+	permissions, err := rolePermissionRows(role.Name, request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	var added []models.Permission
+	for _, permission := range request.Permissions {
+		if !slices.ContainsFunc(role.Permissions, func(row models.RolePermission) bool {
+			return row.Permission == permission
+		}) {
+			added = append(added, permission)
+		}
+	}
+	if err := requireHeldPermissions(r.roleRepo, callerRole, added); err != nil {
+		return nil, err
+	}
+	role.Description = strings.TrimSpace(request.Description)
+	role.Permissions = permissions
+	if _, err := r.roleRepo.Update(role); err != nil {
+		return nil, err
+	}
+	return toRoleResponse(role), nil
+}
+
+// DeleteRole removes a role that is not built in and that no user has.
+func (r roleService) DeleteRole(name models.Role) error {
+	role, err := r.roleRepo.GetByName(name)
+	if err != nil {
+		return err
+	}
+	if role.BuiltIn {
+		return exceptions.NewAppError(exceptions.CONFLICT, fmt.Sprintf("role '%s' is built in", role.Name), nil)
+	}
+	return r.roleRepo.Delete(role)
+}
+
+// RolePermissions returns the permissions a role grants.
+func (r roleService) RolePermissions(role models.Role) ([]models.Permission, error) {
+	return rolePermissions(r.roleRepo, role)
+}
+
+// rolePermissions returns the permissions a role grants: all of them for super admins, the
+// stored ones otherwise.
+func rolePermissions(roleRepo repository.RoleRepository, role models.Role) ([]models.Permission, error) {
+	if role == models.RoleSuperAdmin {
+		return slices.Clone(models.AllPermissions), nil
+	}
+	return roleRepo.GetPermissions(role)
+}
+
+// requireAssignableRole refuses to let a caller give a user a role that grants more than the
+// caller holds. Only super admins can assign the super admin role.
+func requireAssignableRole(roleRepo repository.RoleRepository, callerRole, role models.Role) error {
+	if callerRole == models.RoleSuperAdmin {
+		return nil
+	}
+	if role == models.RoleSuperAdmin {
+		return exceptions.NewAppError(exceptions.FORBIDDEN, "Only super admins can assign the super admin role", nil)
+	}
+	granted, err := rolePermissions(roleRepo, role)
+	if err != nil {
+		return err
+	}
+	return requireHeldPermissions(roleRepo, callerRole, granted)
+}
+
+// requireHeldPermissions refuses to let a caller grant permissions their own role does not
+// grant them. Super admins hold every permission.
+func requireHeldPermissions(roleRepo repository.RoleRepository, callerRole models.Role, permissions []models.Permission) error {
+	if callerRole == models.RoleSuperAdmin || len(permissions) == 0 {
+		return nil
+	}
+	held, err := rolePermissions(roleRepo, callerRole)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, permission := range permissions {
+		if !slices.Contains(held, permission) && !slices.Contains(missing, string(permission)) {
+			missing = append(missing, string(permission))
+		}
+	}
+	if len(missing) > 0 {
+		return exceptions.NewAppError(exceptions.FORBIDDEN, "Cannot grant permissions you do not have", missing)
+	}
+	return nil
+}
+
+// rolePermissionRows checks that every permission exists and returns them as rows of a role,
+// without duplicates.
+func rolePermissionRows(role models.Role, permissions []models.Permission) ([]models.RolePermission, error) {
+	var unknown []string
+	rows := make([]models.RolePermission, 0, len(permissions))
+	seen := make(map[models.Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		if !slices.Contains(models.AllPermissions, permission) {
+			unknown = append(unknown, string(permission))
+			continue
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			rows = append(rows, models.RolePermission{Role: role, Permission: permission})
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, exceptions.NewAppError(exceptions.BadRequest, "unknown permissions", unknown)
+	}
+	return rows, nil
+}
+
+func toRoleResponse(role *models.RoleDefinition) *dto.RoleResponse {
+	permissions := make([]models.Permission, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		permissions[i] = permission.Permission
+	}
+	if role.Name == models.RoleSuperAdmin {
+		permissions = slices.Clone(models.AllPermissions)
+	}
+	slices.Sort(permissions)
+	return &dto.RoleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		BuiltIn:     role.BuiltIn,
+		Permissions: permissions,
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/repository"
+)
+
+type routeService struct {
+	routeRepo        repository.RouteRepository
+	airportRepo      repository.AirportRepository
+	aircraftTypeRepo repository.AircraftTypeRepository
+}
+
+func NewRouteService(routeRepo repository.RouteRepository, airportRepo repository.AirportRepository, aircraftTypeRepo repository.AircraftTypeRepository) RouteService {
+	if routeRepo == nil || airportRepo == nil || aircraftTypeRepo == nil {
+		panic("Missing required repositories for route service")
+	}
+	return &routeService{
+		routeRepo:        routeRepo,
+		airportRepo:      airportRepo,
+		aircraftTypeRepo: aircraftTypeRepo,
+	}
+}
+
+func (r routeService) GetAllRoutes() ([]*dto.RouteResponse, error) {
+	routes, err := r.routeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.RouteResponse, len(routes))
+	for i, route := range routes {
+		responses[i] = toRouteResponse(route)
+	}
+	return responses, nil
+}
+
+func (r routeService) GetRoute(id uint) (*dto.RouteResponse, error) {
+	route, err := r.routeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toRouteResponse(route), nil
+}
+
+// CreateRoute approves a new route. The response warns about allowed aircraft types without
+// the range to fly it and about a default duration far longer than the distance needs.
+func (r routeService) CreateRoute(request *dto.RouteRequest) (*dto.RouteResponse, error) {
+	route := &models.Route{}
+	warnings, err := r.applyRouteRequest(route, request)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.routeRepo.Create(route); err != nil {
+		return nil, err
+	}
+	response := toRouteResponse(route)
+	response.Warnings = warnings
+	return response, nil
+}
+
+// UpdateRoute changes a route's settings. Its airports cannot change once flights use it.
+func (r routeService) UpdateRoute(id uint, request *dto.RouteRequest) (*dto.RouteResponse, error) {
+	route, err := r.routeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(request.DepartureAirport, route.DepartureAirport.AirportCode) ||
+		!strings.EqualFold(request.ArrivalAirport, route.ArrivalAirport.AirportCode) {
+		flights, err := r.routeRepo.CountFlights(route.ID)
+		if err != nil {
+			return nil, err
+		}
+		if flights > 0 {
+			return nil, exceptions.NewAppError(exceptions.CONFLICT,
+				fmt.Sprintf("route %s is used by %d flights, its airports cannot be changed", routeCode(route), flights), nil)
+		}
+	}
+
+	warnings, err := r.applyRouteRequest(route, request)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.routeRepo.Update(route); err != nil {
+		return nil, err
+	}
+	response := toRouteResponse(route)
+	response.Warnings = warnings
+	return response, nil
+}
+
+// DeleteRoute withdraws a route no flight uses.
+func (r routeService) DeleteRoute(id uint) error {
+	route, err := r.routeRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	flights, err := r.routeRepo.CountFlights(route.ID)
+	if err != nil {
+		return err
+	}
+	if flights > 0 {
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("route %s is used by %d flights and cannot be deleted", routeCode(route), flights), nil)
+	}
+	return r.routeRepo.Delete(route)
+}
+
+// GetNetwork returns every airport and the routes between them, optionally only the routes
+// the given aircraft type may fly.
+func (r routeService) GetNetwork(query *dto.RouteNetworkQuery) (*dto.RouteNetworkResponse, error) {
+	aircraftType := strings.ToUpper(strings.TrimSpace(query.AircraftType))
+	if aircraftType != "" {
+		if _, err := r.aircraftTypeRepo.GetByCode(aircraftType); err != nil {
+			return nil, err
+		}
+	}
+
+	airports, err := r.airportRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	routes, err := r.routeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(airports, func(a, b *models.Airport) int {
+		return strings.Compare(a.AirportCode, b.AirportCode)
+	})
+	network := &dto.RouteNetworkResponse{
+		Nodes: make([]dto.RouteNetworkNode, len(airports)),
+		Edges: make([]dto.RouteNetworkEdge, 0, len(routes)),
+	}
+	nodes := make(map[uint]*dto.RouteNetworkNode, len(airports))
+	for i, airport := range airports {
+		network.Nodes[i] = dto.RouteNetworkNode{
+			AirportCode: airport.AirportCode,
+			AirportName: airport.AirportName,
+			CityName:    airport.CityName,
+			Latitude:    airport.Latitude,
+			Longitude:   airport.Longitude,
+		}
+		nodes[airport.ID] = &network.Nodes[i]
+	}
+
+	for _, route := range routes {
+		allowed := allowedAircraftTypeCodes(route)
+		if aircraftType != "" && len(allowed) > 0 && !slices.Contains(allowed, aircraftType) {
+			continue
+		}
+		if node, ok := nodes[route.DepartureAirportID]; ok {
+			node.Outbound++
+		}
+		if node, ok := nodes[route.ArrivalAirportID]; ok {
+			node.Inbound++
+		}
+		distanceKm, _ := routeDistance([]*models.Airport{&route.DepartureAirport, &route.ArrivalAirport})
+		network.Edges = append(network.Edges, dto.RouteNetworkEdge{
+			RouteID:              route.ID,
+			From:                 route.DepartureAirport.AirportCode,
+			To:                   route.ArrivalAirport.AirportCode,
+			DistanceKm:           distanceKm,
+			DefaultDuration:      route.DefaultDuration,
+			AllowedAircraftTypes: allowed,
+		})
+	}
+	return network, nil
+}
+
+// applyRouteRequest copies the request onto the route after checking its airports, aircraft
+// types and overrides, and returns the warnings to show with the route.
+func (r routeService) applyRouteRequest(route *models.Route, request *dto.RouteRequest) ([]string, error) {
+	if request.MinIntermediateStopDuration != nil && request.MaxIntermediateStopDuration != nil &&
+		*request.MinIntermediateStopDuration > *request.MaxIntermediateStopDuration {
+		return nil, exceptions.BadRequestError("minimum intermediate stop duration cannot exceed the maximum", nil)
+	}
+	if request.MinFlightDuration != nil && request.DefaultDuration < *request.MinFlightDuration {
+		return nil, exceptions.BadRequestError(
+			fmt.Sprintf("default duration must be at least the route's minimum flight duration of %d minutes", *request.MinFlightDuration), nil)
+	}
+
+	departure, err := r.airportRepo.GetByCode(strings.ToUpper(request.DepartureAirport))
+	if err != nil {
+		return nil, err
+	}
+	arrival, err := r.airportRepo.GetByCode(strings.ToUpper(request.ArrivalAirport))
+	if err != nil {
+		return nil, err
+	}
+	if departure.ID == arrival.ID {
+		return nil, exceptions.BadRequestError("departure and arrival airports cannot be the same", nil)
+	}
+	existing, err := r.routeRepo.GetByAirportCodes(departure.AirportCode, arrival.AirportCode)
+	if err == nil && existing.ID != route.ID {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("route %s-%s already exists", departure.AirportCode, arrival.AirportCode), nil)
+	} else if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	airports := []*models.Airport{departure, arrival}
+	durationWarning, err := checkFlightDuration(airports, request.DefaultDuration, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	aircraftTypes := make([]models.AircraftType, 0, len(request.AllowedAircraftTypes))
+	for _, code := range request.AllowedAircraftTypes {
+		aircraftType, err := r.aircraftTypeRepo.GetByCode(strings.ToUpper(strings.TrimSpace(code)))
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(aircraftTypes, func(t models.AircraftType) bool { return t.ID == aircraftType.ID }) {
+			continue
+		}
+		warnings = append(warnings, rangeWarnings(aircraftType, airports)...)
+		aircraftTypes = append(aircraftTypes, *aircraftType)
+	}
+	if durationWarning != "" {
+		warnings = append(warnings, durationWarning)
+	}
+
+	route.DepartureAirportID = departure.ID
+	route.DepartureAirport = *departure
+	route.ArrivalAirportID = arrival.ID
+	route.ArrivalAirport = *arrival
+	route.DefaultDuration = request.DefaultDuration
+	route.DefaultBasePrice = request.DefaultBasePrice
+	route.AllowedAircraftTypes = aircraftTypes
+	route.MinFlightDuration = request.MinFlightDuration
+	route.MaxIntermediateStops = request.MaxIntermediateStops
+	route.MinIntermediateStopDuration = request.MinIntermediateStopDuration
+	route.MaxIntermediateStopDuration = request.MaxIntermediateStopDuration
+	route.LatestTicketPurchaseTime = request.LatestTicketPurchaseTime
+	route.TicketCancellationTime = request.TicketCancellationTime
+	return warnings, nil
+}
+
+// checkAllowedAircraft refuses a plane whose aircraft type the route does not allow.
+func checkAllowedAircraft(route *models.Route, plane *models.Plane) error {
+	allowed := allowedAircraftTypeCodes(route)
+	if len(allowed) == 0 {
+		return nil
+	}
+	if plane.AircraftType == nil {
+		return exceptions.BadRequestError(fmt.Sprintf("plane '%s' has no aircraft type, route %s only allows %s",
+			plane.PlaneCode, routeCode(route), strings.Join(allowed, ", ")), nil)
+	}
+	if !slices.Contains(allowed, plane.AircraftType.TypeCode) {
+		return exceptions.BadRequestError(fmt.Sprintf("aircraft type %s of plane '%s' is not allowed on route %s, only %s",
+			plane.AircraftType.TypeCode, plane.PlaneCode, routeCode(route), strings.Join(allowed, ", ")), nil)
+	}
+	return nil
+}
+
+func allowedAircraftTypeCodes(route *models.Route) []string {
+	codes := make([]string, len(route.AllowedAircraftTypes))
+	for i, aircraftType := range route.AllowedAircraftTypes {
+		codes[i] = aircraftType.TypeCode
+	}
+	return codes
+}
+
+func routeCode(route *models.Route) string {
+	return route.DepartureAirport.AirportCode + "-" + route.ArrivalAirport.AirportCode
+}
+
+func toRouteResponse(route *models.Route) *dto.RouteResponse {
+	distanceKm, _ := routeDistance([]*models.Airport{&route.DepartureAirport, &route.ArrivalAirport})
+	return &dto.RouteResponse{
+		ID:                          route.ID,
+		DepartureAirport:            route.DepartureAirport.AirportCode,
+		ArrivalAirport:              route.ArrivalAirport.AirportCode,
+		DistanceKm:                  distanceKm,
+		DefaultDuration:             route.DefaultDuration,
+		DefaultBasePrice:            route.DefaultBasePrice,
+		AllowedAircraftTypes:        allowedAircraftTypeCodes(route),
+		MinFlightDuration:           route.MinFlightDuration,
+		MaxIntermediateStops:        route.MaxIntermediateStops,
+		MinIntermediateStopDuration: route.MinIntermediateStopDuration,
+		MaxIntermediateStopDuration: route.MaxIntermediateStopDuration,
+		LatestTicketPurchaseTime:    route.LatestTicketPurchaseTime,
+		TicketCancellationTime:      route.TicketCancellationTime,
+	}
+}
@@ -10,16 +10,21 @@ import (
 )
 
 type SchedulerService struct {
-	ticketRepo repository.TicketRepository
-	flightRepo repository.FlightRepository
-	logger     *zap.Logger
+	ticketRepo     repository.TicketRepository
+	flightRepo     repository.FlightRepository
+	loyaltyService LoyaltyService
+	privacyService PrivacyService
+	logger         *zap.Logger
 }
 
-func NewSchedulerService(ticketRepo repository.TicketRepository, flightRepo repository.FlightRepository) *SchedulerService {
+func NewSchedulerService(ticketRepo repository.TicketRepository, flightRepo repository.FlightRepository,
+	loyaltyService LoyaltyService, privacyService PrivacyService) *SchedulerService {
 	return &SchedulerService{
-		ticketRepo: ticketRepo,
-		flightRepo: flightRepo,
-		logger:     zap.L(),
+		ticketRepo:     ticketRepo,
+		flightRepo:     flightRepo,
+		loyaltyService: loyaltyService,
+		privacyService: privacyService,
+		logger:         zap.L(),
 	}
 }
 
@@ -35,6 +40,47 @@ func (s *SchedulerService) StartPlaceOrderCancellationJob() {
 	}()
 }
 
+func (s *SchedulerService) StartDataRetentionJob() {
+	// Run at startup, so restarts do not postpone it, then once a day
+	ticker := time.NewTicker(24 * time.Hour)
+	go func() {
+		s.applyRetentionPolicy()
+		for range ticker.C {
+			s.applyRetentionPolicy()
+		}
+	}()
+}
+
+func (s *SchedulerService) applyRetentionPolicy() {
+	tickets, passengers, err := s.privacyService.ApplyRetentionPolicy()
+	if err != nil {
+		s.logger.Error("Error applying data retention policy", zap.Error(err))
+		return
+	}
+	s.logger.Info("Applied data retention policy",
+		zap.Int64("ticketsAnonymized", tickets), zap.Int64("passengersAnonymized", passengers))
+}
+
+func (s *SchedulerService) StartLoyaltyTierJob() {
+	// Run at startup, then once a day, as qualifying points age out
+	ticker := time.NewTicker(24 * time.Hour)
+	go func() {
+		s.refreshLoyaltyTiers()
+		for range ticker.C {
+			s.refreshLoyaltyTiers()
+		}
+	}()
+}
+
+func (s *SchedulerService) refreshLoyaltyTiers() {
+	changed, err := s.loyaltyService.RefreshTiers()
+	if err != nil {
+		s.logger.Error("Error refreshing loyalty tiers", zap.Error(err))
+		return
+	}
+	s.logger.Info("Refreshed loyalty tiers", zap.Int("accountsChanged", changed))
+}
+
 func (s *SchedulerService) cancelExpiredPlaceOrders() error {
 	// Get all flights that are departing within the next 24 hours
 	now := time.Now()
@@ -62,6 +108,11 @@ func (s *SchedulerService) cancelExpiredPlaceOrders() error {
 				zap.Error(err))
 			continue
 		}
+		if err := s.loyaltyService.ReverseForTicket(&ticket, false); err != nil {
+			s.logger.Error("Error returning redeemed points for expired place order",
+				zap.Uint("ticketID", ticket.ID),
+				zap.Error(err))
+		}
 		s.logger.Debug("Expired place order",
 			zap.Uint("ticketID", ticket.ID),
 			zap.Uint("flightID", ticket.FlightID))
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+)
+
+// assignSeats picks a seat for every request of a booking. Requested seat numbers must be
+// free; the other passengers of each ticket class are seated next to each other where the
+// cabin allows it, honoring their seat preferences. Blocked and occupied seats are never
+// assigned. seats must be in seat map order; layout, the plane's seat layout, tells where the
+// aisles are and is nil for planes configured before seat layouts existed.
+func assignSeats(layout *models.SeatLayout, seats []*models.Seat, occupied map[uint]bool, requests []*dto.TicketRequest) ([]*models.Seat, error) {
+	assigned := make([]*models.Seat, len(requests))
+	taken := make(map[uint]bool, len(occupied)+len(requests))
+	for id := range occupied {
+		taken[id] = true
+	}
+
+	seatsByNumber := make(map[string]*models.Seat, len(seats))
+	for _, seat := range seats {
+		seatsByNumber[seat.SeatNumber] = seat
+	}
+
+	// Passengers who chose their seat keep it
+	var classes []string
+	pending := make(map[string][]int)
+	for i, request := range requests {
+		if request.SeatNumber == "" {
+			class := strings.ToUpper(strings.TrimSpace(request.TicketClass))
+			if _, ok := pending[class]; !ok {
+				classes = append(classes, class)
+			}
+			pending[class] = append(pending[class], i)
+			continue
+		}
+
+		seatNumber := strings.ToUpper(strings.TrimSpace(request.SeatNumber))
+		seat, ok := seatsByNumber[seatNumber]
+		if !ok {
+			return nil, exceptions.NotFoundError("seat", seatNumber)
+		}
+		if request.TicketClass != "" && !strings.EqualFold(seat.TicketClass.TicketClassName, request.TicketClass) {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("seat %s is not a %s seat", seatNumber, request.TicketClass), nil)
+		}
+		if seat.IsBlocked {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("seat %s is blocked and cannot be booked", seatNumber), nil)
+		}
+		if taken[seat.ID] {
+			return nil, exceptions.NewAppError(exceptions.CONFLICT, fmt.Sprintf("seat %s is already booked for this flight", seatNumber), nil)
+		}
+		taken[seat.ID] = true
+		assigned[i] = seat
+	}
+
+	// Everyone else is seated together within their ticket class
+	for _, class := range classes {
+		indexes := pending[class]
+		rows := seatRows(seats, class)
+		if len(rows) == 0 {
+			return nil, exceptions.BadRequestError(fmt.Sprintf("flight has no %s seats", class), nil)
+		}
+
+		preferences := make([]models.SeatPosition, len(indexes))
+		for j, i := range indexes {
+			preferences[j] = requests[i].SeatPreference
+		}
+		block := findSeatBlock(layout, rows, taken, len(indexes), preferences)
+		if block == nil {
+			return nil, exceptions.NewAppError(exceptions.CONFLICT,
+				fmt.Sprintf("not enough free %s seats on this flight for %d passengers", class, len(indexes)), nil)
+		}
+		for j, seat := range placePassengers(block, preferences) {
+			assigned[indexes[j]] = seat
+			taken[seat.ID] = true
+		}
+	}
+	return assigned, nil
+}
+
+// seatRows groups the seats of a ticket class by row. Seats without a row, such as those of
+// planes configured before seat layouts existed, form a single row in seat order.
+func seatRows(seats []*models.Seat, class string) [][]*models.Seat {
+	var rows [][]*models.Seat
+	for _, seat := range seats {
+		if !strings.EqualFold(seat.TicketClass.TicketClassName, class) {
+			continue
+		}
+		if n := len(rows); n > 0 && rows[n-1][0].RowNumber == seat.RowNumber {
+			rows[n-1] = append(rows[n-1], seat)
+			continue
+		}
+		rows = append(rows, []*models.Seat{seat})
+	}
+	return rows
+}
+
+// findSeatBlock returns n free seats for passengers travelling together. It looks for seats
+// side by side first, then seats in one row across the aisle, and finally the free seats
+// spanning the fewest rows.
+func findSeatBlock(layout *models.SeatLayout, rows [][]*models.Seat, taken map[uint]bool, n int, preferences []models.SeatPosition) []*models.Seat {
+	var sideBySide, sameRow [][]*models.Seat
+	var free []*models.Seat
+	for _, row := range rows {
+		var run, rowFree []*models.Seat
+		for i, seat := range row {
+			if i > 0 && acrossAisle(layout, row[i-1], seat) {
+				sideBySide = append(sideBySide, run)
+				run = nil
+			}
+			if seat.IsBlocked || taken[seat.ID] {
+				sideBySide = append(sideBySide, run)
+				run = nil
+				continue
+			}
+			run = append(run, seat)
+			rowFree = append(rowFree, seat)
+		}
+		sideBySide = append(sideBySide, run)
+		sameRow = append(sameRow, rowFree)
+		free = append(free, rowFree...)
+	}
+
+	for _, candidates := range [][][]*models.Seat{sideBySide, sameRow, {free}} {
+		if block := bestWindow(candidates, n, preferences); block != nil {
+			return block
+		}
+	}
+	return nil
+}
+
+// bestWindow returns the n consecutive seats of any list that span the fewest rows and
+// satisfy the most preferences, favoring seats nearer the front.
+func bestWindow(lists [][]*models.Seat, n int, preferences []models.SeatPosition) []*models.Seat {
+	var best []*models.Seat
+	bestSpan, bestScore := 0, 0
+	for _, list := range lists {
+		for start := 0; start+n <= len(list); start++ {
+			block := list[start : start+n]
+			span := block[n-1].RowNumber - block[0].RowNumber
+			score := preferenceScore(block, preferences)
+			if best == nil || span < bestSpan || (span == bestSpan && score > bestScore) {
+				best, bestSpan, bestScore = block, span, score
+			}
+		}
+	}
+	return best
+}
+
+// acrossAisle reports whether two neighboring seats of a row are separated by an aisle: one
+// follows a layout column from the left seat's up to the right seat's. Narrow cabins leave
+// columns out, so the seats' positions alone cannot tell; a window seat may face an aisle.
+// Without a layout there are no known aisles.
+func acrossAisle(layout *models.SeatLayout, left, right *models.Seat) bool {
+	if layout == nil {
+		return false
+	}
+	from := slices.Index(layout.Columns, left.ColumnLetter)
+	to := slices.Index(layout.Columns, right.ColumnLetter)
+	if from < 0 || to < from {
+		return false
+	}
+	for _, column := range layout.Columns[from:to] {
+		if slices.Contains(layout.AislesAfter, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// preferenceScore counts how many seat preferences the block can satisfy.
+func preferenceScore(block []*models.Seat, preferences []models.SeatPosition) int {
+	available := make(map[models.SeatPosition]int)
+	for _, seat := range block {
+		available[seat.Position]++
+	}
+	score := 0
+	for _, preference := range preferences {
+		if preference != "" && available[preference] > 0 {
+			available[preference]--
+			score++
+		}
+	}
+	return score
+}
+
+// placePassengers seats the passengers within the block, giving each preference a matching
+// seat where one is left. The result is indexed like preferences.
+func placePassengers(block []*models.Seat, preferences []models.SeatPosition) []*models.Seat {
+	placed := make([]*models.Seat, len(preferences))
+	used := make([]bool, len(block))
+	for i, preference := range preferences {
+		if preference == "" {
+			continue
+		}
+		for j, seat := range block {
+			if !used[j] && seat.Position == preference {
+				placed[i], used[j] = seat, true
+				break
+			}
+		}
+	}
+	for i := range placed {
+		if placed[i] != nil {
+			continue
+		}
+		for j, seat := range block {
+			if !used[j] {
+				placed[i], used[j] = seat, true
+				break
+			}
+		}
+	}
+	return placed
+}
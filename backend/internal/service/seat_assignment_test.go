@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+)
+
+var (
+	// sixAbreast is a 3-3 cabin with the aisle after C
+	sixAbreast = &models.SeatLayout{
+		Columns:     []string{"A", "B", "C", "D", "E", "F"},
+		AislesAfter: []string{"C"},
+	}
+	// wideBody has aisles after C and G; its 1-2-1 cabins seat only A, D, G and K
+	wideBody = &models.SeatLayout{
+		Columns:     []string{"A", "B", "C", "D", "E", "F", "G", "H", "K"},
+		AislesAfter: []string{"C", "G"},
+	}
+	oneTwoOne = []string{"A", "D", "G", "K"}
+)
+
+// testSeatMap returns the economy seats of the given cabin columns, all of the layout's when
+// nil, in seat map order. Seat IDs follow the same order, starting at 1.
+func testSeatMap(layout *models.SeatLayout, columns []string, rows int, blocked ...string) []*models.Seat {
+	if columns == nil {
+		columns = layout.Columns
+	}
+	economy := models.TicketClass{TicketClassName: "ECONOMY"}
+	var seats []*models.Seat
+	for row := 1; row <= rows; row++ {
+		for _, column := range columns {
+			seat := &models.Seat{
+				SeatNumber:   fmt.Sprintf("%d%s", row, column),
+				RowNumber:    row,
+				ColumnLetter: column,
+				Position:     seatPosition(layout, columns, column),
+				TicketClass:  economy,
+			}
+			seat.ID = uint(len(seats) + 1)
+			seat.IsBlocked = slices.Contains(blocked, seat.SeatNumber)
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
+
+func TestAssignSeats(t *testing.T) {
+	tests := []struct {
+		name        string
+		layout      *models.SeatLayout // sixAbreast when nil
+		columns     []string           // Cabin columns, all of the layout's when nil
+		rows        int
+		blocked     []string
+		occupied    []string
+		preferences []models.SeatPosition // One economy request per entry
+		seatNumbers []string              // Requested seats, by request, when set
+		want        []string
+		wantStatus  int // Status of the error expected instead of seats
+	}{
+		{
+			name:        "side by side at the front",
+			rows:        3,
+			preferences: []models.SeatPosition{"", ""},
+			want:        []string{"1A", "1B"},
+		},
+		{
+			name:        "blocked seat splits the block",
+			rows:        3,
+			blocked:     []string{"1B"},
+			preferences: []models.SeatPosition{"", ""},
+			want:        []string{"1D", "1E"},
+		},
+		{
+			name:        "blocked seats push the group back a row",
+			rows:        3,
+			blocked:     []string{"1B", "1E"},
+			preferences: []models.SeatPosition{"", "", ""},
+			want:        []string{"2A", "2B", "2C"},
+		},
+		{
+			name:        "same row across the aisle when no three seats are side by side",
+			rows:        1,
+			blocked:     []string{"1B"},
+			occupied:    []string{"1E"},
+			preferences: []models.SeatPosition{"", "", ""},
+			want:        []string{"1A", "1C", "1D"},
+		},
+		{
+			name:        "spanning rows skips blocked seats",
+			rows:        2,
+			blocked:     []string{"1A", "1B", "1C", "1D", "2B", "2C", "2D", "2E", "2F"},
+			preferences: []models.SeatPosition{"", "", ""},
+			want:        []string{"1E", "1F", "2A"},
+		},
+		{
+			name:        "preferences within the block",
+			rows:        3,
+			preferences: []models.SeatPosition{models.SeatPositionAisle, models.SeatPositionMiddle},
+			want:        []string{"1C", "1B"},
+		},
+		{
+			name:        "preferences prefer a block that satisfies them",
+			rows:        3,
+			blocked:     []string{"1A"},
+			preferences: []models.SeatPosition{models.SeatPositionWindow, ""},
+			want:        []string{"1F", "1E"},
+		},
+		{
+			name:        "chosen seat is kept and others seated around it",
+			rows:        3,
+			preferences: []models.SeatPosition{"", ""},
+			seatNumbers: []string{"1a", ""},
+			want:        []string{"1A", "1B"},
+		},
+		{
+			name:        "narrow cabin seats the centre pair together",
+			layout:      wideBody,
+			columns:     oneTwoOne,
+			rows:        2,
+			preferences: []models.SeatPosition{"", ""},
+			want:        []string{"1D", "1G"},
+		},
+		{
+			name:        "narrow cabin window and aisle seats are not side by side",
+			layout:      wideBody,
+			columns:     oneTwoOne,
+			rows:        2,
+			occupied:    []string{"1D"},
+			preferences: []models.SeatPosition{"", ""},
+			want:        []string{"2D", "2G"},
+		},
+		{
+			name:        "narrow cabin group across the aisles of one row",
+			layout:      wideBody,
+			columns:     oneTwoOne,
+			rows:        2,
+			preferences: []models.SeatPosition{"", "", ""},
+			want:        []string{"1A", "1D", "1G"},
+		},
+		{
+			name:        "blocked seat cannot be chosen",
+			rows:        3,
+			blocked:     []string{"2C"},
+			preferences: []models.SeatPosition{""},
+			seatNumbers: []string{"2C"},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "occupied seat cannot be chosen",
+			rows:        3,
+			occupied:    []string{"2C"},
+			preferences: []models.SeatPosition{""},
+			seatNumbers: []string{"2C"},
+			wantStatus:  http.StatusConflict,
+		},
+		{
+			name:        "not enough free seats",
+			rows:        1,
+			blocked:     []string{"1A", "1B", "1C", "1D"},
+			occupied:    []string{"1E"},
+			preferences: []models.SeatPosition{"", ""},
+			wantStatus:  http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := tt.layout
+			if layout == nil {
+				layout = sixAbreast
+			}
+			seats := testSeatMap(layout, tt.columns, tt.rows, tt.blocked...)
+			occupied := make(map[uint]bool)
+			for _, seat := range seats {
+				if slices.Contains(tt.occupied, seat.SeatNumber) {
+					occupied[seat.ID] = true
+				}
+			}
+			requests := make([]*dto.TicketRequest, len(tt.preferences))
+			for i, preference := range tt.preferences {
+				requests[i] = &dto.TicketRequest{TicketClass: "Economy", SeatPreference: preference}
+				if i < len(tt.seatNumbers) {
+					requests[i].SeatNumber = tt.seatNumbers[i]
+				}
+			}
+
+			assigned, err := assignSeats(layout, seats, occupied, requests)
+			if tt.wantStatus != 0 {
+				var appErr *exceptions.AppError
+				if !errors.As(err, &appErr) || appErr.StatusCode != tt.wantStatus {
+					t.Fatalf("assignSeats error = %v, want status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("assignSeats: %v", err)
+			}
+
+			got := make([]string, len(assigned))
+			for i, seat := range assigned {
+				got[i] = seat.SeatNumber
+				if seat.IsBlocked || occupied[seat.ID] {
+					t.Errorf("request %d was given seat %s, which is not free", i, seat.SeatNumber)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("assignSeats = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
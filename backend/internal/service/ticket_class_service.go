@@ -0,0 +1,160 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
+	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/internal/repository"
+)
+
+func NewTicketClassService(ticketClassRepo repository.TicketClassRepository, aircraftTypeRepo repository.AircraftTypeRepository, paramRepo repository.ParameterRepository) TicketClassService {
+	if ticketClassRepo == nil || aircraftTypeRepo == nil || paramRepo == nil {
+		panic("Missing required repositories for ticket class service")
+	}
+	return &ticketClassService{
+		ticketClassRepo:  ticketClassRepo,
+		aircraftTypeRepo: aircraftTypeRepo,
+		paramRepo:        paramRepo,
+	}
+}
+
+type ticketClassService struct {
+	ticketClassRepo  repository.TicketClassRepository
+	aircraftTypeRepo repository.AircraftTypeRepository
+	paramRepo        repository.ParameterRepository
+}
+
+func (t ticketClassService) GetAllTicketClasses() ([]*dto.TicketClassResponse, error) {
+	ticketClasses, err := t.ticketClassRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.TicketClassResponse, len(ticketClasses))
+	for i, ticketClass := range ticketClasses {
+		responses[i] = toTicketClassResponse(ticketClass)
+	}
+	return responses, nil
+}
+
+func (t ticketClassService) GetTicketClass(id uint) (*dto.TicketClassResponse, error) {
+	ticketClass, err := t.ticketClassRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toTicketClassResponse(ticketClass), nil
+}
+
+// CreateTicketClass adds a class while the parameters in force allow more classes.
+func (t ticketClassService) CreateTicketClass(request *dto.TicketClassRequest) (*dto.TicketClassResponse, error) {
+	params, err := t.paramRepo.GetAllParams()
+	if err != nil {
+		return nil, err
+	}
+	ticketClass := &models.TicketClass{}
+	applyTicketClassRequest(ticketClass, request)
+	if _, err := t.ticketClassRepo.Create(ticketClass, params.MaxTicketClasses); err != nil {
+		return nil, err
+	}
+	return toTicketClassResponse(ticketClass), nil
+}
+
+// UpdateTicketClass changes a class. Seats keep the class, and the seat templates of aircraft
+// types follow a rename.
+func (t ticketClassService) UpdateTicketClass(id uint, request *dto.TicketClassRequest) (*dto.TicketClassResponse, error) {
+	ticketClass, err := t.ticketClassRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := ticketClass.TicketClassName
+	applyTicketClassRequest(ticketClass, request)
+	var templates []*models.AircraftType
+	if ticketClass.TicketClassName != oldName {
+		templates, err = t.templatesUsing(oldName)
+		if err != nil {
+			return nil, err
+		}
+		for _, aircraftType := range templates {
+			for i := range aircraftType.SeatLayout.Cabins {
+				if aircraftType.SeatLayout.Cabins[i].TicketClass == oldName {
+					aircraftType.SeatLayout.Cabins[i].TicketClass = ticketClass.TicketClassName
+				}
+			}
+		}
+	}
+	if _, err := t.ticketClassRepo.Update(ticketClass, templates); err != nil {
+		return nil, err
+	}
+	return toTicketClassResponse(ticketClass), nil
+}
+
+// DeleteTicketClass removes a class that no seat and no seat template uses.
+func (t ticketClassService) DeleteTicketClass(id uint) error {
+	ticketClass, err := t.ticketClassRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	seats, err := t.ticketClassRepo.CountSeats(ticketClass.ID)
+	if err != nil {
+		return err
+	}
+	if seats > 0 {
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("ticket class '%s' is assigned to %d seats", ticketClass.TicketClassName, seats), nil)
+	}
+	templates, err := t.templatesUsing(ticketClass.TicketClassName)
+	if err != nil {
+		return err
+	}
+	if len(templates) > 0 {
+		typeCodes := make([]string, len(templates))
+		for i, aircraftType := range templates {
+			typeCodes[i] = aircraftType.TypeCode
+		}
+		return exceptions.NewAppError(exceptions.CONFLICT,
+			fmt.Sprintf("ticket class '%s' is used in the seat templates of %s",
+				ticketClass.TicketClassName, strings.Join(typeCodes, ", ")), nil)
+	}
+	return t.ticketClassRepo.Delete(ticketClass)
+}
+
+// templatesUsing returns the aircraft types whose seat template has a cabin of the class.
+func (t ticketClassService) templatesUsing(name string) ([]*models.AircraftType, error) {
+	aircraftTypes, err := t.aircraftTypeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var templates []*models.AircraftType
+	for _, aircraftType := range aircraftTypes {
+		if aircraftType.SeatLayout == nil {
+			continue
+		}
+		for _, cabin := range aircraftType.SeatLayout.Cabins {
+			if cabin.TicketClass == name {
+				templates = append(templates, aircraftType)
+				break
+			}
+		}
+	}
+	return templates, nil
+}
+
+func applyTicketClassRequest(ticketClass *models.TicketClass, request *dto.TicketClassRequest) {
+	ticketClass.TicketClassName = strings.TrimSpace(request.Name)
+	ticketClass.PricePercentage = request.PricePercentage
+	ticketClass.CabinCode = request.CabinCode
+	ticketClass.DisplayOrder = request.DisplayOrder
+}
+
+func toTicketClassResponse(ticketClass *models.TicketClass) *dto.TicketClassResponse {
+	return &dto.TicketClassResponse{
+		ID:              ticketClass.ID,
+		Name:            ticketClass.TicketClassName,
+		PricePercentage: ticketClass.PricePercentage,
+		CabinCode:       ticketClass.CabinCode,
+		DisplayOrder:    ticketClass.DisplayOrder,
+	}
+}
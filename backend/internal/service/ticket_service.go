@@ -1,20 +1,37 @@
 package service
 
 import (
+	"crypto/rand"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aprilboiz/flight-management/internal/dto"
 	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/internal/repository"
+	"github.com/aprilboiz/flight-management/pkg/encryption"
+	"github.com/aprilboiz/flight-management/pkg/validator"
+	"gorm.io/gorm"
 )
 
+// passportValidityMonths is how long a passport must remain valid after departure.
+const passportValidityMonths = 6
+
+// piiRotationBatchSize is how many tickets are re-encrypted per transaction.
+const piiRotationBatchSize = 500
+
+// bookingReferenceLength is the number of characters in a booking reference.
+const bookingReferenceLength = 6
+
 type ticketService struct {
-	ticketRepo repository.TicketRepository
-	flightRepo repository.FlightRepository
-	planeRepo  repository.PlaneRepository
-	paramRepo  repository.ParameterRepository
+	ticketRepo    repository.TicketRepository
+	flightRepo    repository.FlightRepository
+	planeRepo     repository.PlaneRepository
+	paramRepo     repository.ParameterRepository
+	passengerRepo repository.PassengerRepository
+
+	loyaltyService LoyaltyService
 }
 
 func (t *ticketService) GetAllTickets() ([]*dto.TicketResponse, error) {
@@ -25,16 +42,18 @@ func (t *ticketService) GetAllTickets() ([]*dto.TicketResponse, error) {
 	}
 	for _, ticket := range allTickets {
 		tickets = append(tickets, &dto.TicketResponse{
-			ID:           ticket.ID,
-			FlightCode:   ticket.Flight.FlightCode,
-			SeatNumber:   ticket.Seat.SeatNumber,
-			Price:        ticket.Price,
-			FullName:     ticket.FullName,
-			IDCard:       ticket.IDCard,
-			PhoneNumber:  ticket.PhoneNumber,
-			Email:        ticket.Email,
-			TicketStatus: ticket.TicketStatus,
-			BookingType:  ticket.BookingType,
+			ID:               ticket.ID,
+			FlightCode:       ticket.Flight.FlightCode,
+			SeatNumber:       ticket.Seat.SeatNumber,
+			Price:            ticket.Price,
+			FullName:         ticket.FullName,
+			IDCard:           ticket.IDCard,
+			PhoneNumber:      ticket.PhoneNumber,
+			Email:            ticket.Email,
+			TicketStatus:     ticket.TicketStatus,
+			BookingType:      ticket.BookingType,
+			PassengerID:      ticket.PassengerID,
+			BookingReference: ticket.BookingReference,
 		})
 	}
 	return tickets, nil
@@ -46,98 +65,607 @@ func (t *ticketService) GetTicketByID(id uint) (*dto.TicketResponse, error) {
 		return nil, err
 	}
 	return &dto.TicketResponse{
-		ID:           ticket.ID,
-		FlightCode:   ticket.Flight.FlightCode,
-		SeatNumber:   ticket.Seat.SeatNumber,
-		Price:        ticket.Price,
-		FullName:     ticket.FullName,
-		IDCard:       ticket.IDCard,
-		PhoneNumber:  ticket.PhoneNumber,
-		Email:        ticket.Email,
-		TicketStatus: ticket.TicketStatus,
-		BookingType:  ticket.BookingType,
+		ID:               ticket.ID,
+		FlightCode:       ticket.Flight.FlightCode,
+		SeatNumber:       ticket.Seat.SeatNumber,
+		Price:            ticket.Price,
+		FullName:         ticket.FullName,
+		IDCard:           ticket.IDCard,
+		PhoneNumber:      ticket.PhoneNumber,
+		Email:            ticket.Email,
+		TicketStatus:     ticket.TicketStatus,
+		BookingType:      ticket.BookingType,
+		PassengerID:      ticket.PassengerID,
+		BookingReference: ticket.BookingReference,
+	}, nil
+}
+
+func (t *ticketService) GetTicketsByIDCard(idCard string) ([]*dto.TicketResponse, error) {
+	index := encryption.GetKeyring().BlindIndex(idCard)
+	if index == "" {
+		return nil, exceptions.BadRequestError("ID card number is required", nil)
+	}
+
+	matches, err := t.ticketRepo.GetByIDCardIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	tickets := make([]*dto.TicketResponse, 0, len(matches))
+	for _, ticket := range matches {
+		tickets = append(tickets, &dto.TicketResponse{
+			ID:               ticket.ID,
+			FlightCode:       ticket.Flight.FlightCode,
+			SeatNumber:       ticket.Seat.SeatNumber,
+			Price:            ticket.Price,
+			FullName:         ticket.FullName,
+			IDCard:           ticket.IDCard,
+			PhoneNumber:      ticket.PhoneNumber,
+			Email:            ticket.Email,
+			TicketStatus:     ticket.TicketStatus,
+			BookingType:      ticket.BookingType,
+			PassengerID:      ticket.PassengerID,
+			BookingReference: ticket.BookingReference,
+		})
+	}
+	return tickets, nil
+}
+
+func (t *ticketService) GetTicketsByBookingReference(reference string) ([]*dto.TicketResponse, error) {
+	matches, err := t.ticketRepo.GetByBookingReference(strings.ToUpper(strings.TrimSpace(reference)))
+	if err != nil {
+		return nil, err
+	}
+	tickets := make([]*dto.TicketResponse, 0, len(matches))
+	for _, ticket := range matches {
+		tickets = append(tickets, &dto.TicketResponse{
+			ID:               ticket.ID,
+			FlightCode:       ticket.Flight.FlightCode,
+			SeatNumber:       ticket.Seat.SeatNumber,
+			Price:            ticket.Price,
+			FullName:         ticket.FullName,
+			IDCard:           ticket.IDCard,
+			PhoneNumber:      ticket.PhoneNumber,
+			Email:            ticket.Email,
+			TicketStatus:     ticket.TicketStatus,
+			BookingType:      ticket.BookingType,
+			PassengerID:      ticket.PassengerID,
+			BookingReference: ticket.BookingReference,
+		})
+	}
+	return tickets, nil
+}
+
+// RotatePIIEncryption re-encrypts ticket, passenger and travel document PII still sealed with a
+// retired key (or stored in plaintext) under the active key. Retired keys can be removed from
+// configuration afterwards.
+func (t *ticketService) RotatePIIEncryption() (*dto.PIIRotationResponse, error) {
+	keyring := encryption.GetKeyring()
+	tickets, err := t.ticketRepo.ReencryptPII(keyring.ActivePrefix(), piiRotationBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	passengers, documents, err := t.passengerRepo.ReencryptPII(keyring.ActivePrefix(), piiRotationBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PIIRotationResponse{
+		ActiveKeyID:           keyring.ActiveKeyID(),
+		ReencryptedTickets:    tickets,
+		ReencryptedPassengers: passengers,
+		ReencryptedDocuments:  documents,
 	}, nil
 }
 
 func (t *ticketService) Create(ticket *dto.TicketRequest) (*dto.TicketResponse, error) {
+	tickets, err := t.book(ticket.FlightCode, []*dto.TicketRequest{ticket})
+	if err != nil {
+		return nil, err
+	}
+	return tickets[0], nil
+}
+
+func (t *ticketService) CreateGroup(group *dto.GroupTicketRequest) (*dto.GroupTicketResponse, error) {
+	requests := make([]*dto.TicketRequest, len(group.Passengers))
+	for i, passenger := range group.Passengers {
+		requests[i] = &dto.TicketRequest{
+			FlightCode:         group.FlightCode,
+			TicketClass:        group.TicketClass,
+			SeatPreference:     passenger.SeatPreference,
+			BookingType:        group.BookingType,
+			TicketPassengerDTO: passenger.TicketPassengerDTO,
+		}
+	}
+
+	tickets, err := t.book(group.FlightCode, requests)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.GroupTicketResponse{
+		BookingReference: tickets[0].BookingReference,
+		Tickets:          tickets,
+	}, nil
+}
+
+// book issues one ticket per request on the flight under a shared booking reference.
+// Requests without a seat number are assigned seats of their ticket class. Seats are
+// assigned while the flight is locked, so concurrent bookings never share a seat.
+func (t *ticketService) book(flightCode string, requests []*dto.TicketRequest) ([]*dto.TicketResponse, error) {
 	// 1. Validate flight exists and is not in the past
-	flight, err := t.flightRepo.GetByCode(ticket.FlightCode)
+	flight, err := t.flightRepo.GetByCode(flightCode)
 	if err != nil {
 		return nil, err
 	}
 
 	// Check if a flight is in the past
-	if flight.DepartureDateTime.Before(time.Now()) {
+	bookedAt := time.Now()
+	if flight.DepartureDateTime.Before(bookedAt) {
 		return nil, exceptions.BadRequestError("cannot book ticket for a past flight", nil)
 	}
 
-	// 2. Validate seat exists and is available
-	seat, err := t.planeRepo.GetSeatByNumberAndPlaneCode(ticket.SeatNumber, flight.Plane.PlaneCode)
+	// 2. Check the seats can be assigned before any passenger details are recorded
+	seats, err := t.planeRepo.GetSeatsByPlaneID(flight.PlaneID)
 	if err != nil {
 		return nil, err
 	}
-
-	// Check if a seat is already booked for this flight
-	var existingTicket models.Ticket
-	result := t.ticketRepo.GetDB().Where("flight_id = ? AND seat_id = ?", flight.ID, seat.ID).First(&existingTicket)
-	if result.Error == nil {
-		return nil, exceptions.BadRequestError("seat is already booked for this flight", nil)
+	activeTickets, err := t.ticketRepo.GetActiveTicketsByFlightID(flight.ID)
+	if err != nil {
+		return nil, err
+	}
+	occupied := make(map[uint]bool, len(activeTickets))
+	for _, ticket := range activeTickets {
+		occupied[ticket.SeatID] = true
+	}
+	if _, err := assignSeats(flight.Plane.SeatLayout, seats, occupied, requests); err != nil {
+		return nil, err
 	}
 
-	// 3. Calculate ticket price based on seat class
-	basePrice := flight.BasePrice
-	ticketPrice := basePrice * seat.TicketClass.PricePercentage
-
-	// 4. Validate booking type and timing
-	bookingType := models.BookingTypeTicket
-	if ticket.BookingType == models.BookingTypePlaceOrder {
-		bookingType = models.BookingTypePlaceOrder
+	// 3. Validate booking type and timing
+	for _, ticket := range requests {
+		if ticket.BookingType != models.BookingTypePlaceOrder {
+			ticket.BookingType = models.BookingTypeTicket
+			continue
+		}
 
-		// Get parameters for place order timing
-		params, err := t.paramRepo.GetAllParams()
+		// Get the parameters in force on the flight at booking time for place order timing
+		params, err := t.paramRepo.GetParamsAt(bookedAt)
 		if err != nil {
 			return nil, err
 		}
+		params = flightParameters(params, flight)
 
 		// Check if place order is within the allowed time window
 		daysBefore := time.Duration(params.LatestTicketPurchaseTime) * 24 * time.Hour
 		deadline := flight.DepartureDateTime.Add(-daysBefore)
-		if time.Now().After(deadline) {
+		if bookedAt.After(deadline) {
 			return nil, exceptions.BadRequestError(fmt.Sprintf("place orders must be made at least %d days before departure", params.LatestTicketPurchaseTime), nil)
 		}
 	}
 
-	// 5. Create the ticket
-	newTicket := &models.Ticket{
-		FlightID:     flight.ID,
-		SeatID:       seat.ID,
-		Price:        ticketPrice,
-		FullName:     ticket.FullName,
-		IDCard:       ticket.IDCard,
-		PhoneNumber:  ticket.PhoneNumber,
-		Email:        ticket.Email,
-		TicketStatus: models.TicketStatusActive,
-		BookingType:  bookingType,
+	reference, err := newBookingReference()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make(map[string]*bookingPassenger, len(requests))
+	passengers := make([]*bookingPassenger, len(requests))
+	tickets := make([]*models.Ticket, len(requests))
+	for i, ticket := range requests {
+		// 4. Validate travel documents for the route
+		passport, err := t.validateTravelDocuments(flight, ticket)
+		if err != nil {
+			return nil, err
+		}
+
+		// 5. Resolve the passenger profile the ticket is issued to
+		passenger, err := t.resolvePassenger(ticket, profiles)
+		if err != nil {
+			return nil, err
+		}
+		if passport != nil {
+			if err := t.addPassport(passenger, passport); err != nil {
+				return nil, err
+			}
+		}
+
+		passengers[i] = passenger
+		tickets[i] = &models.Ticket{
+			FlightID:         flight.ID,
+			FullName:         ticket.FullName,
+			IDCard:           ticket.IDCard,
+			PhoneNumber:      ticket.PhoneNumber,
+			Email:            ticket.Email,
+			TicketStatus:     models.TicketStatusActive,
+			BookingType:      ticket.BookingType,
+			PassengerID:      &passenger.passenger.ID,
+			BookingReference: reference,
+			CreatedAt:        bookedAt,
+		}
 	}
 
-	createdTicket, err := t.ticketRepo.Create(newTicket)
+	// 6. Record the passenger profiles, assign the seats and create the tickets
+	var assigned []*models.Seat
+	err = t.ticketRepo.CreateWithSeats(flight.ID, tickets, func(tx *gorm.DB, occupied map[uint]bool) error {
+		for _, passenger := range passengers {
+			if err := passenger.save(tx); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		assigned, err = assignSeats(flight.Plane.SeatLayout, seats, occupied, requests)
+		if err != nil {
+			return err
+		}
+		for i, seat := range assigned {
+			// Calculate ticket price based on seat class and seat surcharge
+			ticketPrice := flight.BasePrice*seat.TicketClass.PricePercentage + seat.Surcharge
+
+			// Apply a loyalty points redemption to the fare
+			if requests[i].RedeemPoints > 0 {
+				discount, err := t.loyaltyService.QuoteRedemption(passengers[i].passenger.ID, requests[i].RedeemPoints, ticketPrice)
+				if err != nil {
+					return err
+				}
+				ticketPrice -= discount
+			}
+
+			tickets[i].SeatID = seat.ID
+			tickets[i].Price = ticketPrice
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 6. Return the response
-	return &dto.TicketResponse{
-		ID:           createdTicket.ID,
-		FlightCode:   flight.FlightCode,
-		SeatNumber:   seat.SeatNumber,
-		Price:        createdTicket.Price,
-		FullName:     createdTicket.FullName,
-		IDCard:       createdTicket.IDCard,
-		PhoneNumber:  createdTicket.PhoneNumber,
-		Email:        createdTicket.Email,
-		TicketStatus: createdTicket.TicketStatus,
-		BookingType:  createdTicket.BookingType,
-	}, nil
+	// 7. Debit the redeemed points
+	redeemed := make([]bool, len(tickets))
+	for i, ticket := range requests {
+		if ticket.RedeemPoints == 0 {
+			continue
+		}
+		if err := t.loyaltyService.RedeemForTicket(passengers[i].passenger.ID, tickets[i].ID, ticket.RedeemPoints); err != nil {
+			// The points could not be debited, so the discounted booking must not stand
+			if undoErr := t.abandonBooking(tickets, redeemed); undoErr != nil {
+				return nil, undoErr
+			}
+			return nil, err
+		}
+		redeemed[i] = true
+	}
+
+	// 8. Return the responses
+	responses := make([]*dto.TicketResponse, len(tickets))
+	for i, createdTicket := range tickets {
+		responses[i] = &dto.TicketResponse{
+			ID:               createdTicket.ID,
+			FlightCode:       flight.FlightCode,
+			SeatNumber:       assigned[i].SeatNumber,
+			Price:            createdTicket.Price,
+			FullName:         createdTicket.FullName,
+			IDCard:           createdTicket.IDCard,
+			PhoneNumber:      createdTicket.PhoneNumber,
+			Email:            createdTicket.Email,
+			TicketStatus:     createdTicket.TicketStatus,
+			BookingType:      createdTicket.BookingType,
+			PassengerID:      createdTicket.PassengerID,
+			BookingReference: createdTicket.BookingReference,
+		}
+	}
+	return responses, nil
+}
+
+// abandonBooking undoes a booking whose tickets were created but could not be paid for.
+// Tickets that already debited points are cancelled, which returns the points on the
+// ledger; the others are deleted.
+func (t *ticketService) abandonBooking(tickets []*models.Ticket, redeemed []bool) error {
+	for i, ticket := range tickets {
+		if !redeemed[i] {
+			if err := t.ticketRepo.Delete(ticket.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := t.ticketRepo.UpdateTicketStatus(ticket.ID, models.TicketStatusCancelled); err != nil {
+			return err
+		}
+		ticket.TicketStatus = models.TicketStatusCancelled
+		if err := t.syncLoyaltyPoints(ticket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newBookingReference returns a random six-character reference shared by the tickets of
+// one booking. Letters and digits that are easily confused are left out.
+func newBookingReference() (string, error) {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	buf := make([]byte, bookingReferenceLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", exceptions.InternalError("failed to generate booking reference", err)
+	}
+	for i, b := range buf {
+		buf[i] = alphabet[int(b)%len(alphabet)]
+	}
+	return string(buf), nil
+}
+
+// validateTravelDocuments checks the documents a passenger needs for the flight. Domestic
+// flights need a national ID valid for the country of travel. International flights also
+// need a passport that stays valid for six months after departure. Saved passengers may
+// omit the passport when their profile already holds one.
+func (t *ticketService) validateTravelDocuments(flight *models.Flight, ticket *dto.TicketRequest) (*models.TravelDocument, error) {
+	fieldErrors := validator.FieldErrors{}
+	idCountry := airportCountry(&flight.DepartureAirport)
+
+	var passport *models.TravelDocument
+	if isInternationalFlight(flight) {
+		var err error
+		passport, err = t.passportForTicket(ticket, fieldErrors)
+		if err != nil {
+			return nil, err
+		}
+		if passport != nil {
+			idCountry = passport.IssuingCountry
+			minExpiry := flight.DepartureDateTime.AddDate(0, passportValidityMonths, 0)
+			if passport.ExpiryDate != nil && passport.ExpiryDate.Before(minExpiry) {
+				fieldErrors["passport.expiry_date"] = fmt.Sprintf("Passport must be valid until at least %s", minExpiry.Format(time.DateOnly))
+			}
+		}
+	}
+
+	// ID cards taken from a saved profile were checked when they were recorded
+	if ticket.IDCard != "" {
+		if err := validator.ValidateNationalID(idCountry, strings.TrimSpace(ticket.IDCard)); err != nil {
+			fieldErrors["id_card"] = err.Error()
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, exceptions.NewAppError(exceptions.BadRequest, "Travel document validation failed", validator.ValidationErrors(fieldErrors))
+	}
+	return passport, nil
+}
+
+// passportForTicket reads the passport from the request, falling back to the passport with
+// the latest expiry on the saved passenger profile. Missing fields are recorded in fieldErrors.
+func (t *ticketService) passportForTicket(ticket *dto.TicketRequest, fieldErrors validator.FieldErrors) (*models.TravelDocument, error) {
+	if ticket.Passport == nil && ticket.PassengerID != nil {
+		passenger, err := t.passengerRepo.GetByID(*ticket.PassengerID)
+		if err != nil {
+			return nil, err
+		}
+		var saved *models.TravelDocument
+		for i, document := range passenger.Documents {
+			if document.DocumentType != models.DocumentTypePassport || document.ExpiryDate == nil {
+				continue
+			}
+			if saved == nil || document.ExpiryDate.After(*saved.ExpiryDate) {
+				saved = &passenger.Documents[i]
+			}
+		}
+		if saved != nil {
+			return saved, nil
+		}
+	}
+
+	if ticket.Passport == nil {
+		fieldErrors["passport"] = "Passport details are required for international flights"
+		return nil, nil
+	}
+
+	passport := &models.TravelDocument{
+		DocumentType:   models.DocumentTypePassport,
+		DocumentNumber: strings.ToUpper(strings.TrimSpace(ticket.Passport.Number)),
+		IssuingCountry: strings.TrimSpace(ticket.Passport.Nationality),
+	}
+	if passport.DocumentNumber == "" {
+		fieldErrors["passport.number"] = "This field is required"
+	}
+	if passport.IssuingCountry == "" {
+		fieldErrors["passport.nationality"] = "This field is required"
+	}
+	if ticket.Passport.ExpiryDate == "" {
+		fieldErrors["passport.expiry_date"] = "This field is required"
+	} else if expiryDate, err := time.Parse(time.DateOnly, ticket.Passport.ExpiryDate); err != nil {
+		fieldErrors["passport.expiry_date"] = "Invalid date, expected YYYY-MM-DD"
+	} else {
+		passport.ExpiryDate = &expiryDate
+	}
+	return passport, nil
+}
+
+// addPassport adds the passport used for a booking to the passenger's profile. Passports taken
+// from the profile are already on it.
+func (t *ticketService) addPassport(passenger *bookingPassenger, passport *models.TravelDocument) error {
+	if passport.ID != 0 {
+		return nil
+	}
+	for _, added := range passenger.passports {
+		if added.DocumentNumber == passport.DocumentNumber {
+			return nil
+		}
+	}
+
+	owner, err := t.passengerRepo.GetByDocument(passport.DocumentType, encryption.GetKeyring().BlindIndex(passport.DocumentNumber))
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if owner != nil {
+		if owner.ID != passenger.passenger.ID {
+			return exceptions.NewAppError(exceptions.BadRequest, "Travel document validation failed", validator.ValidationErrors(validator.FieldErrors{
+				"passport.number": "Passport belongs to another passenger",
+			}))
+		}
+		for _, document := range owner.Documents {
+			if document.DocumentType == passport.DocumentType && document.DocumentNumber == passport.DocumentNumber {
+				passport.ID = document.ID
+				passport.CreatedAt = document.CreatedAt
+			}
+		}
+	}
+
+	passenger.passports = append(passenger.passports, passport)
+	if passenger.passenger.Nationality == "" {
+		passenger.passenger.Nationality = passport.IssuingCountry
+		passenger.changed = true
+	}
+	return nil
+}
+
+// isInternationalFlight reports whether any airport on the itinerary lies in a different
+// country from the departure airport.
+func isInternationalFlight(flight *models.Flight) bool {
+	if !sameCountry(&flight.DepartureAirport, &flight.ArrivalAirport) {
+		return true
+	}
+	for _, stop := range flight.IntermediateStops {
+		if !sameCountry(&flight.DepartureAirport, &stop.Airport) {
+			return true
+		}
+	}
+	return false
+}
+
+// airportCountry identifies the airport's country by its ISO code, or by its name for
+// airports entered without a code.
+func airportCountry(airport *models.Airport) string {
+	if airport.CountryCode != "" {
+		return airport.CountryCode
+	}
+	return airport.CountryName
+}
+
+// sameCountry compares the ISO codes of two airports, and their names when either lacks one.
+func sameCountry(a, b *models.Airport) bool {
+	if a.CountryCode != "" && b.CountryCode != "" {
+		return a.CountryCode == b.CountryCode
+	}
+	return strings.EqualFold(strings.TrimSpace(a.CountryName), strings.TrimSpace(b.CountryName))
+}
+
+// bookingPassenger is the profile a booking issues tickets to, with the changes the booking
+// makes to it. Nothing is written until the booking transaction saves it.
+type bookingPassenger struct {
+	passenger *models.Passenger // ID is zero for a first-time traveler
+	changed   bool
+	passports []*models.TravelDocument
+}
+
+// save writes the profile, its changes and its new passports with the booking transaction.
+func (b *bookingPassenger) save(tx *gorm.DB) error {
+	if b.passenger.ID == 0 {
+		if err := tx.Create(b.passenger).Error; err != nil {
+			return exceptions.InternalError("failed to create passenger", err)
+		}
+	} else if b.changed {
+		if err := tx.Omit("Documents", "Tickets").Save(b.passenger).Error; err != nil {
+			return exceptions.InternalError("failed to update passenger", err)
+		}
+	}
+	b.changed = false
+	for _, passport := range b.passports {
+		passport.PassengerID = b.passenger.ID
+		if err := tx.Save(passport).Error; err != nil {
+			return exceptions.InternalError("failed to save travel document", err)
+		}
+	}
+	b.passports = nil
+	return nil
+}
+
+// resolvePassenger finds the passenger profile for a ticket request, preparing a new one keyed
+// by the ID card number for first-time travelers. Missing contact details on the request are
+// filled in from the saved profile, and newer details on the request update the profile.
+// Requests of one booking for the same traveler share the profile in profiles, keyed by the
+// blind index of the ID card.
+func (t *ticketService) resolvePassenger(ticket *dto.TicketRequest, profiles map[string]*bookingPassenger) (*bookingPassenger, error) {
+	var passenger *models.Passenger
+	var err error
+
+	ticket.IDCard = strings.ToUpper(strings.TrimSpace(ticket.IDCard))
+	if ticket.PassengerID != nil {
+		passenger, err = t.passengerRepo.GetByID(*ticket.PassengerID)
+		if err != nil {
+			return nil, err
+		}
+		if ticket.IDCard == "" {
+			ticket.IDCard = primaryDocumentNumber(passenger)
+		} else if !holdsDocument(passenger, ticket.IDCard) {
+			return nil, exceptions.NewAppError(exceptions.BadRequest, "Travel document validation failed", validator.ValidationErrors(validator.FieldErrors{
+				"id_card": "ID card does not match the passenger's travel documents",
+			}))
+		}
+		if ticket.IDCard == "" {
+			return nil, exceptions.BadRequestError("passenger has no travel document on file, id_card is required", nil)
+		}
+	}
+
+	key := encryption.GetKeyring().BlindIndex(ticket.IDCard)
+	resolved, ok := profiles[key]
+	if !ok {
+		if passenger == nil {
+			passenger, err = t.passengerRepo.GetByDocument(models.DocumentTypeIDCard, key)
+			if err != nil && !isNotFound(err) {
+				return nil, err
+			}
+		}
+		if passenger == nil {
+			passenger = &models.Passenger{
+				FullName:    ticket.FullName,
+				PhoneNumber: ticket.PhoneNumber,
+				Email:       ticket.Email,
+				Documents: []models.TravelDocument{{
+					DocumentType:   models.DocumentTypeIDCard,
+					DocumentNumber: ticket.IDCard,
+				}},
+			}
+		}
+		resolved = &bookingPassenger{passenger: passenger}
+		profiles[key] = resolved
+	}
+	passenger = resolved.passenger
+
+	if ticket.FullName == "" {
+		ticket.FullName = passenger.FullName
+	}
+	if ticket.PhoneNumber == "" {
+		ticket.PhoneNumber = passenger.PhoneNumber
+	}
+	if ticket.Email == "" {
+		ticket.Email = passenger.Email
+	}
+	if ticket.PhoneNumber != passenger.PhoneNumber || ticket.Email != passenger.Email {
+		passenger.PhoneNumber = ticket.PhoneNumber
+		passenger.Email = ticket.Email
+		resolved.changed = true
+	}
+	return resolved, nil
+}
+
+// holdsDocument reports whether one of the passenger's travel documents has the number.
+func holdsDocument(passenger *models.Passenger, number string) bool {
+	for _, document := range passenger.Documents {
+		if strings.EqualFold(document.DocumentNumber, number) {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryDocumentNumber returns the passenger's ID card number, falling back to a passport.
+func primaryDocumentNumber(passenger *models.Passenger) string {
+	number := ""
+	for _, document := range passenger.Documents {
+		switch document.DocumentType {
+		case models.DocumentTypeIDCard:
+			return document.DocumentNumber
+		case models.DocumentTypePassport:
+			number = document.DocumentNumber
+		}
+	}
+	return number
 }
 
 func (t *ticketService) ConvertPlaceOrderToTicket(placeOrderID uint) (*dto.TicketResponse, error) {
@@ -158,11 +686,13 @@ func (t *ticketService) ConvertPlaceOrderToTicket(placeOrderID uint) (*dto.Ticke
 		return nil, err
 	}
 
-	// 4. Get parameters for timing validation
+	// 4. Get the parameters in force on the flight now for timing validation, so that a
+	// parameter change applies to place orders still unpaid
 	params, err := t.paramRepo.GetAllParams()
 	if err != nil {
 		return nil, err
 	}
+	params = flightParameters(params, flight)
 
 	// 5. Check if conversion is within an allowed time window
 	daysBefore := time.Duration(params.LatestTicketPurchaseTime) * 24 * time.Hour
@@ -182,16 +712,18 @@ func (t *ticketService) ConvertPlaceOrderToTicket(placeOrderID uint) (*dto.Ticke
 
 	// 7. Return the response
 	return &dto.TicketResponse{
-		ID:           updatedTicket.ID,
-		FlightCode:   flight.FlightCode,
-		SeatNumber:   updatedTicket.Seat.SeatNumber,
-		Price:        updatedTicket.Price,
-		FullName:     updatedTicket.FullName,
-		IDCard:       updatedTicket.IDCard,
-		PhoneNumber:  updatedTicket.PhoneNumber,
-		Email:        updatedTicket.Email,
-		TicketStatus: updatedTicket.TicketStatus,
-		BookingType:  updatedTicket.BookingType,
+		ID:               updatedTicket.ID,
+		FlightCode:       flight.FlightCode,
+		SeatNumber:       updatedTicket.Seat.SeatNumber,
+		Price:            updatedTicket.Price,
+		FullName:         updatedTicket.FullName,
+		IDCard:           updatedTicket.IDCard,
+		PhoneNumber:      updatedTicket.PhoneNumber,
+		Email:            updatedTicket.Email,
+		TicketStatus:     updatedTicket.TicketStatus,
+		BookingType:      updatedTicket.BookingType,
+		PassengerID:      updatedTicket.PassengerID,
+		BookingReference: updatedTicket.BookingReference,
 	}, nil
 }
 
@@ -214,6 +746,10 @@ func (t *ticketService) CancelPlaceOrders(flightCode string) error {
 			if err := t.ticketRepo.UpdateTicketStatus(ticket.ID, models.TicketStatusExpired); err != nil {
 				return err
 			}
+			ticket.TicketStatus = models.TicketStatusExpired
+			if err := t.syncLoyaltyPoints(ticket); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -235,11 +771,12 @@ func (t *ticketService) UpdateTicketStatus(ticketID uint, newStatus models.Ticke
 			return nil, err
 		}
 
-		// Get parameters for cancellation time
+		// Get the cancellation time in force on the flight now
 		params, err := t.paramRepo.GetAllParams()
 		if err != nil {
 			return nil, err
 		}
+		params = flightParameters(params, flight)
 
 		// Calculate cancellation deadline
 		daysBefore := time.Duration(params.TicketCancellationTime) * 24 * time.Hour
@@ -272,21 +809,42 @@ func (t *ticketService) UpdateTicketStatus(ticketID uint, newStatus models.Ticke
 		return nil, err
 	}
 
+	// Keep the passenger's loyalty points in step with the ticket
+	if err := t.syncLoyaltyPoints(updatedTicket); err != nil {
+		return nil, err
+	}
+
 	// Return response
 	return &dto.TicketResponse{
-		ID:           updatedTicket.ID,
-		FlightCode:   updatedTicket.Flight.FlightCode,
-		SeatNumber:   updatedTicket.Seat.SeatNumber,
-		Price:        updatedTicket.Price,
-		FullName:     updatedTicket.FullName,
-		IDCard:       updatedTicket.IDCard,
-		PhoneNumber:  updatedTicket.PhoneNumber,
-		Email:        updatedTicket.Email,
-		TicketStatus: updatedTicket.TicketStatus,
-		BookingType:  updatedTicket.BookingType,
+		ID:               updatedTicket.ID,
+		FlightCode:       updatedTicket.Flight.FlightCode,
+		SeatNumber:       updatedTicket.Seat.SeatNumber,
+		Price:            updatedTicket.Price,
+		FullName:         updatedTicket.FullName,
+		IDCard:           updatedTicket.IDCard,
+		PhoneNumber:      updatedTicket.PhoneNumber,
+		Email:            updatedTicket.Email,
+		TicketStatus:     updatedTicket.TicketStatus,
+		BookingType:      updatedTicket.BookingType,
+		PassengerID:      updatedTicket.PassengerID,
+		BookingReference: updatedTicket.BookingReference,
 	}, nil
 }
 
+// syncLoyaltyPoints accrues points once a ticket has been used, and reverses them when a
+// ticket is refunded. Points redeemed on a ticket that will not be flown are returned.
+func (t *ticketService) syncLoyaltyPoints(ticket *models.Ticket) error {
+	switch ticket.TicketStatus {
+	case models.TicketStatusUsed:
+		return t.loyaltyService.AccrueForTicket(ticket)
+	case models.TicketStatusRefunded:
+		return t.loyaltyService.ReverseForTicket(ticket, true)
+	case models.TicketStatusCancelled, models.TicketStatusExpired:
+		return t.loyaltyService.ReverseForTicket(ticket, false)
+	}
+	return nil
+}
+
 func (t *ticketService) DeleteTicket(id uint) error {
 	// Validate ticket exists
 	_, err := t.ticketRepo.GetByID(id)
@@ -317,11 +875,13 @@ func (t *ticketService) GetBookingTypes() []models.BookingType {
 	}
 }
 
-func NewTicketService(ticketRepo repository.TicketRepository, flightRepo repository.FlightRepository, planeRepo repository.PlaneRepository, paramRepo repository.ParameterRepository) TicketService {
+func NewTicketService(ticketRepo repository.TicketRepository, flightRepo repository.FlightRepository, planeRepo repository.PlaneRepository, paramRepo repository.ParameterRepository, passengerRepo repository.PassengerRepository, loyaltyService LoyaltyService) TicketService {
 	return &ticketService{
-		ticketRepo: ticketRepo,
-		flightRepo: flightRepo,
-		planeRepo:  planeRepo,
-		paramRepo:  paramRepo,
+		ticketRepo:     ticketRepo,
+		flightRepo:     flightRepo,
+		planeRepo:      planeRepo,
+		paramRepo:      paramRepo,
+		passengerRepo:  passengerRepo,
+		loyaltyService: loyaltyService,
 	}
 }
@@ -1,30 +1,88 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aprilboiz/flight-management/internal/dto"
+	"github.com/aprilboiz/flight-management/internal/exceptions"
 	"github.com/aprilboiz/flight-management/internal/models"
 	"github.com/aprilboiz/flight-management/internal/repository"
 	"github.com/aprilboiz/flight-management/pkg/auth"
+	"github.com/aprilboiz/flight-management/pkg/config"
+	"github.com/aprilboiz/flight-management/pkg/mail"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserExists         = errors.New("user already exists")
+	ErrUserDisabled       = errors.New("user is disabled")
+
+	ErrRegistrationClosed    = errors.New("registration is closed")
+	ErrInvitationRequired    = errors.New("an invitation is required to register")
+	ErrEmailDomainNotAllowed = errors.New("email domain is not allowed to register")
+	ErrInvalidInvitation     = errors.New("invitation is invalid, expired or already used")
+
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid, expired or revoked")
+)
+
+const defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// Invitation statuses
+const (
+	invitationPending = "PENDING"
+	invitationUsed    = "USED"
+	invitationExpired = "EXPIRED"
 )
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo          repository.UserRepository
+	invitationRepo    repository.InvitationRepository
+	sessionRepo       repository.SessionRepository
+	roleRepo          repository.RoleRepository
+	loginThrottleRepo repository.LoginThrottleRepository
+	auditRepo         repository.AuditRepository
+	passwordResetRepo repository.PasswordResetRepository
+	mailer            mail.Mailer
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository, invitationRepo repository.InvitationRepository, sessionRepo repository.SessionRepository, roleRepo repository.RoleRepository, loginThrottleRepo repository.LoginThrottleRepository, auditRepo repository.AuditRepository, passwordResetRepo repository.PasswordResetRepository, mailer mail.Mailer) UserService {
+	if userRepo == nil || invitationRepo == nil || sessionRepo == nil || roleRepo == nil || loginThrottleRepo == nil || auditRepo == nil || passwordResetRepo == nil || mailer == nil {
+		panic("Missing required repositories for user service")
+	}
+	return &userService{
+		userRepo:          userRepo,
+		invitationRepo:    invitationRepo,
+		sessionRepo:       sessionRepo,
+		roleRepo:          roleRepo,
+		loginThrottleRepo: loginThrottleRepo,
+		auditRepo:         auditRepo,
+		passwordResetRepo: passwordResetRepo,
+		mailer:            mailer,
+	}
 }
 
+// Register creates an account according to the registration mode. An invitation gives its
+// preassigned role and is used up; in domain mode, an email in an allowed domain is enough to
+// register as staff. The password must meet the password policy.
 func (s *userService) Register(req dto.RegisterRequest) (*dto.AuthResponse, error) {
+	registration := config.GetConfig().Security.Registration
+	if registration.Mode == "" || registration.Mode == config.RegistrationDisabled {
+		return nil, ErrRegistrationClosed
+	}
+
 	// Check if user already exists
 	if _, err := s.userRepo.GetByUsername(req.Username); err == nil {
 		return nil, ErrUserExists
@@ -32,64 +90,596 @@ func (s *userService) Register(req dto.RegisterRequest) (*dto.AuthResponse, erro
 	if _, err := s.userRepo.GetByEmail(req.Email); err == nil {
 		return nil, ErrUserExists
 	}
+	if err := checkPasswordPolicy(req.Password, req.Username, req.Email); err != nil {
+		return nil, err
+	}
 
 	user := &models.User{
 		Username: req.Username,
 		Password: req.Password,
 		Email:    req.Email,
-		Role:     models.RoleStaff, // Default role
+		Role:     models.RoleStaff,
 	}
 
-	if err := user.HashPassword(); err != nil {
-		return nil, err
+	var invitation *models.Invitation
+	switch {
+	case req.InvitationToken != "":
+		var err error
+		invitation, err = s.pendingInvitation(req.InvitationToken)
+		if err != nil {
+			return nil, err
+		}
+		if invitation.Email != "" && !strings.EqualFold(invitation.Email, req.Email) {
+			return nil, ErrInvalidInvitation
+		}
+		// The role may have been deleted since the invitation was issued
+		if _, err := s.roleRepo.GetByName(invitation.Role); err != nil {
+			if isNotFound(err) {
+				return nil, ErrInvalidInvitation
+			}
+			return nil, err
+		}
+		user.Role = invitation.Role
+	case registration.Mode == config.RegistrationDomain:
+		if !emailInDomains(req.Email, registration.AllowedDomains) {
+			return nil, ErrEmailDomainNotAllowed
+		}
+	default:
+		return nil, ErrInvitationRequired
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := user.HashPassword(); err != nil {
 		return nil, err
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.Username, user.Role)
-	if err != nil {
+	if invitation != nil {
+		redeemed, err := s.invitationRepo.Redeem(invitation, user)
+		if err != nil {
+			return nil, err
+		}
+		if !redeemed {
+			return nil, ErrInvalidInvitation
+		}
+	} else if err := s.userRepo.Create(user); err != nil {
 		return nil, err
 	}
 
-	return &dto.AuthResponse{
-		Token: token,
-		User: dto.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Role:      user.Role,
-			CreatedAt: user.CreatedAt.Format(time.RFC3339),
-			UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
-		},
-	}, nil
+	return s.startSession(user)
 }
 
-func (s *userService) Login(req dto.LoginRequest) (*dto.AuthResponse, error) {
+// Login checks a password, throttling failed attempts per username and per IP address. Unknown
+// usernames are throttled like known ones, so the errors do not tell whether a username exists.
+func (s *userService) Login(req dto.LoginRequest, ipAddress string) (*dto.AuthResponse, error) {
+	userKey, ipKey := usernameThrottleKey(req.Username), ipThrottleKey(ipAddress)
+	if err := s.checkLoginThrottle(userKey, ipKey); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByUsername(req.Username)
 	if err != nil {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(req.Password))
+		if err := s.recordLoginFailure(userKey, ipKey, req.Username, ipAddress); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
 
 	if err := user.CheckPassword(req.Password); err != nil {
+		if err := s.recordLoginFailure(userKey, ipKey, req.Username, ipAddress); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
+	if _, err := s.loginThrottleRepo.Reset(userKey); err != nil {
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	return s.startSession(user)
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token. Each refresh
+// token works once: presenting a used one again means it leaked, and the session is revoked.
+func (s *userService) Refresh(req dto.RefreshRequest) (*dto.AuthResponse, error) {
+	used, err := s.sessionRepo.GetRefreshToken(hashToken(req.RefreshToken))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	session := used.Session
+	if session == nil || session.RevokedAt != nil || session.User == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if used.UsedAt != nil {
+		zap.L().Warn("Refresh token reused, revoking the session",
+			zap.Uint("session_id", session.ID), zap.String("username", session.User.Username))
+		if err := s.sessionRepo.Revoke(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(used.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	user := session.User
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	refreshToken, next, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.sessionRepo.Rotate(used, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		if err := s.sessionRepo.Revoke(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	return authResponse(user, session.ID, refreshToken, next)
+}
+
+// Logout revokes the session the caller's access token belongs to.
+func (s *userService) Logout(sessionID uint) error {
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// LogoutAll revokes every session of a user, on every device.
+func (s *userService) LogoutAll(userID uint) error {
+	_, err := s.sessionRepo.RevokeAllForUser(userID)
+	return err
+}
+
+// ValidateAccessToken checks that a valid access token has not been revoked since it was issued:
+// its session is still open, and its user still exists, is enabled and has the same role. A
+// token issued before a role change must be refreshed to carry the new role.
+func (s *userService) ValidateAccessToken(claims *auth.Claims) error {
+	session, err := s.sessionRepo.GetActive(claims.SessionID)
+	if err != nil {
+		if isNotFound(err) {
+			return exceptions.NewAppError(exceptions.UNAUTHORIZED, "Token has been revoked", nil)
+		}
+		return err
+	}
+	user := session.User
+	if user == nil || user.ID != claims.UserID || user.DisabledAt != nil || user.Role != claims.Role {
+		return exceptions.NewAppError(exceptions.UNAUTHORIZED, "Token has been revoked", nil)
+	}
+	return nil
+}
+
+// startSession opens a session for a user who just authenticated.
+func (s *userService) startSession(user *models.User) (*dto.AuthResponse, error) {
+	refreshToken, first, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	session := &models.Session{UserID: user.ID}
+	if err := s.sessionRepo.Create(session, first); err != nil {
+		return nil, err
+	}
+	return authResponse(user, session.ID, refreshToken, first)
+}
+
+func (s *userService) ListUsers(query *dto.UserSearchQuery) ([]*dto.UserResponse, error) {
+	users, err := s.userRepo.Search(query.Search, query.Role)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = toUserResponse(user)
+	}
+	return responses, nil
+}
+
+// GetMe describes the caller with the permissions of their role.
+func (s *userService) GetMe(id uint) (*dto.MeResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := rolePermissions(s.roleRepo, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MeResponse{User: *toUserResponse(user), Permissions: permissions}, nil
+}
+
+func (s *userService) GetUser(id uint) (*dto.UserResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return toUserResponse(user), nil
+}
+
+// CreateUser adds a user with the requested role, for admins setting up accounts. The password
+// must meet the password policy. Only super admins can create super admins, and others only
+// users of roles whose permissions they hold.
+func (s *userService) CreateUser(req *dto.CreateUserRequest, callerRole models.Role) (*dto.UserResponse, error) {
+	if err := s.requireRole(req.Role); err != nil {
+		return nil, err
+	}
+	if err := requireAssignableRole(s.roleRepo, callerRole, req.Role); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.GetByUsername(req.Username); err == nil {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT, "User already exists", nil)
+	}
+	if _, err := s.userRepo.GetByEmail(req.Email); err == nil {
+		return nil, exceptions.NewAppError(exceptions.CONFLICT, "User already exists", nil)
+	}
+	if err := checkPasswordPolicy(req.Password, req.Username, req.Email); err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username: req.Username,
+		Password: req.Password,
+		Email:    req.Email,
+		Role:     req.Role,
+	}
+	if err := user.HashPassword(); err != nil {
+		return nil, exceptions.InternalError("failed to hash password", err)
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		var appErr *exceptions.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, exceptions.InternalError("failed to create user", err)
+	}
+	return toUserResponse(user), nil
+}
+
+// ChangeRole gives a user another role. The last enabled super admin cannot be demoted. Callers
+// who are not super admins can only move users between roles whose permissions they hold.
+func (s *userService) ChangeRole(id uint, role, callerRole models.Role) (*dto.UserResponse, error) {
+	if err := s.requireRole(role); err != nil {
+		return nil, err
+	}
+	if err := requireAssignableRole(s.roleRepo, callerRole, role); err != nil {
+		return nil, err
+	}
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAssignableRole(s.roleRepo, callerRole, user.Role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+	if err := s.userRepo.UpdateKeepingSuperAdmin(user); err != nil {
+		return nil, err
+	}
+	return toUserResponse(user), nil
+}
+
+// DisableUser stops a user from logging in. The last enabled super admin cannot be disabled.
+// Here and in EnableUser, UnlockUser and DeleteUser the caller must be able to assign the user's role.
+func (s *userService) DisableUser(id uint, callerRole models.Role) (*dto.UserResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAssignableRole(s.roleRepo, callerRole, user.Role); err != nil {
+		return nil, err
+	}
+	if user.DisabledAt == nil {
+		now := time.Now()
+		user.DisabledAt = &now
+		if err := s.userRepo.UpdateKeepingSuperAdmin(user); err != nil {
+			return nil, err
+		}
+		if _, err := s.sessionRepo.RevokeAllForUser(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return toUserResponse(user), nil
+}
+
+func (s *userService) EnableUser(id uint, callerRole models.Role) (*dto.UserResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAssignableRole(s.roleRepo, callerRole, user.Role); err != nil {
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		user.DisabledAt = nil
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, exceptions.InternalError("failed to update user", err)
+		}
+	}
+	return toUserResponse(user), nil
+}
+
+// UnlockUser lifts the lockout of a user after failed logins and forgets their failures.
+func (s *userService) UnlockUser(id uint, callerRole models.Role, actor, ipAddress string) (*dto.UserResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAssignableRole(s.roleRepo, callerRole, user.Role); err != nil {
+		return nil, err
+	}
+	key := usernameThrottleKey(user.Username)
+	throttled, err := s.loginThrottleRepo.Reset(key)
+	if err != nil {
+		return nil, err
+	}
+	if throttled {
+		if err := s.auditRepo.Create(&models.AuditLog{
+			Action:    models.AuditAccountUnlock,
+			Actor:     actor,
+			Subject:   key,
+			IPAddress: ipAddress,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return toUserResponse(user), nil
+}
+
+// DeleteUser removes a user. The last enabled super admin cannot be deleted.
+func (s *userService) DeleteUser(id uint, callerRole models.Role) error {
+	user, err := s.getUser(id)
+	if err != nil {
+		return err
+	}
+	if err := requireAssignableRole(s.roleRepo, callerRole, user.Role); err != nil {
+		return err
+	}
+	if err := s.userRepo.DeleteKeepingSuperAdmin(user); err != nil {
+		return err
+	}
+	_, err = s.sessionRepo.RevokeAllForUser(user.ID)
+	return err
+}
+
+// BootstrapSuperAdmin creates the configured super admin on first run, when no enabled super
+// admin exists. BOOTSTRAP_ADMIN_PASSWORD takes precedence over the configured password, which
+// must meet the password policy.
+func (s *userService) BootstrapSuperAdmin() error {
+	cfg := config.GetConfig().Security.BootstrapAdmin
+	password := cfg.Password
+	if env := os.Getenv("BOOTSTRAP_ADMIN_PASSWORD"); env != "" {
+		password = env
+	}
+	if cfg.Username == "" || password == "" {
+		return nil
+	}
+
+	superAdmins, err := s.userRepo.CountEnabledByRole(models.RoleSuperAdmin)
+	if err != nil {
+		return err
+	}
+	if superAdmins > 0 {
+		return nil
+	}
+	if _, err := s.CreateUser(&dto.CreateUserRequest{
+		Username: cfg.Username,
+		Password: password,
+		Email:    cfg.Email,
+		Role:     models.RoleSuperAdmin,
+	}, models.RoleSuperAdmin); err != nil {
+		return err
+	}
+	zap.L().Info("Created the initial super admin", zap.String("username", cfg.Username))
+	return nil
+}
+
+// CreateInvitation issues a single-use invitation for the requested role. The token is only
+// returned here. Only super admins can invite super admins, and others can only invite into
+// roles whose permissions they hold themselves.
+func (s *userService) CreateInvitation(req *dto.InvitationRequest, issuer string, issuerRole models.Role) (*dto.InvitationResponse, error) {
+	if err := s.requireRole(req.Role); err != nil {
+		return nil, err
+	}
+	if err := requireAssignableRole(s.roleRepo, issuerRole, req.Role); err != nil {
+		return nil, err
+	}
+	ttl := req.ExpiresInHours
+	if ttl == 0 {
+		ttl = config.GetConfig().Security.Registration.InvitationTTLHours
+	}
+	if ttl <= 0 {
+		return nil, exceptions.BadRequestError("expires_in_hours is required, no default validity is configured", nil)
+	}
+
+	encodedToken, err := randomToken()
+	if err != nil {
+		return nil, exceptions.InternalError("failed to generate invitation token", err)
+	}
 
-	token, err := auth.GenerateToken(user.ID, user.Username, user.Role)
+	invitation := &models.Invitation{
+		TokenHash: hashToken(encodedToken),
+		Email:     strings.TrimSpace(req.Email),
+		Role:      req.Role,
+		CreatedBy: issuer,
+		ExpiresAt: time.Now().Add(time.Duration(ttl) * time.Hour),
+	}
+	if _, err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+	response := toInvitationResponse(invitation)
+	response.Token = encodedToken
+	return response, nil
+}
+
+func (s *userService) ListInvitations() ([]*dto.InvitationResponse, error) {
+	invitations, err := s.invitationRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = toInvitationResponse(invitation)
+	}
+	return responses, nil
+}
+
+// RevokeInvitation withdraws an invitation that has not been used.
+func (s *userService) RevokeInvitation(id uint) error {
+	invitation, err := s.invitationRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if invitation.UsedAt != nil {
+		return exceptions.NewAppError(exceptions.CONFLICT, "invitation has already been used", nil)
+	}
+	return s.invitationRepo.Delete(invitation)
+}
+
+// pendingInvitation returns the invitation of a token if it can still be redeemed.
+func (s *userService) pendingInvitation(token string) (*models.Invitation, error) {
+	invitation, err := s.invitationRepo.GetByTokenHash(hashToken(token))
 	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrInvalidInvitation
+		}
 		return nil, err
 	}
+	if invitationStatus(invitation) != invitationPending {
+		return nil, ErrInvalidInvitation
+	}
+	return invitation, nil
+}
+
+// requireRole checks that a role exists before users are given it.
+func (s *userService) requireRole(role models.Role) error {
+	if _, err := s.roleRepo.GetByName(role); err != nil {
+		if isNotFound(err) {
+			return exceptions.BadRequestError(fmt.Sprintf("role '%s' does not exist", role), nil)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *userService) getUser(id uint) (*models.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NotFoundError("user", strconv.FormatUint(uint64(id), 10))
+		}
+		return nil, exceptions.InternalError("failed to get user", err)
+	}
+	return user, nil
+}
 
+func toUserResponse(user *models.User) *dto.UserResponse {
+	response := &dto.UserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+	}
+	if user.DisabledAt != nil {
+		response.DisabledAt = user.DisabledAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+func toInvitationResponse(invitation *models.Invitation) *dto.InvitationResponse {
+	response := &dto.InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		Status:    invitationStatus(invitation),
+		CreatedBy: invitation.CreatedBy,
+		CreatedAt: invitation.CreatedAt.Format(time.RFC3339),
+		ExpiresAt: invitation.ExpiresAt.Format(time.RFC3339),
+	}
+	if invitation.UsedAt != nil {
+		response.UsedAt = invitation.UsedAt.Format(time.RFC3339)
+	}
+	if invitation.UsedBy != nil {
+		response.UsedBy = invitation.UsedBy.Username
+	}
+	return response
+}
+
+func invitationStatus(invitation *models.Invitation) string {
+	switch {
+	case invitation.UsedAt != nil:
+		return invitationUsed
+	case time.Now().After(invitation.ExpiresAt):
+		return invitationExpired
+	default:
+		return invitationPending
+	}
+}
+
+// hashToken returns the hex SHA-256 of a token, the form in which invitation and refresh
+// tokens are stored.
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// emailInDomains reports whether the domain of an email is one of domains, ignoring case.
+func emailInDomains(email string, domains []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range domains {
+		if strings.EqualFold(domain, strings.TrimPrefix(strings.TrimSpace(allowed), "@")) {
+			return true
+		}
+	}
+	return false
+}
+
+// randomToken returns 32 random bytes encoded for use in URLs and JSON.
+func randomToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// newRefreshToken returns a refresh token and the record to store for it.
+func newRefreshToken() (string, *models.RefreshToken, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", nil, exceptions.InternalError("failed to generate refresh token", err)
+	}
+	ttl := defaultRefreshTokenTTL
+	if hours := config.GetConfig().Security.Tokens.RefreshTokenHours; hours > 0 {
+		ttl = time.Duration(hours) * time.Hour
+	}
+	return token, &models.RefreshToken{
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+func authResponse(user *models.User, sessionID uint, refreshToken string, stored *models.RefreshToken) (*dto.AuthResponse, error) {
+	token, err := auth.GenerateToken(user.ID, user.Username, user.Role, sessionID)
+	if err != nil {
+		return nil, err
+	}
 	return &dto.AuthResponse{
-		Token: token,
-		User: dto.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Role:      user.Role,
-			CreatedAt: user.CreatedAt.Format(time.RFC3339),
-			UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
-		},
+		Token:                 token,
+		TokenExpiresAt:        time.Now().Add(auth.AccessTokenTTL()).Format(time.RFC3339),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: stored.ExpiresAt.Format(time.RFC3339),
+		User:                  *toUserResponse(user),
 	}, nil
 }
@@ -3,8 +3,10 @@ package main
 import (
 	"time"
 
+	"github.com/aprilboiz/flight-management/pkg/auth"
 	"github.com/aprilboiz/flight-management/pkg/config"
 	"github.com/aprilboiz/flight-management/pkg/logger"
+	"github.com/aprilboiz/flight-management/pkg/mail"
 
 	"github.com/aprilboiz/flight-management/internal/api"
 	"github.com/aprilboiz/flight-management/internal/api/handlers"
@@ -45,6 +47,16 @@ func main() {
 
 	log.Info("Starting application")
 
+	// Load the token signing keys, refusing to start without one in production
+	auth.GetKeySet()
+	// Load the password policy and its breached password list
+	auth.GetPasswordPolicy()
+
+	mailer, err := mail.NewMailer(config.GetConfig().Mail)
+	if err != nil {
+		log.Fatal("Failed to set up the mail transport", zap.Error(err))
+	}
+
 	// Initialize database connection
 	db := database.GetDatabase()
 
@@ -53,43 +65,95 @@ func main() {
 	flightRepo := repository.NewFlightRepository(db)
 	airportRepo := repository.NewAirportRepository(db)
 	planeRepo := repository.NewPlaneRepository(db)
+	ticketClassRepo := repository.NewTicketClassRepository(db)
 	ticketRepo := repository.NewTicketRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	passengerRepo := repository.NewPassengerRepository(db)
+	loyaltyRepo := repository.NewLoyaltyRepository(db)
+	erasureRepo := repository.NewErasureRequestRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
+	aircraftTypeRepo := repository.NewAircraftTypeRepository(db)
+	routeRepo := repository.NewRouteRepository(db)
 
 	// Services
-	paramService := service.NewParamService(paramRepo)
-	flightService := service.NewFlightService(flightRepo, airportRepo, planeRepo, paramRepo, ticketRepo)
-	airportService := service.NewAirportService(airportRepo)
-	planeService := service.NewPlaneService(planeRepo)
-	ticketService := service.NewTicketService(ticketRepo, flightRepo, planeRepo, paramRepo)
-	userService := service.NewUserService(userRepo)
+	paramService := service.NewParamService(paramRepo, flightRepo, ticketRepo, routeRepo, airportRepo, ticketClassRepo)
+	flightService := service.NewFlightService(flightRepo, airportRepo, planeRepo, paramRepo, ticketRepo, maintenanceRepo, aircraftTypeRepo, routeRepo)
+	airportService := service.NewAirportService(airportRepo, paramRepo)
+	routeService := service.NewRouteService(routeRepo, airportRepo, aircraftTypeRepo)
+	planeService := service.NewPlaneService(planeRepo, aircraftTypeRepo, ticketClassRepo)
+	ticketClassService := service.NewTicketClassService(ticketClassRepo, aircraftTypeRepo, paramRepo)
+	maintenanceService := service.NewMaintenanceService(maintenanceRepo, planeRepo, flightRepo, ticketRepo)
+	loyaltyService := service.NewLoyaltyService(loyaltyRepo, passengerRepo)
+	ticketService := service.NewTicketService(ticketRepo, flightRepo, planeRepo, paramRepo, passengerRepo, loyaltyService)
+	userService := service.NewUserService(userRepo, invitationRepo, sessionRepo, roleRepo, loginThrottleRepo, auditRepo, passwordResetRepo, mailer)
+	if err := userService.BootstrapSuperAdmin(); err != nil {
+		log.Fatal("Failed to create the initial super admin", zap.Error(err))
+	}
+	roleService := service.NewRoleService(roleRepo)
+	auditService := service.NewAuditService(auditRepo)
+	passengerService := service.NewPassengerService(passengerRepo, ticketRepo)
+	privacyService := service.NewPrivacyService(erasureRepo, ticketRepo, passengerRepo, loyaltyService)
 
 	// Initialize scheduler service
-	schedulerService := service.NewSchedulerService(ticketRepo, flightRepo)
+	schedulerService := service.NewSchedulerService(ticketRepo, flightRepo, loyaltyService, privacyService)
 
 	// Start the place order cancellation job
 	schedulerService.StartPlaceOrderCancellationJob()
 
+	// Start the personal data retention job
+	schedulerService.StartDataRetentionJob()
+
+	// Start the job lowering loyalty tiers whose qualifying points have aged out
+	schedulerService.StartLoyaltyTierJob()
+
 	// Handlers
 	paramHandler := handlers.NewParameterHandler(paramService)
 	flightHandler := handlers.NewFlightHandler(flightService)
 	airportHandler := handlers.NewAirportHandler(airportService)
 	planeHandler := handlers.NewPlaneHandler(planeService)
+	ticketClassHandler := handlers.NewTicketClassHandler(ticketClassService)
+	routeHandler := handlers.NewRouteHandler(routeService)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
 	ticketHandler := handlers.NewTicketHandler(ticketService)
 	userHandler := handlers.NewUserHandler(userService, log)
+	roleHandler := handlers.NewRoleHandler(roleService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	passengerHandler := handlers.NewPassengerHandler(passengerService)
+	loyaltyHandler := handlers.NewLoyaltyHandler(loyaltyService)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
 
 	h := api.Handlers{
-		ParameterHandler: paramHandler,
-		AirportHandler:   airportHandler,
-		PlaneHandler:     planeHandler,
-		FlightHandler:    flightHandler,
-		TicketHandler:    ticketHandler,
-		UserHandler:      userHandler,
-		Logger:           log,
+		ParameterHandler:   paramHandler,
+		AirportHandler:     airportHandler,
+		PlaneHandler:       planeHandler,
+		TicketClassHandler: ticketClassHandler,
+		MaintenanceHandler: maintenanceHandler,
+		RouteHandler:       routeHandler,
+		FlightHandler:      flightHandler,
+		TicketHandler:      ticketHandler,
+		UserHandler:        userHandler,
+		RoleHandler:        roleHandler,
+		AuditHandler:       auditHandler,
+		PassengerHandler:   passengerHandler,
+		LoyaltyHandler:     loyaltyHandler,
+		PrivacyHandler:     privacyHandler,
+		TokenValidator:     userService,
+		PermissionResolver: roleService,
+		Logger:             log,
 	}
 
 	// Create Gin router
 	router := gin.Default()
+	// Client IPs throttle logins, so only trust X-Forwarded-For from known proxies
+	if err := router.SetTrustedProxies(config.GetConfig().Server.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
 
 	// ✅ Enable CORS for frontend (e.g. React at localhost:5173)
 	router.Use(cors.New(cors.Config{
@@ -2,10 +2,10 @@ package auth
 
 import (
 	"errors"
-	"os"
 	"time"
 
 	"github.com/aprilboiz/flight-management/internal/models"
+	"github.com/aprilboiz/flight-management/pkg/config"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -14,32 +14,39 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+const defaultAccessTokenTTL = 15 * time.Minute
+
 type Claims struct {
-	UserID   uint        `json:"user_id"`
-	Username string      `json:"username"`
-	Role     models.Role `json:"role"`
+	UserID    uint        `json:"user_id"`
+	Username  string      `json:"username"`
+	Role      models.Role `json:"role"`
+	SessionID uint        `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, username string, role models.Role) (string, error) {
+// GenerateToken issues a short-lived access token for a session of the user.
+func GenerateToken(userID uint, username string, role models.Role, sessionID uint) (string, error) {
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL())),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    config.GetConfig().Security.Tokens.Issuer,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(getJWTSecret()))
+	return GetKeySet().sign(claims)
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
-		return []byte(getJWTSecret()), nil
-	})
+	var options []jwt.ParserOption
+	if issuer := config.GetConfig().Security.Tokens.Issuer; issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, GetKeySet().verificationKey, options...)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -55,10 +62,11 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	return nil, ErrInvalidToken
 }
 
-func getJWTSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "RrXOUgb8nBFCSRCFhul7jlCOrTZFxu8e"
+// AccessTokenTTL is how long access tokens last, from security.tokens.access_token_minutes.
+func AccessTokenTTL() time.Duration {
+	minutes := config.GetConfig().Security.Tokens.AccessTokenMinutes
+	if minutes <= 0 {
+		return defaultAccessTokenTTL
 	}
-	return secret
+	return time.Duration(minutes) * time.Minute
 }
@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/aprilboiz/flight-management/pkg/config"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	minRSAKeyBits   = 2048
+	minSecretLength = 32
+
+	// secretKeyID names the HS256 key taken from JWT_SECRET when no key files are configured
+	secretKeyID = "secret"
+	// defaultKeyID names the key of JWT_SIGNING_KEY_FILE when no active key ID is configured
+	defaultKeyID = "default"
+)
+
+var (
+	ErrNoSigningKey      = errors.New("no JWT signing key configured")
+	ErrUnknownSigningKey = errors.New("JWT signing key not found")
+)
+
+// signingKey verifies the tokens of one kid and, when its private part is known, signs them.
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.PrivateKey // nil for verification-only keys
+	public  crypto.PublicKey
+}
+
+// KeySet signs access tokens with the active key and verifies tokens signed with any known
+// key, so signing keys can be rotated without logging everybody out.
+type KeySet struct {
+	activeKeyID string
+	keys        map[string]*signingKey
+}
+
+var (
+	keySet     *KeySet
+	keySetOnce sync.Once
+)
+
+// GetKeySet returns the application key set built from the security configuration.
+func GetKeySet() *KeySet {
+	keySetOnce.Do(func() {
+		cfg := config.GetConfig()
+		k, err := NewKeySet(cfg.Security.Tokens, cfg.Environment)
+		if err != nil {
+			zap.L().Fatal("Failed to load JWT signing keys", zap.Error(err))
+		}
+		keySet = k
+	})
+	return keySet
+}
+
+// NewKeySet loads the configured key files. JWT_SIGNING_KEY_FILE replaces the file of the active
+// key. Without key files, JWT_SECRET signs with HS256; without either, development signs with a
+// temporary Ed25519 key and production refuses to start.
+func NewKeySet(cfg config.TokenConfig, environment string) (*KeySet, error) {
+	activeKeyID := cfg.ActiveKeyID
+	files := make(map[string]string, len(cfg.Keys)+1)
+	for id, file := range cfg.Keys {
+		files[id] = file
+	}
+	if file := os.Getenv("JWT_SIGNING_KEY_FILE"); file != "" {
+		if activeKeyID == "" {
+			activeKeyID = defaultKeyID
+		}
+		files[activeKeyID] = file
+	}
+
+	if len(files) == 0 {
+		if secret := os.Getenv("JWT_SECRET"); secret != "" {
+			return newSecretKeySet(secret)
+		}
+		if environment == config.EnvironmentProduction {
+			return nil, ErrNoSigningKey
+		}
+		zap.L().Warn("No JWT signing key configured, using a temporary key; tokens will not survive a restart")
+		return newEphemeralKeySet()
+	}
+
+	k := &KeySet{
+		activeKeyID: activeKeyID,
+		keys:        make(map[string]*signingKey, len(files)),
+	}
+	for id, file := range files {
+		key, err := loadSigningKey(id, file)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.keys[id] = key
+	}
+	active, ok := k.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: active key %q", ErrUnknownSigningKey, activeKeyID)
+	}
+	if active.private == nil {
+		return nil, fmt.Errorf("active key %q is a public key and cannot sign", activeKeyID)
+	}
+	return k, nil
+}
+
+func newSecretKeySet(secret string) (*KeySet, error) {
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("JWT_SECRET must be at least %d characters", minSecretLength)
+	}
+	return &KeySet{
+		activeKeyID: secretKeyID,
+		keys: map[string]*signingKey{secretKeyID: {
+			id:      secretKeyID,
+			method:  jwt.SigningMethodHS256,
+			private: []byte(secret),
+			public:  []byte(secret),
+		}},
+	}, nil
+}
+
+func newEphemeralKeySet() (*KeySet, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := "dev-" + base64.RawURLEncoding.EncodeToString(public[:6])
+	return &KeySet{
+		activeKeyID: id,
+		keys: map[string]*signingKey{id: {
+			id:      id,
+			method:  jwt.SigningMethodEdDSA,
+			private: private,
+			public:  public,
+		}},
+	}, nil
+}
+
+// loadSigningKey reads a PEM file holding an RSA or Ed25519 private key, or only a public key.
+func loadSigningKey(id, file string) (*signingKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", file)
+	}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q in %s", block.Type, file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{id: id}
+	switch parsed := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, parsed, &parsed.PublicKey
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, parsed
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, parsed, parsed.Public()
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, parsed
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, expected RSA or Ed25519", parsed)
+	}
+	if public, ok := key.public.(*rsa.PublicKey); ok && public.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("RSA keys must have at least %d bits", minRSAKeyBits)
+	}
+	return key, nil
+}
+
+// sign signs claims with the active key, naming it in the kid header.
+func (k *KeySet) sign(claims jwt.Claims) (string, error) {
+	key := k.keys[k.activeKeyID]
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// verificationKey returns the key a token names in its kid header, refusing a token whose
+// algorithm is not the key's own.
+func (k *KeySet) verificationKey(token *jwt.Token) (any, error) {
+	id, _ := token.Header["kid"].(string)
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSigningKey, id)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), id)
+	}
+	return key.public, nil
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"` // OKP keys
+	X         string `json:"x,omitempty"`   // OKP keys
+	N         string `json:"n,omitempty"`   // RSA keys
+	E         string `json:"e,omitempty"`   // RSA keys
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that verify access tokens, for other services. HS256 secrets
+// are never published.
+func (k *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range k.keys {
+		jwk := JWK{KeyID: key.id, Use: "sig", Algorithm: key.method.Alg()}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/aprilboiz/flight-management/pkg/config"
+	"go.uber.org/zap"
+)
+
+// maxPasswordBytes is the longest password bcrypt accepts
+const maxPasswordBytes = 72
+
+// PasswordPolicy checks new passwords for length, character classes and known breaches.
+type PasswordPolicy struct {
+	cfg config.PasswordConfig
+
+	breached       map[string]struct{} // Lowercased plain-text passwords
+	breachedHashes map[string]struct{} // Uppercase SHA-1 hex
+}
+
+var (
+	passwordPolicy     *PasswordPolicy
+	passwordPolicyOnce sync.Once
+)
+
+// GetPasswordPolicy returns the application password policy built from the security configuration.
+func GetPasswordPolicy() *PasswordPolicy {
+	passwordPolicyOnce.Do(func() {
+		p, err := NewPasswordPolicy(config.GetConfig().Security.Password)
+		if err != nil {
+			zap.L().Fatal("Failed to load the password policy", zap.Error(err))
+		}
+		passwordPolicy = p
+	})
+	return passwordPolicy
+}
+
+// NewPasswordPolicy builds a policy, reading the breached password list if one is configured.
+func NewPasswordPolicy(cfg config.PasswordConfig) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{
+		cfg:            cfg,
+		breached:       make(map[string]struct{}),
+		breachedHashes: make(map[string]struct{}),
+	}
+	if cfg.BreachedListFile == "" {
+		return p, nil
+	}
+
+	file, err := os.Open(cfg.BreachedListFile)
+	if err != nil {
+		return nil, fmt.Errorf("breached password list: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1Hex(hash) {
+			p.breachedHashes[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+		p.breached[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("breached password list: %w", err)
+	}
+	zap.L().Info("Loaded breached password list",
+		zap.String("path", cfg.BreachedListFile),
+		zap.Int("entries", len(p.breached)+len(p.breachedHashes)))
+	return p, nil
+}
+
+// Check returns what a password lacks to meet the policy, nothing when it does. The password
+// must not contain any of the personal values, such as the username, of at least 3 characters.
+func (p *PasswordPolicy) Check(password string, personal ...string) []string {
+	var violations []string
+	if len([]rune(password)) < p.cfg.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.cfg.MinLength))
+	}
+	if len(password) > maxPasswordBytes {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes long", maxPasswordBytes))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.cfg.RequireUppercase && !upper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.cfg.RequireLowercase && !lower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.cfg.RequireDigit && !digit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.cfg.RequireSymbol && !symbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	lowered := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if len([]rune(value)) >= 3 && strings.Contains(lowered, value) {
+			violations = append(violations, "must not contain your username or email")
+			break
+		}
+	}
+	if p.isBreached(password) {
+		violations = append(violations, "appears in a list of breached passwords")
+	}
+	return violations
+}
+
+func (p *PasswordPolicy) isBreached(password string) bool {
+	if _, ok := p.breached[strings.ToLower(password)]; ok {
+		return true
+	}
+	if len(p.breachedHashes) == 0 {
+		return false
+	}
+	hash := sha1.Sum([]byte(password))
+	_, ok := p.breachedHashes[strings.ToUpper(hex.EncodeToString(hash[:]))]
+	return ok
+}
+
+func isSHA1Hex(s string) bool {
+	if len(s) != 2*sha1.Size {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -14,11 +14,16 @@ type Config struct {
 	Server      ServerConfig   `yaml:"server"`
 	Database    DatabaseConfig `yaml:"database"`
 	Logging     LoggingConfig  `yaml:"logging"`
+	Loyalty     LoyaltyConfig  `yaml:"loyalty"`
+	Security    SecurityConfig `yaml:"security"`
+	Airports    AirportsConfig `yaml:"airports"`
+	Mail        MailConfig     `yaml:"mail"`
 }
 
 type ServerConfig struct {
-	Port int    `yaml:"port"`
-	Host string `yaml:"host"`
+	Port           int      `yaml:"port"`
+	Host           string   `yaml:"host"`
+	TrustedProxies []string `yaml:"trusted_proxies"` // Proxies whose X-Forwarded-For is believed, none when empty
 }
 
 type DatabaseConfig struct {
@@ -44,6 +49,118 @@ type LoggingConfig struct {
 	OutputPath string `yaml:"output_path"`
 }
 
+type LoyaltyConfig struct {
+	MemberNumberPrefix string             `yaml:"member_number_prefix"`
+	PointsPerUnitSpent float64            `yaml:"points_per_unit_spent"` // Points per currency unit paid
+	PointsPerKilometer float64            `yaml:"points_per_kilometer"`
+	PointValue         float64            `yaml:"point_value"`          // Fare discount per redeemed point
+	MaxRedemptionRatio float64            `yaml:"max_redemption_ratio"` // Share of a fare that points may cover
+	ClassMultipliers   map[string]float64 `yaml:"class_multipliers"`    // Keyed by ticket class name
+	TierThresholds     map[string]int     `yaml:"tier_thresholds"`      // Qualifying points over 12 months, keyed by tier
+}
+
+// Mail transports
+const (
+	MailTransportSMTP = "smtp"
+	MailTransportFile = "file" // Writes messages to Directory instead of sending them, for development
+)
+
+type MailConfig struct {
+	Transport string     `yaml:"transport"` // One of the mail transports, file when empty
+	From      string     `yaml:"from"`
+	Directory string     `yaml:"directory"` // Where the file transport writes messages
+	SMTP      SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"` // No authentication when empty
+	Password string `yaml:"password"` // Prefer SMTP_PASSWORD over storing it here
+}
+
+type AirportsConfig struct {
+	ImportDirectory string `yaml:"import_directory"` // Directory airport CSV files are imported from
+}
+
+type SecurityConfig struct {
+	Encryption     EncryptionConfig     `yaml:"encryption"`
+	PII            PIIConfig            `yaml:"pii"`
+	BootstrapAdmin BootstrapAdminConfig `yaml:"bootstrap_admin"`
+	Registration   RegistrationConfig   `yaml:"registration"`
+	Tokens         TokenConfig          `yaml:"tokens"`
+	Login          LoginConfig          `yaml:"login"`
+	Password       PasswordConfig       `yaml:"password"`
+}
+
+// PasswordConfig is the policy new passwords must meet, and how they are reset. Passwords in
+// the breached list are refused whatever their strength.
+type PasswordConfig struct {
+	MinLength         int    `yaml:"min_length"`
+	RequireUppercase  bool   `yaml:"require_uppercase"`
+	RequireLowercase  bool   `yaml:"require_lowercase"`
+	RequireDigit      bool   `yaml:"require_digit"`
+	RequireSymbol     bool   `yaml:"require_symbol"`
+	BreachedListFile  string `yaml:"breached_list_file"`  // One password, or SHA-1 hash as in breach corpora, per line; none when empty
+	ResetTokenMinutes int    `yaml:"reset_token_minutes"` // Validity of password reset links
+	ResetURL          string `yaml:"reset_url"`           // Client page receiving the reset token in its token query parameter
+}
+
+// LoginConfig throttles failed logins. Each failure for a username doubles the wait before
+// the next attempt, from BaseDelaySeconds up to MaxDelaySeconds, and too many failures for a
+// username or an IP address lock it out.
+type LoginConfig struct {
+	MaxFailures          int `yaml:"max_failures"`           // Failures for a username before it is locked out
+	MaxFailuresPerIP     int `yaml:"max_failures_per_ip"`    // Failures from an IP address before it is locked out
+	FailureWindowMinutes int `yaml:"failure_window_minutes"` // Failures older than this are forgotten
+	LockoutMinutes       int `yaml:"lockout_minutes"`
+	BaseDelaySeconds     int `yaml:"base_delay_seconds"`
+	MaxDelaySeconds      int `yaml:"max_delay_seconds"`
+}
+
+// TokenConfig sets how long tokens last and how access tokens are signed. Access tokens are
+// short-lived; refresh tokens are rotated on every use and a session lasts as long as it keeps
+// being refreshed.
+type TokenConfig struct {
+	AccessTokenMinutes int               `yaml:"access_token_minutes"`
+	RefreshTokenHours  int               `yaml:"refresh_token_hours"`
+	Issuer             string            `yaml:"issuer"`        // iss claim of access tokens, checked when set
+	ActiveKeyID        string            `yaml:"active_key_id"` // Key used to sign new access tokens
+	Keys               map[string]string `yaml:"keys"`          // PEM files of RSA or Ed25519 keys, keyed by key ID; public keys only verify
+}
+
+// Registration modes
+const (
+	RegistrationDisabled   = "disabled"    // No self-registration, not even with an invitation
+	RegistrationInviteOnly = "invite_only" // Only with an invitation
+	RegistrationDomain     = "domain"      // With an invitation, or as staff with an email in AllowedDomains
+)
+
+type RegistrationConfig struct {
+	Mode               string   `yaml:"mode"`                 // One of the registration modes, disabled when empty
+	AllowedDomains     []string `yaml:"allowed_domains"`      // Email domains that may register in domain mode
+	InvitationTTLHours int      `yaml:"invitation_ttl_hours"` // Default validity of invitations
+}
+
+// BootstrapAdminConfig is the super admin created on first run, when no super admin exists yet.
+// Nothing is created while the username or the password is empty.
+type BootstrapAdminConfig struct {
+	Username string `yaml:"username"`
+	Email    string `yaml:"email"`
+	Password string `yaml:"password"` // Prefer BOOTSTRAP_ADMIN_PASSWORD over storing it here
+}
+
+type EncryptionConfig struct {
+	ActiveKeyID   string            `yaml:"active_key_id"`   // Key used to encrypt new values
+	Keys          map[string]string `yaml:"keys"`            // Base64-encoded 32-byte AES keys, keyed by key ID
+	BlindIndexKey string            `yaml:"blind_index_key"` // Base64-encoded HMAC key for searchable hashes
+}
+
+type PIIConfig struct {
+	RetentionDays   int `yaml:"retention_days"`    // Days after departure before tickets are anonymized automatically, 0 disables
+	ErasureHoldDays int `yaml:"erasure_hold_days"` // Days after departure an erasure request must wait, e.g. for refund disputes
+}
+
 var (
 	cfg  *Config   // Private variable to hold the single instance
 	once sync.Once // Ensures initialization code runs only once
@@ -7,11 +7,13 @@ import (
 	"time"
 
 	"github.com/aprilboiz/flight-management/pkg/config"
+	"github.com/aprilboiz/flight-management/pkg/encryption"
 
 	"github.com/aprilboiz/flight-management/internal/models"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -96,17 +98,298 @@ func migrateDatabase(db *gorm.DB) error {
 	//if err != nil {
 	//	return err
 	//}
-	return db.AutoMigrate(
+	if err := dropParameterLock(db); err != nil {
+		return err
+	}
+	err := db.AutoMigrate(
+		&models.AircraftType{},
 		&models.Plane{},
 		&models.TicketClass{},
 		&models.Airport{},
 		&models.Seat{},
+		&models.MaintenanceWindow{},
+		&models.Route{},
 		&models.Flight{},
 		&models.IntermediateStop{},
+		&models.Passenger{},
+		&models.TravelDocument{},
 		&models.Ticket{},
+		&models.ErasureRequest{},
+		&models.LoyaltyAccount{},
+		&models.LoyaltyTransaction{},
 		&models.Parameter{},
+		&models.RoleDefinition{},
+		&models.RolePermission{},
 		&models.User{},
+		&models.Invitation{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.PasswordResetToken{},
+		&models.LoginThrottle{},
+		&models.AuditLog{},
 	)
+	if err != nil {
+		return err
+	}
+	if err := guardLoyaltyLedger(db); err != nil {
+		return err
+	}
+	if err := uniqueAirportCodes(db); err != nil {
+		return err
+	}
+	if err := airportCountryCodes(db); err != nil {
+		return err
+	}
+	if err := uniqueRoutes(db); err != nil {
+		return err
+	}
+	if err := uniqueTicketClassNames(db); err != nil {
+		return err
+	}
+	if err := uniqueActiveSeats(db); err != nil {
+		return err
+	}
+	if err := uniqueUsers(db); err != nil {
+		return err
+	}
+	if err := encryptLegacyPII(db); err != nil {
+		return err
+	}
+	return seedBuiltInRoles(db)
+}
+
+// dropParameterLock removes the column that kept the parameters table to a single row, so
+// that it can hold a row per parameter version.
+func dropParameterLock(db *gorm.DB) error {
+	return db.Exec(`ALTER TABLE IF EXISTS parameters DROP COLUMN IF EXISTS lock`).Error
+}
+
+// uniqueAirportCodes makes IATA and ICAO codes unique among airports that are not deleted.
+func uniqueAirportCodes(db *gorm.DB) error {
+	statements := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_airports_airport_code
+		ON airports (airport_code) WHERE deleted_at IS NULL`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_airports_icao_code
+		ON airports (icao_code) WHERE deleted_at IS NULL AND icao_code IS NOT NULL`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// airportCountryCodes fills in the country code of airports imported before it was stored,
+// whose country name was left as the ISO code. Importing them again with a countries file
+// names their country.
+func airportCountryCodes(db *gorm.DB) error {
+	return db.Exec(`UPDATE airports SET country_code = country_name
+		WHERE COALESCE(country_code, '') = '' AND country_name ~ '^[A-Z]{2}$'`).Error
+}
+
+// uniqueRoutes allows a single route per direction between two airports, ignoring deleted routes.
+func uniqueRoutes(db *gorm.DB) error {
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_routes_airport_pair
+		ON routes (departure_airport_id, arrival_airport_id) WHERE deleted_at IS NULL`).Error
+}
+
+// uniqueTicketClassNames keeps class names distinct regardless of case, since seats and seat
+// layouts refer to classes by name.
+func uniqueTicketClassNames(db *gorm.DB) error {
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_classes_name
+		ON ticket_classes (LOWER(ticket_class_name)) WHERE deleted_at IS NULL`).Error
+}
+
+// uniqueUsers makes usernames and emails unique among users that are not deleted, so a deleted
+// user can be created again. The unique indexes AutoMigrate used to create covered deleted users
+// too and are dropped.
+func uniqueUsers(db *gorm.DB) error {
+	statements := []string{
+		`DROP INDEX IF EXISTS idx_users_username`,
+		`DROP INDEX IF EXISTS idx_users_email`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_active_username
+		ON users (username) WHERE deleted_at IS NULL`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_active_email
+		ON users (email) WHERE deleted_at IS NULL`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueActiveSeats allows at most one active ticket per seat and flight, so two bookings
+// racing for the same seat cannot both succeed. Cancelled and expired tickets free the seat.
+func uniqueActiveSeats(db *gorm.DB) error {
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_active_seat
+		ON tickets (flight_id, seat_id) WHERE ticket_status = 'ACTIVE'`).Error
+}
+
+// encryptLegacyPII encrypts the tickets, passenger profiles and travel documents saved before
+// their PII was encrypted and fills in their blind indexes, so lookups by ID card or email find
+// them. It then makes document numbers unique by their blind index instead of their plaintext.
+func encryptLegacyPII(db *gorm.DB) error {
+	statements := []string{
+		`DROP INDEX IF EXISTS idx_travel_document_number`,
+		`DROP INDEX IF EXISTS idx_passengers_email`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	const batchSize = 500
+	var lastTicketID uint
+	for {
+		var tickets []*models.Ticket
+		err := db.
+			Where("id > ?", lastTicketID).
+			Where("(id_card <> '' AND id_card NOT LIKE 'enc:%') OR (phone_number <> '' AND phone_number NOT LIKE 'enc:%') OR (email <> '' AND email NOT LIKE 'enc:%')").
+			Order("id").Limit(batchSize).Find(&tickets).Error
+		if err != nil {
+			return err
+		}
+		if len(tickets) == 0 {
+			break
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			for _, ticket := range tickets {
+				err := tx.Model(ticket).
+					Select("IDCard", "IDCardIndex", "PhoneNumber", "Email", "EmailIndex").
+					Updates(ticket).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		lastTicketID = tickets[len(tickets)-1].ID
+	}
+	for {
+		var passengers []*models.Passenger
+		err := db.Unscoped().
+			Where("(phone_number <> '' AND phone_number NOT LIKE 'enc:%') OR (email <> '' AND email NOT LIKE 'enc:%')").
+			Order("id").Limit(batchSize).Find(&passengers).Error
+		if err != nil {
+			return err
+		}
+		if len(passengers) == 0 {
+			break
+		}
+		for _, passenger := range passengers {
+			err := db.Unscoped().Model(passenger).
+				Select("PhoneNumber", "PhoneNumberIndex", "Email", "EmailIndex").
+				Updates(passenger).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for {
+		var documents []*models.TravelDocument
+		err := db.Unscoped().
+			Where("document_number <> '' AND document_number NOT LIKE 'enc:%'").
+			Order("id").Limit(batchSize).Find(&documents).Error
+		if err != nil {
+			return err
+		}
+		if len(documents) == 0 {
+			break
+		}
+		for _, document := range documents {
+			err := db.Unscoped().Model(document).Select("DocumentNumber", "DocumentNumberIndex").Updates(document).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := decryptPassengerNames(db); err != nil {
+		return err
+	}
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_travel_documents_number
+		ON travel_documents (document_type, document_number_index) WHERE deleted_at IS NULL`).Error
+}
+
+// decryptPassengerNames turns passenger names back into plaintext. They were encrypted for a
+// while, which left only exact matches for name searches while the same names stayed readable
+// on tickets.
+func decryptPassengerNames(db *gorm.DB) error {
+	var passengers []struct {
+		ID       uint
+		FullName string
+	}
+	err := db.Table("passengers").
+		Select("id", "full_name").
+		Where("full_name LIKE 'enc:%'").
+		Find(&passengers).Error
+	if err != nil {
+		return err
+	}
+	for _, passenger := range passengers {
+		name, err := encryption.GetKeyring().Decrypt(passenger.FullName, "full_name")
+		if err != nil {
+			return fmt.Errorf("passenger %d name: %w", passenger.ID, err)
+		}
+		if err := db.Table("passengers").Where("id = ?", passenger.ID).Update("full_name", name).Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec(`ALTER TABLE passengers DROP COLUMN IF EXISTS full_name_index`).Error
+}
+
+// seedBuiltInRoles creates the built-in roles with their default permissions. Roles that already
+// exist keep the permissions they were given since.
+func seedBuiltInRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, name := range []models.Role{models.RoleSuperAdmin, models.RoleAdmin, models.RoleStaff} {
+			role := models.RoleDefinition{Name: name, BuiltIn: true}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Permissions").Create(&role)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 || name == models.RoleSuperAdmin {
+				continue
+			}
+			permissions := make([]models.RolePermission, len(models.DefaultRolePermissions[name]))
+			for i, permission := range models.DefaultRolePermissions[name] {
+				permissions[i] = models.RolePermission{Role: name, Permission: permission}
+			}
+			if err := tx.Create(&permissions).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// guardLoyaltyLedger installs a trigger that rejects UPDATE and DELETE on the points ledger,
+// so entries stay immutable even for writes that bypass the GORM hooks.
+func guardLoyaltyLedger(db *gorm.DB) error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION reject_loyalty_ledger_change() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'loyalty ledger is append-only';
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS loyalty_transactions_append_only ON loyalty_transactions`,
+		`CREATE TRIGGER loyalty_transactions_append_only
+		BEFORE UPDATE OR DELETE ON loyalty_transactions
+		FOR EACH ROW EXECUTE FUNCTION reject_loyalty_ledger_change()`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func GetSequenceNameForTable(table string, column string) (string, error) {
@@ -1,29 +1,74 @@
 -- Inserting data into the Airport table
-INSERT INTO airports (airport_code, airport_name, city_name, country_name, created_at, updated_at) VALUES
-                                                                                                       ('SGN', 'Tan Son Nhat International Airport', 'Ho Chi Minh City', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('HAN', 'Noi Bai International Airport', 'Hanoi', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('DAD', 'Da Nang International Airport', 'Da Nang', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('CXR', 'Cam Ranh International Airport', 'Nha Trang', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('PQC', 'Phu Quoc International Airport', 'Phu Quoc', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('HPH', 'Cat Bi International Airport', 'Hai Phong', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('HUI', 'Phu Bai International Airport', 'Hue', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('VDO', 'Van Don International Airport', 'Quang Ninh', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('VCA', 'Can Tho International Airport', 'Can Tho', 'Vietnam', NOW(), NOW()),
-                                                                                                       ('DLI', 'Lien Khuong International Airport', 'Da Lat', 'Vietnam', NOW(), NOW());
+INSERT INTO airports (airport_code, icao_code, airport_name, city_name, country_name, country_code, latitude, longitude, elevation_ft, created_at, updated_at) VALUES
+                                                                                                       ('SGN', 'VVTS', 'Tan Son Nhat International Airport', 'Ho Chi Minh City', 'Vietnam', 'VN', 10.8188, 106.6519, 33, NOW(), NOW()),
+                                                                                                       ('HAN', 'VVNB', 'Noi Bai International Airport', 'Hanoi', 'Vietnam', 'VN', 21.2212, 105.8072, 39, NOW(), NOW()),
+                                                                                                       ('DAD', 'VVDN', 'Da Nang International Airport', 'Da Nang', 'Vietnam', 'VN', 16.0439, 108.1993, 33, NOW(), NOW()),
+                                                                                                       ('CXR', 'VVCR', 'Cam Ranh International Airport', 'Nha Trang', 'Vietnam', 'VN', 11.9982, 109.2194, 40, NOW(), NOW()),
+                                                                                                       ('PQC', 'VVPQ', 'Phu Quoc International Airport', 'Phu Quoc', 'Vietnam', 'VN', 10.1698, 103.9931, 37, NOW(), NOW()),
+                                                                                                       ('HPH', 'VVCI', 'Cat Bi International Airport', 'Hai Phong', 'Vietnam', 'VN', 20.8194, 106.7250, 6, NOW(), NOW()),
+                                                                                                       ('HUI', 'VVPB', 'Phu Bai International Airport', 'Hue', 'Vietnam', 'VN', 16.4015, 107.7026, 48, NOW(), NOW()),
+                                                                                                       ('VDO', 'VVVD', 'Van Don International Airport', 'Quang Ninh', 'Vietnam', 'VN', 21.1178, 107.4144, 26, NOW(), NOW()),
+                                                                                                       ('VCA', 'VVCT', 'Can Tho International Airport', 'Can Tho', 'Vietnam', 'VN', 10.0851, 105.7117, 9, NOW(), NOW()),
+                                                                                                       ('DLI', 'VVDL', 'Lien Khuong International Airport', 'Da Lat', 'Vietnam', 'VN', 11.7500, 108.3670, 3156, NOW(), NOW());
 
 
+-- Inserting data into the AircraftType table
+INSERT INTO aircraft_types (type_code, manufacturer, model_name, range_km, cruise_speed_kmh, seat_layout, created_at, updated_at) VALUES
+    ('A321', 'Airbus', 'A321', 5950, 828, '{"columns":["A","B","C","D","E","F"],"aisles_after":["C"],"cabins":[{"ticket_class":"Business","from_row":1,"to_row":4,"columns":["A","C","D","F"]},{"ticket_class":"Economy","from_row":5,"to_row":29}],"blocked_seats":[],"exit_rows":[10,11]}', NOW(), NOW()),
+    ('A359', 'Airbus', 'A350-900', 15000, 903, '{"columns":["A","B","C","D","E","F","G","H","K"],"aisles_after":["C","G"],"cabins":[{"ticket_class":"Business","from_row":1,"to_row":8,"columns":["A","D","G","K"]},{"ticket_class":"Economy","from_row":10,"to_row":42}],"blocked_seats":[],"exit_rows":[10,24]}', NOW(), NOW()),
+    ('B789', 'Boeing', '787-9', 14140, 903, '{"columns":["A","B","C","D","E","F","G","H","K"],"aisles_after":["C","F"],"cabins":[{"ticket_class":"Business","from_row":1,"to_row":7,"columns":["A","D","G","K"]},{"ticket_class":"Economy","from_row":10,"to_row":40}],"blocked_seats":[],"exit_rows":[10,24]}', NOW(), NOW()),
+    ('B78X', 'Boeing', '787-10', 11910, 903, '{"columns":["A","B","C","D","E","F","G","H","K"],"aisles_after":["C","F"],"cabins":[{"ticket_class":"Business","from_row":1,"to_row":9,"columns":["A","D","G","K"]},{"ticket_class":"Economy","from_row":10,"to_row":46}],"blocked_seats":[],"exit_rows":[10,28]}', NOW(), NOW());
+
 -- Inserting data into the Plane table
-INSERT INTO planes (plane_code, plane_name, created_at, updated_at) VALUES
-                                                                        ('RUA321', 'Airbus A321', NOW(), NOW()),
-                                                                        ('RUA359', 'Airbus A350-900', NOW(), NOW()),
-                                                                        ('RUA186', 'Airbus A321', NOW(), NOW()),
-                                                                        ('RUA205', 'Boeing 787-9', NOW(), NOW()),
-                                                                        ('RUA787', 'Boeing 787-10', NOW(), NOW());
+INSERT INTO planes (plane_code, plane_name, aircraft_type_id, created_at, updated_at) VALUES
+                                                                        ('RUA321', 'Airbus A321', (SELECT id FROM aircraft_types WHERE type_code = 'A321'), NOW(), NOW()),
+                                                                        ('RUA359', 'Airbus A350-900', (SELECT id FROM aircraft_types WHERE type_code = 'A359'), NOW(), NOW()),
+                                                                        ('RUA186', 'Airbus A321', (SELECT id FROM aircraft_types WHERE type_code = 'A321'), NOW(), NOW()),
+                                                                        ('RUA205', 'Boeing 787-9', (SELECT id FROM aircraft_types WHERE type_code = 'B789'), NOW(), NOW()),
+                                                                        ('RUA787', 'Boeing 787-10', (SELECT id FROM aircraft_types WHERE type_code = 'B78X'), NOW(), NOW());
+
+-- Inserting data into the Route table
+INSERT INTO routes (departure_airport_id, arrival_airport_id, default_duration, default_base_price, created_at, updated_at)
+SELECT departure.id, arrival.id, pair.duration, pair.price, NOW(), NOW()
+FROM (VALUES
+          ('SGN', 'HAN', 130, 1500000),
+          ('HAN', 'SGN', 130, 1500000),
+          ('SGN', 'DAD', 85, 1000000),
+          ('DAD', 'SGN', 85, 1000000),
+          ('HAN', 'DAD', 80, 1000000),
+          ('DAD', 'HAN', 80, 1000000),
+          ('SGN', 'PQC', 60, 800000),
+          ('PQC', 'SGN', 60, 800000),
+          ('HAN', 'PQC', 135, 1600000),
+          ('PQC', 'HAN', 135, 1600000),
+          ('SGN', 'HPH', 125, 1400000),
+          ('HPH', 'SGN', 125, 1400000),
+          ('HAN', 'CXR', 115, 1300000),
+          ('CXR', 'HAN', 115, 1300000),
+          ('HAN', 'DLI', 110, 1300000),
+          ('DLI', 'HAN', 110, 1300000),
+          ('SGN', 'VDO', 130, 1500000),
+          ('HAN', 'VCA', 130, 1400000),
+          ('VCA', 'HAN', 130, 1400000),
+          ('SGN', 'HUI', 85, 1000000),
+          ('HUI', 'SGN', 85, 1000000)
+     ) AS pair (departure_code, arrival_code, duration, price)
+JOIN airports departure ON departure.airport_code = pair.departure_code
+JOIN airports arrival ON arrival.airport_code = pair.arrival_code;
+
+-- Only the wide-bodies fly the trunk route between Ho Chi Minh City and Hanoi
+INSERT INTO route_aircraft_types (route_id, aircraft_type_id)
+SELECT routes.id, aircraft_types.id
+FROM routes
+JOIN airports departure ON departure.id = routes.departure_airport_id
+JOIN airports arrival ON arrival.id = routes.arrival_airport_id
+JOIN aircraft_types ON aircraft_types.type_code IN ('A359', 'B789', 'B78X')
+WHERE (departure.airport_code, arrival.airport_code) IN (('SGN', 'HAN'), ('HAN', 'SGN'));
 
 -- Inserting data into the TicketClass table
-INSERT INTO ticket_classes (ticket_class_name, price_percentage, created_at, updated_at) VALUES
-                                                                                             ('Economy', 1.0, NOW(), NOW()),
-                                                                                             ('Business', 1.05, NOW(), NOW());
+INSERT INTO ticket_classes (ticket_class_name, price_percentage, cabin_code, display_order, created_at, updated_at) VALUES
+                                                                                                                         ('Business', 1.05, 'J', 1, NOW(), NOW()),
+                                                                                                                         ('Economy', 1.0, 'Y', 2, NOW(), NOW());
 
 -- Inserting data into the Seat table (assuming each plane has a mix of Economy and Business class seats)
 -- For RUA321 (e.g., 150 Economy, 16 Business)
@@ -80,8 +125,8 @@ DO $$
     END$$;
 
 -- Inserting data into the Configuration table
-INSERT INTO parameters (number_of_airports, min_flight_duration, max_intermediate_stops, min_intermediate_stop_duration, max_intermediate_stop_duration, max_ticket_classes, latest_ticket_purchase_time, ticket_cancellation_time, created_at, updated_at) VALUES
-    (10, 30, 2, 10, 20, 2, 1, 0, NOW(), NOW());
+INSERT INTO parameters (version, effective_from, created_by, comment, number_of_airports, min_flight_duration, max_intermediate_stops, min_intermediate_stop_duration, max_intermediate_stop_duration, max_ticket_classes, latest_ticket_purchase_time, ticket_cancellation_time, created_at, updated_at) VALUES
+    (1, NOW(), 'system', 'Initial parameters', 10, 30, 2, 10, 20, 2, 1, 0, NOW(), NOW());
 
 
 -- Insert admin user
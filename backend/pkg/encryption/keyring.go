@@ -0,0 +1,206 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aprilboiz/flight-management/pkg/config"
+	"go.uber.org/zap"
+)
+
+// ciphertextPrefix marks values written by the keyring. Stored values have the form
+// "enc:<key id>:<base64 nonce+ciphertext>"; anything else is treated as legacy plaintext.
+const ciphertextPrefix = "enc:"
+
+var (
+	ErrUnknownKey         = errors.New("encryption key not found")
+	ErrMalformedCipher    = errors.New("malformed ciphertext")
+	ErrInvalidKeyMaterial = errors.New("encryption keys must be base64-encoded 32-byte values")
+	ErrDevelopmentKey     = errors.New("the development PII keys from config.yml cannot be used in production")
+)
+
+// developmentKeys are the keys committed in config.yml. Anyone can read them, so production
+// refuses to encrypt or index with them.
+var developmentKeys = map[string]struct{}{
+	"UzLSfjkmDpEc2up5GLNwRU35DqrOu3wubfPYmQYlnfg=": {},
+	"rNeuHolctvy+jDmmRxvDCAxRfrNX6Wk5ICiWXDeMLnQ=": {},
+}
+
+// Keyring encrypts values with the active key and decrypts values written with any
+// known key, so keys can be rotated without downtime.
+type Keyring struct {
+	activeKeyID string
+	ciphers     map[string]cipher.AEAD
+	indexKey    []byte
+}
+
+var (
+	keyring     *Keyring
+	keyringOnce sync.Once
+)
+
+// GetKeyring returns the application keyring built from the security configuration.
+func GetKeyring() *Keyring {
+	keyringOnce.Do(func() {
+		cfg := config.GetConfig()
+		k, err := NewKeyring(cfg.Security.Encryption, cfg.Environment)
+		if err != nil {
+			zap.L().Fatal("Failed to load PII encryption keys", zap.Error(err))
+		}
+		keyring = k
+	})
+	return keyring
+}
+
+// NewKeyring builds a keyring from configuration. PII_ENCRYPTION_KEY adds a key under the ID in
+// PII_ENCRYPTION_KEY_ID and makes it the active key; the configured keys still decrypt what they
+// wrote. PII_BLIND_INDEX_KEY replaces the blind index key. In production the development keys
+// from config.yml are refused.
+func NewKeyring(cfg config.EncryptionConfig, environment string) (*Keyring, error) {
+	activeKeyID := cfg.ActiveKeyID
+	keys := make(map[string]string, len(cfg.Keys)+1)
+	for id, key := range cfg.Keys {
+		keys[id] = key
+	}
+	if key := os.Getenv("PII_ENCRYPTION_KEY"); key != "" {
+		id := os.Getenv("PII_ENCRYPTION_KEY_ID")
+		if id == "" {
+			return nil, errors.New("PII_ENCRYPTION_KEY requires PII_ENCRYPTION_KEY_ID to name the key")
+		}
+		if configured, ok := keys[id]; ok && configured != key {
+			return nil, fmt.Errorf("PII_ENCRYPTION_KEY_ID %q names a configured key with different material", id)
+		}
+		keys[id] = key
+		activeKeyID = id
+	}
+	indexKey := cfg.BlindIndexKey
+	if key := os.Getenv("PII_BLIND_INDEX_KEY"); key != "" {
+		indexKey = key
+	}
+
+	if activeKeyID == "" || strings.Contains(activeKeyID, ":") {
+		return nil, fmt.Errorf("invalid active key id %q", activeKeyID)
+	}
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q", ErrUnknownKey, activeKeyID)
+	}
+	if environment == config.EnvironmentProduction {
+		if _, ok := developmentKeys[keys[activeKeyID]]; ok {
+			return nil, fmt.Errorf("%w: active key %q", ErrDevelopmentKey, activeKeyID)
+		}
+		if _, ok := developmentKeys[indexKey]; ok {
+			return nil, fmt.Errorf("%w: blind index key", ErrDevelopmentKey)
+		}
+	}
+
+	k := &Keyring{
+		activeKeyID: activeKeyID,
+		ciphers:     make(map[string]cipher.AEAD, len(keys)),
+	}
+	for id, encoded := range keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		material, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		block, err := aes.NewCipher(material)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.ciphers[id] = aead
+	}
+
+	material, err := decodeKey(indexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+	k.indexKey = material
+	return k, nil
+}
+
+// ActiveKeyID returns the ID of the key used for new ciphertexts.
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeKeyID
+}
+
+// ActivePrefix returns the prefix shared by every value encrypted with the active key.
+func (k *Keyring) ActivePrefix() string {
+	return ciphertextPrefix + k.activeKeyID + ":"
+}
+
+// Encrypt seals plaintext with the active key. The context is authenticated but not stored,
+// which binds a ciphertext to the column it was written to.
+func (k *Keyring) Encrypt(plaintext, context string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	aead := k.ciphers[k.activeKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(context))
+	return k.ActivePrefix() + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with whichever key wrote it.
+// Values without the ciphertext prefix are returned unchanged.
+func (k *Keyring) Decrypt(value, context string) (string, error) {
+	if !strings.HasPrefix(value, ciphertextPrefix) {
+		return value, nil
+	}
+	keyID, payload, found := strings.Cut(strings.TrimPrefix(value, ciphertextPrefix), ":")
+	if !found {
+		return "", ErrMalformedCipher
+	}
+	aead, ok := k.ciphers[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformedCipher
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(context))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex returns a keyed hash of the normalized value, allowing equality lookups
+// on encrypted columns without decrypting them.
+func (k *Keyring) BlindIndex(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	material, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(material) != 32 {
+		return nil, ErrInvalidKeyMaterial
+	}
+	return material, nil
+}
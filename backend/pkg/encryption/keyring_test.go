@@ -0,0 +1,383 @@
+package encryption
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aprilboiz/flight-management/pkg/config"
+)
+
+const (
+	testKey1     = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+	testKey2     = "ICEiIyQlJicoKSorLC0uLzAxMjM0NTY3ODk6Ozw9Pj8="
+	testIndexKey = "QEFCQ0RFRkdISUpLTE1OT1BRUlNUVVZXWFlaW1xdXl8="
+)
+
+// newTestKeyring builds a keyring without the environment overrides.
+func newTestKeyring(t *testing.T, activeKeyID string, keys map[string]string) *Keyring {
+	t.Helper()
+	t.Setenv("PII_ENCRYPTION_KEY", "")
+	t.Setenv("PII_BLIND_INDEX_KEY", "")
+	k, err := NewKeyring(config.EncryptionConfig{
+		ActiveKeyID:   activeKeyID,
+		Keys:          keys,
+		BlindIndexKey: testIndexKey,
+	}, config.EnvironmentDevelopment)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return k
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string]string{"k1": testKey1})
+
+	tests := []struct {
+		name      string
+		plaintext string
+	}{
+		{"id card", "079123456789"},
+		{"email", "nguyen.van.a@example.com"},
+		{"unicode name", "Nguyễn Văn Đức"},
+		{"value containing the prefix", "enc:k1:not-a-ciphertext"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ciphertext, err := k.Encrypt(tt.plaintext, "tickets.id_card")
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			if tt.plaintext == "" {
+				if ciphertext != "" {
+					t.Fatalf("Encrypt(%q) = %q, want empty", tt.plaintext, ciphertext)
+				}
+				return
+			}
+			if !strings.HasPrefix(ciphertext, k.ActivePrefix()) {
+				t.Errorf("ciphertext %q lacks the active prefix %q", ciphertext, k.ActivePrefix())
+			}
+			if strings.Contains(ciphertext, tt.plaintext) {
+				t.Errorf("ciphertext %q contains the plaintext", ciphertext)
+			}
+			got, err := k.Decrypt(ciphertext, "tickets.id_card")
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if got != tt.plaintext {
+				t.Errorf("Decrypt = %q, want %q", got, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestEncryptUsesFreshNonces(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string]string{"k1": testKey1})
+	first, err := k.Encrypt("079123456789", "tickets.id_card")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	second, err := k.Encrypt("079123456789", "tickets.id_card")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if first == second {
+		t.Errorf("encrypting the same value twice gave the same ciphertext %q", first)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string]string{"k1": testKey1})
+	ciphertext, err := k.Encrypt("079123456789", "tickets.id_card")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		context string
+		wantErr error // nil when any error will do
+	}{
+		{"other column", ciphertext, "tickets.email", nil},
+		{"unknown key", "enc:k9:" + strings.TrimPrefix(ciphertext, k.ActivePrefix()), "tickets.id_card", ErrUnknownKey},
+		{"missing key id", "enc:abc", "tickets.id_card", ErrMalformedCipher},
+		{"bad base64", "enc:k1:***", "tickets.id_card", ErrMalformedCipher},
+		{"too short", "enc:k1:AAAA", "tickets.id_card", ErrMalformedCipher},
+		{"tampered", ciphertext[:len(ciphertext)-4] + "AAA=", "tickets.id_card", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := k.Decrypt(tt.value, tt.context)
+			if err == nil {
+				t.Fatalf("Decrypt(%q) succeeded, want an error", tt.value)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decrypt(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecryptLegacyPlaintext(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string]string{"k1": testKey1})
+	for _, value := range []string{"079123456789", "a@example.com", ""} {
+		got, err := k.Decrypt(value, "tickets.id_card")
+		if err != nil {
+			t.Fatalf("Decrypt(%q): %v", value, err)
+		}
+		if got != value {
+			t.Errorf("Decrypt(%q) = %q, want it unchanged", value, got)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	old := newTestKeyring(t, "k1", map[string]string{"k1": testKey1})
+	rotated := newTestKeyring(t, "k2", map[string]string{"k1": testKey1, "k2": testKey2})
+	retired := newTestKeyring(t, "k2", map[string]string{"k2": testKey2})
+
+	written, err := old.Encrypt("079123456789", "passengers.email")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	rewritten, err := rotated.Encrypt("079123456789", "passengers.email")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		keyring *Keyring
+		value   string
+		wantErr bool
+	}{
+		{"old value after rotation", rotated, written, false},
+		{"new value after rotation", rotated, rewritten, false},
+		{"new value once the old key is removed", retired, rewritten, false},
+		{"old value once the old key is removed", retired, written, true},
+		{"new value on the old keyring", old, rewritten, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.keyring.Decrypt(tt.value, "passengers.email")
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownKey) {
+					t.Fatalf("Decrypt error = %v, want %v", err, ErrUnknownKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if got != "079123456789" {
+				t.Errorf("Decrypt = %q, want %q", got, "079123456789")
+			}
+		})
+	}
+
+	if strings.HasPrefix(written, rotated.ActivePrefix()) {
+		t.Errorf("value written with k1 %q has the prefix of the active key k2", written)
+	}
+	if !strings.HasPrefix(rewritten, rotated.ActivePrefix()) {
+		t.Errorf("value written after rotation %q lacks the prefix %q", rewritten, rotated.ActivePrefix())
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string]string{"k1": testKey1})
+	// The blind index does not depend on the encryption keys, so it survives their rotation
+	rotated := newTestKeyring(t, "k2", map[string]string{"k2": testKey2})
+
+	tests := []struct {
+		name      string
+		a, b      string
+		wantEqual bool
+	}{
+		{"same value", "079123456789", "079123456789", true},
+		{"case", "b1234567", "B1234567", true},
+		{"surrounding space", "  a@example.com ", "A@EXAMPLE.COM", true},
+		{"different values", "079123456789", "079123456788", false},
+		{"inner space", "Nguyen Van A", "NguyenVan A", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := k.BlindIndex(tt.a), rotated.BlindIndex(tt.b)
+			if (a == b) != tt.wantEqual {
+				t.Errorf("BlindIndex(%q) = %q, BlindIndex(%q) = %q, want equal %v", tt.a, a, tt.b, b, tt.wantEqual)
+			}
+			if len(a) != 64 {
+				t.Errorf("BlindIndex(%q) has length %d, want 64", tt.a, len(a))
+			}
+		})
+	}
+
+	for _, value := range []string{"", "   "} {
+		if got := k.BlindIndex(value); got != "" {
+			t.Errorf("BlindIndex(%q) = %q, want empty", value, got)
+		}
+	}
+}
+
+func TestBlindIndexDependsOnKey(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string]string{"k1": testKey1})
+	other, err := NewKeyring(config.EncryptionConfig{
+		ActiveKeyID:   "k1",
+		Keys:          map[string]string{"k1": testKey1},
+		BlindIndexKey: testKey2,
+	}, config.EnvironmentDevelopment)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if k.BlindIndex("079123456789") == other.BlindIndex("079123456789") {
+		t.Error("blind indexes made with different keys are equal")
+	}
+}
+
+func TestNewKeyring(t *testing.T) {
+	devKeys := config.EncryptionConfig{
+		ActiveKeyID:   "k1",
+		Keys:          map[string]string{"k1": "UzLSfjkmDpEc2up5GLNwRU35DqrOu3wubfPYmQYlnfg="},
+		BlindIndexKey: "rNeuHolctvy+jDmmRxvDCAxRfrNX6Wk5ICiWXDeMLnQ=",
+	}
+
+	tests := []struct {
+		name         string
+		cfg          config.EncryptionConfig
+		environment  string
+		envKey       string
+		envKeyID     string
+		envIndexKey  string
+		wantActiveID string
+		wantErr      error // nil with wantFail for errors without a sentinel
+		wantFail     bool
+	}{
+		{
+			name:         "configured keys",
+			cfg:          config.EncryptionConfig{ActiveKeyID: "k1", Keys: map[string]string{"k1": testKey1}, BlindIndexKey: testIndexKey},
+			environment:  config.EnvironmentProduction,
+			wantActiveID: "k1",
+		},
+		{
+			name:         "development keys in development",
+			cfg:          devKeys,
+			environment:  config.EnvironmentDevelopment,
+			wantActiveID: "k1",
+		},
+		{
+			name:        "development keys in production",
+			cfg:         devKeys,
+			environment: config.EnvironmentProduction,
+			wantErr:     ErrDevelopmentKey,
+		},
+		{
+			name:        "development blind index key in production",
+			cfg:         devKeys,
+			environment: config.EnvironmentProduction,
+			envKey:      testKey2,
+			envKeyID:    "prod1",
+			wantErr:     ErrDevelopmentKey,
+		},
+		{
+			name:         "environment keys in production",
+			cfg:          devKeys,
+			environment:  config.EnvironmentProduction,
+			envKey:       testKey2,
+			envKeyID:     "prod1",
+			envIndexKey:  testIndexKey,
+			wantActiveID: "prod1",
+		},
+		{
+			name:        "environment key without an ID",
+			cfg:         devKeys,
+			environment: config.EnvironmentDevelopment,
+			envKey:      testKey2,
+			wantFail:    true,
+		},
+		{
+			name:        "environment key reusing a configured ID",
+			cfg:         devKeys,
+			environment: config.EnvironmentDevelopment,
+			envKey:      testKey2,
+			envKeyID:    "k1",
+			wantFail:    true,
+		},
+		{
+			name:        "unknown active key",
+			cfg:         config.EncryptionConfig{ActiveKeyID: "k2", Keys: map[string]string{"k1": testKey1}, BlindIndexKey: testIndexKey},
+			environment: config.EnvironmentDevelopment,
+			wantErr:     ErrUnknownKey,
+		},
+		{
+			name:        "short key",
+			cfg:         config.EncryptionConfig{ActiveKeyID: "k1", Keys: map[string]string{"k1": "c2hvcnQ="}, BlindIndexKey: testIndexKey},
+			environment: config.EnvironmentDevelopment,
+			wantErr:     ErrInvalidKeyMaterial,
+		},
+		{
+			name:        "key ID with a colon",
+			cfg:         config.EncryptionConfig{ActiveKeyID: "k:1", Keys: map[string]string{"k:1": testKey1}, BlindIndexKey: testIndexKey},
+			environment: config.EnvironmentDevelopment,
+			wantFail:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PII_ENCRYPTION_KEY", tt.envKey)
+			t.Setenv("PII_ENCRYPTION_KEY_ID", tt.envKeyID)
+			t.Setenv("PII_BLIND_INDEX_KEY", tt.envIndexKey)
+
+			k, err := NewKeyring(tt.cfg, tt.environment)
+			if tt.wantErr != nil || tt.wantFail {
+				if err == nil {
+					t.Fatal("NewKeyring succeeded, want an error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("NewKeyring error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewKeyring: %v", err)
+			}
+			if k.ActiveKeyID() != tt.wantActiveID {
+				t.Errorf("ActiveKeyID = %q, want %q", k.ActiveKeyID(), tt.wantActiveID)
+			}
+		})
+	}
+}
+
+func TestEnvironmentKeyKeepsConfiguredKeys(t *testing.T) {
+	configured := newTestKeyring(t, "k1", map[string]string{"k1": testKey1})
+	written, err := configured.Encrypt("079123456789", "tickets.id_card")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	t.Setenv("PII_ENCRYPTION_KEY", testKey2)
+	t.Setenv("PII_ENCRYPTION_KEY_ID", "env1")
+	k, err := NewKeyring(config.EncryptionConfig{
+		ActiveKeyID:   "k1",
+		Keys:          map[string]string{"k1": testKey1},
+		BlindIndexKey: testIndexKey,
+	}, config.EnvironmentDevelopment)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+
+	got, err := k.Decrypt(written, "tickets.id_card")
+	if err != nil {
+		t.Fatalf("Decrypt of a value written with the configured key: %v", err)
+	}
+	if got != "079123456789" {
+		t.Errorf("Decrypt = %q, want %q", got, "079123456789")
+	}
+	rewritten, err := k.Encrypt("079123456789", "tickets.id_card")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(rewritten, "enc:env1:") {
+		t.Errorf("Encrypt = %q, want it written with the environment key env1", rewritten)
+	}
+}
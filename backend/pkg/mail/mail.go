@@ -0,0 +1,148 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aprilboiz/flight-management/pkg/config"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidAddress = errors.New("invalid email address")
+
+// Message is a plain-text email to one recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages.
+type Mailer interface {
+	Send(message Message) error
+}
+
+// NewMailer returns the configured transport. SMTP_PASSWORD replaces the configured SMTP
+// password.
+func NewMailer(cfg config.MailConfig) (Mailer, error) {
+	if _, err := netmail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("mail from address %q: %w", cfg.From, err)
+	}
+	switch cfg.Transport {
+	case config.MailTransportSMTP:
+		if cfg.SMTP.Host == "" {
+			return nil, errors.New("the smtp mail transport requires a host")
+		}
+		smtpCfg := cfg.SMTP
+		if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+			smtpCfg.Password = password
+		}
+		return &smtpMailer{from: cfg.From, cfg: smtpCfg}, nil
+	case config.MailTransportFile, "":
+		directory := cfg.Directory
+		if directory == "" {
+			directory = filepath.Join("data", "mail")
+		}
+		return &fileMailer{from: cfg.From, directory: directory}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail transport %q", cfg.Transport)
+	}
+}
+
+// smtpMailer sends messages through an SMTP relay, with STARTTLS when the relay offers it.
+type smtpMailer struct {
+	from string
+	cfg  config.SMTPConfig
+}
+
+func (m *smtpMailer) Send(message Message) error {
+	from, err := netmail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+	to, err := netmail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidAddress, message.To)
+	}
+	data, err := compose(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	return smtp.SendMail(addr, auth, from.Address, []string{to.Address}, data)
+}
+
+// fileMailer is the local stand-in for a mail server: it writes each message to its own .eml
+// file, which mail clients can open.
+type fileMailer struct {
+	from      string
+	directory string
+}
+
+func (m *fileMailer) Send(message Message) error {
+	data, err := compose(m.from, message)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.directory, 0o750); err != nil {
+		return err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	path := filepath.Join(m.directory, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	zap.L().Info("Mail written to file instead of being sent",
+		zap.String("path", path), zap.String("subject", message.Subject))
+	return nil
+}
+
+// compose renders a message with its headers. Addresses are parsed and the subject encoded, so
+// neither can inject headers.
+func compose(from string, message Message) ([]byte, error) {
+	sender, err := netmail.ParseAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := netmail.ParseAddress(message.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, message.To)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sender.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", recipient.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	body := strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n")
+	buf.WriteString(body)
+	if !strings.HasSuffix(body, "\r\n") {
+		buf.WriteString("\r\n")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,28 @@
+// Package textfold normalizes text for accent- and case-insensitive matching.
+package textfold
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// letterFolds maps letters that carry no combining mark after decomposition, such as the
+// Vietnamese đ, to their base letter.
+var letterFolds = strings.NewReplacer("đ", "d", "Đ", "d", "ø", "o", "Ø", "o", "ł", "l", "Ł", "l", "ß", "ss")
+
+// Fold lowercases s, strips its diacritics and reduces every run of characters other than
+// letters and digits to a single space, so "Đà Nẵng" and "da-nang" both become "da nang".
+func Fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, letterFolds.Replace(s))
+	if err != nil {
+		folded = s
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,33 @@
+package textfold
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Đà Nẵng", "da nang"},
+		{"da-nang", "da nang"},
+		{"ĐÀ NẴNG", "da nang"},
+		{"Hồ Chí Minh", "ho chi minh"},
+		{"Thành phố Hồ Chí Minh", "thanh pho ho chi minh"},
+		{"Buôn Ma Thuột", "buon ma thuot"},
+		{"  Phú   Quốc (PQC) ", "phu quoc pqc"},
+		{"São Paulo–Guarulhos", "sao paulo guarulhos"},
+		{"Zürich", "zurich"},
+		{"København", "kobenhavn"},
+		{"Łódź", "lodz"},
+		{"Düsseldorf Straße", "dusseldorf strasse"},
+		{"Terminal 2", "terminal 2"},
+		{"", ""},
+		{" - ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Fold(tt.in); got != tt.want {
+				t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
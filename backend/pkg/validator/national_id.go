@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// NationalIDRule checks the format of a national identity document number issued by a
+// single country. Rules are registered per country with RegisterNationalIDRule.
+type NationalIDRule interface {
+	Validate(number string) error
+}
+
+// NationalIDRuleFunc adapts a plain function to the NationalIDRule interface.
+type NationalIDRuleFunc func(number string) error
+
+func (f NationalIDRuleFunc) Validate(number string) error {
+	return f(number)
+}
+
+// PatternRule accepts numbers matching any of its patterns.
+type PatternRule struct {
+	Description string
+	Patterns    []*regexp.Regexp
+}
+
+func (r PatternRule) Validate(number string) error {
+	for _, pattern := range r.Patterns {
+		if pattern.MatchString(number) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Must be %s", r.Description)
+}
+
+var (
+	nationalIDMu    sync.RWMutex
+	nationalIDRules = make(map[string]NationalIDRule)
+)
+
+func init() {
+	RegisterNationalIDRule(PatternRule{
+		Description: "a 9-digit ID card or 12-digit citizen identity card number",
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`^\d{9}$`),
+			regexp.MustCompile(`^\d{12}$`),
+		},
+	}, "VN", "Vietnam")
+}
+
+// RegisterNationalIDRule installs or replaces the rule for a country, registered under its
+// ISO 3166-1 alpha-2 code and optionally the names it goes by. Countries are matched
+// case-insensitively against airport country codes and document issuing countries.
+func RegisterNationalIDRule(rule NationalIDRule, countries ...string) {
+	nationalIDMu.Lock()
+	defer nationalIDMu.Unlock()
+	for _, country := range countries {
+		nationalIDRules[normalizeCountry(country)] = rule
+	}
+}
+
+// ValidateNationalID checks a number against the rule of the given country, an ISO code or
+// a registered name. Countries without a registered rule accept any non-empty number.
+func ValidateNationalID(country, number string) error {
+	if strings.TrimSpace(number) == "" {
+		return errors.New("This field is required")
+	}
+
+	nationalIDMu.RLock()
+	rule, ok := nationalIDRules[normalizeCountry(country)]
+	nationalIDMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return rule.Validate(number)
+}
+
+func normalizeCountry(country string) string {
+	return strings.ToLower(strings.TrimSpace(country))
+}
@@ -2,6 +2,8 @@ package validator
 
 import (
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -28,11 +30,28 @@ func (v *Validator) Validate(i any) error {
 	return v.validator.Struct(i)
 }
 
+// FieldErrors collects validation failures found outside struct tags, keyed by the JSON
+// field name, so they are reported in the same shape as tag validation failures.
+type FieldErrors map[string]string
+
+func (f FieldErrors) Error() string {
+	fields := make([]string, 0, len(f))
+	for field, message := range f {
+		fields = append(fields, field+": "+message)
+	}
+	sort.Strings(fields)
+	return "validation failed: " + strings.Join(fields, "; ")
+}
+
 func ValidationErrors(err error) map[string]string {
 	if err == nil {
 		return nil
 	}
 
+	if fieldErrors, ok := err.(FieldErrors); ok {
+		return fieldErrors
+	}
+
 	errors := make(map[string]string)
 
 	validationErrors, ok := err.(validator.ValidationErrors)